@@ -0,0 +1,31 @@
+package requests
+
+type CountryAdminCreate struct {
+	Code string `json:"code" validate:"required,iso3166_1_alpha2"`
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+type CountryAdminUpdate struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+type CustomerAddressCreateReq struct {
+	RecipientName string  `json:"recipient_name" validate:"required,min=1,max=100"`
+	CountryCode   string  `json:"country_code" validate:"required,len=2"`
+	Region        *string `json:"region,omitempty" validate:"omitempty,max=100"`
+	City          string  `json:"city" validate:"required,min=1,max=100"`
+	Street        string  `json:"street" validate:"required,min=1,max=255"`
+	PostalCode    *string `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Phone         string  `json:"phone" validate:"required,e164"`
+	IsDefault     bool    `json:"is_default"`
+}
+
+type CustomerAddressUpdateReq struct {
+	RecipientName string  `json:"recipient_name" validate:"required,min=1,max=100"`
+	CountryCode   string  `json:"country_code" validate:"required,len=2"`
+	Region        *string `json:"region,omitempty" validate:"omitempty,max=100"`
+	City          string  `json:"city" validate:"required,min=1,max=100"`
+	Street        string  `json:"street" validate:"required,min=1,max=255"`
+	PostalCode    *string `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Phone         string  `json:"phone" validate:"required,e164"`
+}
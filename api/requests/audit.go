@@ -0,0 +1,19 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+)
+
+type AuditLogFilters struct {
+	ActorID       *uuid.UUID `json:"actor_id,omitempty" validate:"omitempty,uuid"`
+	Action        *string    `json:"action,omitempty" validate:"omitempty,max=50"`
+	TableName     *string    `json:"table_name,omitempty" validate:"omitempty,max=63"`
+	EntityID      *uuid.UUID `json:"entity_id,omitempty" validate:"omitempty,uuid"`
+	RequestID     *string    `json:"request_id,omitempty" validate:"omitempty,max=100"`
+	CreatedAtFrom *time.Time `json:"created_at_from,omitempty"`
+	CreatedAtUpTo *time.Time `json:"created_at_up_to,omitempty" validate:"omitempty,gtfield=CreatedAtFrom"`
+	filters.PaginationFilter
+}
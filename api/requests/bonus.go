@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+	"github.com/shopspring/decimal"
+)
+
+type BonusHistoryFilters struct {
+	filters.PaginationFilter
+}
+
+// BonusRedeemReq is sent by the checkout once it has created the order of
+// the buyer, so points are only ever spent on an existing order.
+type BonusRedeemReq struct {
+	OrderID uuid.UUID       `json:"order_id" validate:"required"`
+	BuyerID uuid.UUID       `json:"buyer_id" validate:"required"`
+	Points  decimal.Decimal `json:"points" validate:"decimalgtzero"`
+}
+
+// BonusAdjustReq is a manual correction by support. At least one of
+// PointsDelta and DiscountPercent must be set.
+type BonusAdjustReq struct {
+	PointsDelta     *decimal.Decimal `json:"points_delta,omitempty" validate:"required_without=DiscountPercent,omitempty,decimalnonzero"`
+	DiscountPercent *decimal.Decimal `json:"discount_percent,omitempty" validate:"required_without=PointsDelta,omitempty,decimalpercent"`
+	Note            string           `json:"note" validate:"required,min=3,max=500"`
+}
@@ -10,29 +10,50 @@ type CategoriesAdminFilters struct {
 	Slugs     []string    `json:"slugs" validate:"omitempty,dive,max=50,slug"`
 	ParentIDs []uuid.UUID `json:"parent_ids" validate:"omitempty,dive,uuid"`
 	filters.SearchFilter
+	filters.FieldFilter
 	filters.CreatedUpdatedAtFilter
 	filters.CreatedUpdatedByFilter
 	filters.SortListFilter
 	filters.PaginationFilter
 }
 
+// CategorySorts are the sort keys accepted when listing categories.
+var CategorySorts = filters.SortRegistry{
+	"id":         "id",
+	"name":       "name",
+	"slug":       "slug",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// CategoryFields are the fields accepted in filter[field][op] when listing
+// categories.
+var CategoryFields = filters.FieldSchema{
+	"id":            {Column: "id", Type: filters.UUIDField},
+	"parent_id":     {Column: "parent_id", Type: filters.UUIDField, Nullable: true},
+	"name":          {Column: "name", Type: filters.StringField},
+	"slug":          {Column: "slug", Type: filters.StringField},
+	"description":   {Column: "description", Type: filters.StringField, Nullable: true},
+	"created_at":    {Column: "created_at", Type: filters.TimeField},
+	"updated_at":    {Column: "updated_at", Type: filters.TimeField},
+	"created_by_id": {Column: "created_by_id", Type: filters.UUIDField},
+	"updated_by_id": {Column: "updated_by_id", Type: filters.UUIDField},
+}
+
 type CategoryAdminCreate struct {
 	ParentID    *uuid.UUID `json:"parent_id,omitempty" validate:"omitempty,uuid"`
 	Name        string     `json:"name" validate:"required,min=3,max=50"`
 	Slug        string     `json:"slug" validate:"required,slug"`
 	Description *string    `json:"description,omitempty" validate:"omitempty,max=500"`
 	ImageUrl    string     `json:"image_url" validate:"required,url"`
-	CreatedByID uuid.UUID  `json:"created_by_id" validate:"required,uuid"`
-	UpdatedByID uuid.UUID  `json:"updated_by_id" validate:"required,uuid"`
 }
 
 type CategoryAdminUpdate struct {
-	ParentID    *uuid.UUID `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	ParentID    *uuid.UUID `json:"parent_id" validate:"omitempty,uuid"`
 	Name        string     `json:"name" validate:"required,min=3,max=50"`
 	Slug        string     `json:"slug" validate:"required,slug"`
-	Description *string    `json:"description,omitempty" validate:"omitempty,max=500"`
+	Description *string    `json:"description" validate:"omitempty,max=500"`
 	ImageUrl    string     `json:"image_url" validate:"required,url"`
-	UpdatedByID uuid.UUID  `json:"updated_by_id" validate:"required,uuid"`
 }
 
 type CategoryAdminPartialUpdate struct {
@@ -41,5 +62,4 @@ type CategoryAdminPartialUpdate struct {
 	Slug        *string    `json:"slug,omitempty" validate:"omitempty,slug"`
 	Description *string    `json:"description,omitempty" validate:"omitempty,max=500"`
 	ImageUrl    *string    `json:"image_url,omitempty" validate:"omitempty,url"`
-	UpdatedByID uuid.UUID  `json:"updated_by_id" validate:"required,uuid"`
 }
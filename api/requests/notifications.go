@@ -0,0 +1,13 @@
+package requests
+
+type NotificationPreferencesUpdate struct {
+	OrderUpdates  *bool `json:"order_updates" validate:"required"`
+	Promotions    *bool `json:"promotions" validate:"required"`
+	ReviewReplies *bool `json:"review_replies" validate:"required"`
+}
+
+type NotificationPreferencesPartialUpdate struct {
+	OrderUpdates  *bool `json:"order_updates"`
+	Promotions    *bool `json:"promotions"`
+	ReviewReplies *bool `json:"review_replies"`
+}
@@ -0,0 +1,5 @@
+package requests
+
+type ErasureReq struct {
+	Password string `json:"password" validate:"required,max=72"`
+}
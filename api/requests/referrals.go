@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+)
+
+type ReferralLeaderboardFilters struct {
+	filters.PaginationFilter
+}
+
+type ProductReferralCreateReq struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+}
+
+type PaidOrderLineReq struct {
+	ProductID    uuid.UUID `json:"product_id" validate:"required"`
+	Quantity     int       `json:"quantity" validate:"required,gte=1"`
+	ReferralCode *string   `json:"referral_code,omitempty" validate:"omitempty,alphanum,max=50"`
+}
+
+// PaidOrderReq reports a paid order for purchase attribution.
+type PaidOrderReq struct {
+	OrderID uuid.UUID          `json:"order_id" validate:"required"`
+	BuyerID uuid.UUID          `json:"buyer_id" validate:"required"`
+	Lines   []PaidOrderLineReq `json:"lines" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,19 @@
+package requests
+
+import "github.com/google/uuid"
+
+type RoleAdminCreate struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=500"`
+	Permissions []string `json:"permissions" validate:"required,dive,required,max=100"`
+}
+
+type RoleAdminUpdate struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=500"`
+	Permissions []string `json:"permissions" validate:"required,dive,required,max=100"`
+}
+
+type UserRoleAssign struct {
+	RoleID uuid.UUID `json:"role_id" validate:"required"`
+}
@@ -0,0 +1,31 @@
+package requests
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+)
+
+// Kinds of catalog entities the search covers.
+const (
+	SearchKindProduct  = "product"
+	SearchKindCategory = "category"
+	SearchKindBrand    = "brand"
+)
+
+type CatalogSearchFilters struct {
+	Query string   `json:"q" validate:"required,min=2,max=100"`
+	Kinds []string `json:"kinds" validate:"omitempty,dive,oneof=product category brand"`
+	filters.PaginationFilter
+}
+
+type SearchSuggestFilters struct {
+	Query string `json:"q" validate:"required,max=50"`
+	Limit *int   `json:"limit,omitempty" validate:"omitempty,gte=1,lte=20"`
+}
+
+// SearchClickReq reports that a visitor opened a search result or a
+// suggestion, which makes it rank higher in later suggestions.
+type SearchClickReq struct {
+	Kind string    `json:"kind" validate:"required,oneof=product category brand"`
+	ID   uuid.UUID `json:"id" validate:"required"`
+}
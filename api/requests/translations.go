@@ -11,10 +11,39 @@ type TranslationsAdminFilters struct {
 	FieldNames    []string    `json:"field_names" validate:"omitempty,dive,max=255"`
 	EntityIDs     []uuid.UUID `json:"entity_ids" validate:"omitempty,dive,uuid"`
 	filters.SearchFilter
+	filters.FieldFilter
 	filters.CreatedUpdatedAtFilter
 	filters.CreatedUpdatedByFilter
 	filters.SortListFilter
 	filters.PaginationFilter
+	filters.CursorFilter
+}
+
+// TranslationSorts are the sort keys accepted when listing translations.
+var TranslationSorts = filters.SortRegistry{
+	"id":            "id",
+	"language_code": "language_code",
+	"entity_id":     "entity_id",
+	"table_name":    "table_name",
+	"field_name":    "field_name",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+// TranslationFields are the fields accepted in filter[field][op] when
+// listing translations.
+var TranslationFields = filters.FieldSchema{
+	"id":                    {Column: "id", Type: filters.UUIDField},
+	"language_code":         {Column: "language_code", Type: filters.StringField},
+	"entity_id":             {Column: "entity_id", Type: filters.UUIDField},
+	"table_name":            {Column: "table_name", Type: filters.StringField},
+	"field_name":            {Column: "field_name", Type: filters.StringField},
+	"translated_field_name": {Column: "translated_field_name", Type: filters.StringField},
+	"translated_value":      {Column: "translated_value", Type: filters.StringField},
+	"created_at":            {Column: "created_at", Type: filters.TimeField},
+	"updated_at":            {Column: "updated_at", Type: filters.TimeField},
+	"created_by_id":         {Column: "created_by_id", Type: filters.UUIDField},
+	"updated_by_id":         {Column: "updated_by_id", Type: filters.UUIDField},
 }
 
 type TranslationAdminCreate struct {
@@ -24,8 +53,6 @@ type TranslationAdminCreate struct {
 	FieldName           string    `json:"field_name" validate:"min=1,max=50"`
 	TranslatedFieldName string    `json:"translated_field_name" validate:"min=1,max=50"`
 	TranslatedValue     string    `json:"translated_value" validate:"min=1"`
-	CreatedByID         uuid.UUID `json:"created_by_id" validate:"required,uuid"`
-	UpdatedByID         uuid.UUID `json:"updated_by_id" validate:"required,uuid"`
 }
 
 type TranslationAdminUpdate struct {
@@ -35,7 +62,6 @@ type TranslationAdminUpdate struct {
 	FieldName           string    `json:"field_name" validate:"min=1,max=50"`
 	TranslatedFieldName string    `json:"translated_field_name" validate:"min=1,max=50"`
 	TranslatedValue     string    `json:"translated_value" validate:"min=1"`
-	UpdatedByID         uuid.UUID `json:"updated_by_id" validate:"required,uuid"`
 }
 
 type TranslationAdminPartialUpdate struct {
@@ -45,5 +71,4 @@ type TranslationAdminPartialUpdate struct {
 	FieldName           *string    `json:"field_name,omitempty" validate:"omitempty,min=1,max=50"`
 	TranslatedFieldName *string    `json:"translated_field_name" validate:"omitempty,min=1,max=50"`
 	TranslatedValue     *string    `json:"translated_value,omitempty" validate:"omitempty,min=1"`
-	UpdatedByID         uuid.UUID  `json:"updated_by_id" validate:"required,uuid"`
 }
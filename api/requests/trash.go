@@ -0,0 +1,7 @@
+package requests
+
+import "github.com/kcharymyrat/e-commerce/internal/filters"
+
+type TrashAdminFilters struct {
+	filters.PaginationFilter
+}
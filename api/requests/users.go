@@ -32,68 +32,113 @@ type UsersAdminFilters struct {
 	IsAdmin                 *bool            `json:"is_admin,omitempty" validate:"omitempty"`
 	IsSuperuser             *bool            `json:"is_superuser,omitempty" validate:"omitempty"`
 	filters.SearchFilter
+	filters.FieldFilter
 	filters.CreatedUpdatedAtFilter
 	filters.CreatedUpdatedByFilter
 	filters.SortListFilter
 	filters.PaginationFilter
+	filters.CursorFilter
+}
+
+// UserSorts are the sort keys accepted when listing users. The short
+// aliases match the names of the corresponding range filters.
+var UserSorts = filters.SortRegistry{
+	"id":            "id",
+	"phone":         "phone",
+	"email":         "email",
+	"first_name":    "first_name",
+	"last_name":     "last_name",
+	"is_active":     "is_active",
+	"is_banned":     "is_banned",
+	"is_trusted":    "is_trusted",
+	"is_invited":    "(invited_by_id IS NOT NULL OR inv_ref_id IS NOT NULL OR inv_prod_ref_id IS NOT NULL)",
+	"ref_signups":   "ref_signups",
+	"p_ref_signups": "prod_ref_signups",
+	"p_ref_bought":  "prod_ref_bought",
+	"whole_ddp":     "_dynamic_discount_percent",
+	"ddp":           "dyn_disc_percent",
+	"bonus":         "bonus_points",
+	"is_staff":      "is_staff",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+// UserFields are the fields accepted in filter[field][op] when listing
+// users. Names follow UserSorts.
+var UserFields = filters.FieldSchema{
+	"id":            {Column: "id", Type: filters.UUIDField},
+	"phone":         {Column: "phone", Type: filters.StringField},
+	"email":         {Column: "email", Type: filters.StringField, Nullable: true},
+	"first_name":    {Column: "first_name", Type: filters.StringField, Nullable: true},
+	"last_name":     {Column: "last_name", Type: filters.StringField, Nullable: true},
+	"is_active":     {Column: "is_active", Type: filters.BoolField},
+	"is_banned":     {Column: "is_banned", Type: filters.BoolField},
+	"is_trusted":    {Column: "is_trusted", Type: filters.BoolField},
+	"is_staff":      {Column: "is_staff", Type: filters.BoolField},
+	"is_admin":      {Column: "is_admin", Type: filters.BoolField},
+	"is_superuser":  {Column: "is_superuser", Type: filters.BoolField},
+	"invited_by_id": {Column: "invited_by_id", Type: filters.UUIDField, Nullable: true},
+	"ref_signups":   {Column: "ref_signups", Type: filters.IntField},
+	"p_ref_signups": {Column: "prod_ref_signups", Type: filters.IntField},
+	"p_ref_bought":  {Column: "prod_ref_bought", Type: filters.IntField},
+	"whole_ddp":     {Column: "_dynamic_discount_percent", Type: filters.DecimalField},
+	"ddp":           {Column: "dyn_disc_percent", Type: filters.DecimalField},
+	"bonus":         {Column: "bonus_points", Type: filters.DecimalField},
+	"created_at":    {Column: "created_at", Type: filters.TimeField},
+	"updated_at":    {Column: "updated_at", Type: filters.TimeField},
+	"created_by_id": {Column: "created_by_id", Type: filters.UUIDField, Nullable: true},
+	"updated_by_id": {Column: "updated_by_id", Type: filters.UUIDField, Nullable: true},
 }
 
 type UserAdminCreate struct {
-	Phone       string     `json:"phone" validate:"required,e164"`
-	Password    string     `json:"password" validate:"required,min=8,max=72,password"`
-	FirstName   *string    `json:"first_name" validate:"omitempty,max=50,alpha"`
-	LastName    *string    `json:"last_name" validate:"omitempty,max=50,alpha"`
-	Patronomic  *string    `json:"patronomic" validate:"omitempty,max=50,alpha"`
-	Email       *string    `json:"email" validate:"omitempty,email"`
-	IsActive    bool       `json:"is_active" validate:"required"`
-	CreatedByID *uuid.UUID `json:"created_by_id,omitempty" validate:"omitempty,uuid"`
+	Phone      string  `json:"phone" validate:"required,e164"`
+	Password   string  `json:"password" validate:"required,min=8,max=72,password"`
+	FirstName  *string `json:"first_name" validate:"omitempty,max=50,alpha"`
+	LastName   *string `json:"last_name" validate:"omitempty,max=50,alpha"`
+	Patronomic *string `json:"patronomic" validate:"omitempty,max=50,alpha"`
+	Email      *string `json:"email" validate:"omitempty,email"`
+	IsActive   bool    `json:"is_active" validate:"required"`
 }
 
 type UserAdminUpdate struct {
-	Phone       string    `json:"phone" validate:"required,e164"`
-	Password    string    `json:"password" validate:"required,min=8,max=72,password"`
-	FirstName   *string   `json:"first_name" validate:"omitempty,max=50,alpha"`
-	LastName    *string   `json:"last_name" validate:"omitempty,max=50,alpha"`
-	Patronomic  *string   `json:"patronomic" validate:"omitempty,max=50,alpha"`
-	Email       *string   `json:"email" validate:"omitempty,email"`
-	IsActive    bool      `json:"is_active" validate:"required"`
-	UpdatedByID uuid.UUID `json:"updated_by_id" validate:"uuid"`
+	Phone      string  `json:"phone" validate:"required,e164"`
+	Password   string  `json:"password" validate:"omitempty,min=8,max=72,password"`
+	FirstName  *string `json:"first_name" validate:"omitempty,max=50,alpha"`
+	LastName   *string `json:"last_name" validate:"omitempty,max=50,alpha"`
+	Patronomic *string `json:"patronomic" validate:"omitempty,max=50,alpha"`
+	Email      *string `json:"email" validate:"omitempty,email"`
+	IsActive   *bool   `json:"is_active" validate:"required"`
 }
 
 type UserAdminPartialUpdate struct {
-	Phone       *string   `json:"phone" validate:"required,e164"`
-	Password    *string   `json:"password" validate:"required,min=8,max=72,password"`
-	FirstName   *string   `json:"first_name" validate:"omitempty,max=50,alpha"`
-	LastName    *string   `json:"last_name" validate:"omitempty,max=50,alpha"`
-	Patronomic  *string   `json:"patronomic" validate:"omitempty,max=50,alpha"`
-	Email       *string   `json:"email" validate:"omitempty,email"`
-	IsActive    *bool     `json:"is_active" validate:"required"`
-	UpdatedByID uuid.UUID `json:"updated_by_id" validate:"uuid"`
+	Phone      *string `json:"phone" validate:"required,e164"`
+	Password   *string `json:"password" validate:"omitempty,min=8,max=72,password"`
+	FirstName  *string `json:"first_name" validate:"omitempty,max=50,alpha"`
+	LastName   *string `json:"last_name" validate:"omitempty,max=50,alpha"`
+	Patronomic *string `json:"patronomic" validate:"omitempty,max=50,alpha"`
+	Email      *string `json:"email" validate:"omitempty,email"`
+	IsActive   *bool   `json:"is_active" validate:"required"`
 }
 
 type UserSelfUpdate struct {
-	Phone       string    `json:"phone" validate:"required,e164"`
-	Password    string    `json:"password" validate:"required,min=8,max=72,password"`
-	FirstName   *string   `json:"first_name" validate:"omitempty,max=50,alpha"`
-	LastName    *string   `json:"last_name" validate:"omitempty,max=50,alpha"`
-	Patronomic  *string   `json:"patronomic" validate:"omitempty,max=50,alpha"`
-	Email       *string   `json:"email" validate:"omitempty,email"`
-	UpdatedByID uuid.UUID `json:"updated_by_id" validate:"uuid"`
+	FirstName  *string `json:"first_name" validate:"omitempty,max=50,alpha"`
+	LastName   *string `json:"last_name" validate:"omitempty,max=50,alpha"`
+	Patronomic *string `json:"patronomic" validate:"omitempty,max=50,alpha"`
+	Email      *string `json:"email" validate:"omitempty,email"`
 }
 
 type UserSelfPartialUpdate struct {
-	Phone       *string   `json:"phone" validate:"required,e164"`
-	Password    *string   `json:"password" validate:"required,min=8,max=72,password"`
-	FirstName   *string   `json:"first_name" validate:"omitempty,max=50,alpha"`
-	LastName    *string   `json:"last_name" validate:"omitempty,max=50,alpha"`
-	Patronomic  *string   `json:"patronomic" validate:"omitempty,max=50,alpha"`
-	Email       *string   `json:"email" validate:"omitempty,email"`
-	UpdatedByID uuid.UUID `json:"updated_by_id" validate:"uuid"`
+	FirstName  *string `json:"first_name" validate:"omitempty,max=50,alpha"`
+	LastName   *string `json:"last_name" validate:"omitempty,max=50,alpha"`
+	Patronomic *string `json:"patronomic" validate:"omitempty,max=50,alpha"`
+	Email      *string `json:"email" validate:"omitempty,email"`
 }
 
 type UserPasswordRegisterReq struct {
-	Phone    string `json:"phone" validate:"required,e164"`
-	Password string `json:"password" validate:"required,min=8,max=72,password"`
+	Phone               string  `json:"phone" validate:"required,e164"`
+	Password            string  `json:"password" validate:"required,min=8,max=72,password"`
+	ReferralCode        *string `json:"referral_code,omitempty" validate:"omitempty,alphanum,max=50"`
+	ProductReferralCode *string `json:"product_referral_code,omitempty" validate:"omitempty,alphanum,max=50"`
 }
 
 type UserLoginReq struct {
@@ -105,6 +150,66 @@ type UserSMSLoginReq struct {
 	Phone string `json:"phone" validate:"required,e164"`
 }
 
+type UserOTPVerifyReq struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required,numeric,min=4,max=10"`
+}
+
+type PhoneChangeReq struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+type PhoneChangeVerifyReq struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required,numeric,min=4,max=10"`
+}
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=72"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72,password,nefield=CurrentPassword"`
+}
+
+type PasswordResetVerifyReq struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required,numeric,min=4,max=10"`
+}
+
+type PasswordResetReq struct {
+	ResetToken  string `json:"reset_token" validate:"required,max=100"`
+	NewPassword string `json:"new_password" validate:"required,min=8,max=72,password"`
+}
+
 type RenewAccessTokenReq struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
+
+type LoginLockoutClearReq struct {
+	Kind string `json:"kind" validate:"required,oneof=phone ip"`
+	Key  string `json:"key" validate:"required,max=64"`
+}
+
+type MFAEnrollReq struct {
+	MFAToken string `json:"mfa_token" validate:"required,max=100"`
+}
+
+type MFAVerifyReq struct {
+	MFAToken string `json:"mfa_token" validate:"required,max=100"`
+	Code     string `json:"code" validate:"required,max=20"`
+}
+
+type TOTPCodeReq struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type UserModerationReq struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+type UserLevelChangeReq struct {
+	Level  string `json:"level" validate:"required,oneof=staff admin superuser"`
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+type EmailVerifyReq struct {
+	Token string `json:"token" validate:"required,max=1000"`
+}
@@ -0,0 +1,46 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CountryPublicResponse struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type CountryAdminResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedByID uuid.UUID `json:"created_by_id"`
+	UpdatedByID uuid.UUID `json:"updated_by_id"`
+	Version     int       `json:"version"`
+}
+
+type CustomerAddressSelfResponse struct {
+	ID            uuid.UUID `json:"id"`
+	RecipientName string    `json:"recipient_name"`
+	CountryCode   string    `json:"country_code"`
+	CountryName   string    `json:"country_name"`
+	Region        *string   `json:"region,omitempty"`
+	City          string    `json:"city"`
+	Street        string    `json:"street"`
+	PostalCode    *string   `json:"postal_code,omitempty"`
+	Phone         string    `json:"phone"`
+	IsDefault     bool      `json:"is_default"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Version       int       `json:"version"`
+}
+
+type CustomerProfileSelfResponse struct {
+	ID             uuid.UUID                      `json:"id"`
+	UserID         uuid.UUID                      `json:"user_id"`
+	DefaultAddress *CustomerAddressSelfResponse   `json:"default_address"`
+	Addresses      []*CustomerAddressSelfResponse `json:"addresses"`
+}
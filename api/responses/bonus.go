@@ -0,0 +1,13 @@
+package responses
+
+import "github.com/shopspring/decimal"
+
+type BonusBalanceResponse struct {
+	BonusPoints     decimal.Decimal `json:"bonus_points"`
+	DiscountPercent decimal.Decimal `json:"dyn_disc_percent"`
+}
+
+type BonusRedemptionResponse struct {
+	RedeemedPoints decimal.Decimal `json:"redeemed_points"`
+	Amount         decimal.Decimal `json:"amount"`
+}
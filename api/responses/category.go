@@ -28,6 +28,7 @@ type CategoryAdminResponse struct {
 	CreatedByID uuid.UUID  `json:"created_by_id"`
 	UpdatedByID uuid.UUID  `json:"updated_by_id"`
 	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CategoryPublicResponse struct {
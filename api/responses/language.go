@@ -7,14 +7,15 @@ import (
 )
 
 type LanguageAdminResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Code        string    `json:"code"`
-	Name        string    `json:"name"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID uuid.UUID `json:"created_by_id"`
-	UpdatedByID uuid.UUID `json:"updated_by_id"`
-	Version     int       `json:"version"`
+	ID          uuid.UUID  `json:"id"`
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CreatedByID uuid.UUID  `json:"created_by_id"`
+	UpdatedByID uuid.UUID  `json:"updated_by_id"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type LanguagePublicResponse struct {
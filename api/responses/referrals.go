@@ -0,0 +1,30 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReferralSelfResponse struct {
+	Code           string `json:"code"`
+	ShareURL       string `json:"share_url"`
+	RefSignups     int    `json:"ref_signups"`
+	TotalReferrals int    `json:"total_referrals"`
+}
+
+type ProductReferralSelfResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	Code      string    `json:"code"`
+	ShareURL  string    `json:"share_url"`
+	Clicks    int       `json:"clicks"`
+	Signups   int       `json:"signups"`
+	Purchases int       `json:"purchases"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ProductReferralClickResponse struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Code      string    `json:"code"`
+}
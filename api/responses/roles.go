@@ -0,0 +1,20 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RoleAdminResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	IsSystem    bool       `json:"is_system"`
+	Permissions []string   `json:"permissions"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CreatedByID *uuid.UUID `json:"created_by_id,omitempty"`
+	UpdatedByID *uuid.UUID `json:"updated_by_id,omitempty"`
+	Version     int        `json:"version"`
+}
@@ -15,6 +15,8 @@ type UserSelfResponse struct {
 	Patronomic          *string         `json:"patronomic" validate:"omitempty,max=50,alpha"`
 	DOB                 *time.Time      `json:"dob" validate:"omitempty,gte=1900-01-01"`
 	Email               *string         `json:"email" validate:"omitempty,email"`
+	EmailVerifiedAt     *time.Time      `json:"email_verified_at"`
+	PhoneVerifiedAt     *time.Time      `json:"phone_verified_at"`
 	IsActive            bool            `json:"is_active" validate:"required"`
 	IsBanned            bool            `json:"is_banned" validate:"required"`
 	IsTrusted           bool            `json:"is_trusted" validate:"required"`
@@ -40,6 +42,8 @@ type UserSelfResponse struct {
 
 type UserAdminResponse struct {
 	UserSelfResponse
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ErasedAt  *time.Time `json:"erased_at,omitempty"`
 }
 
 type UserPublicResponse struct {
@@ -78,3 +82,29 @@ type RenewAccessTokenResponse struct {
 	AccessToken          string    `json:"access_token" validate:"required"`
 	AccessTokenExpiresAt time.Time `json:"access_token_expires_at" validate:"required"`
 }
+
+type OTPSentResponse struct {
+	Phone     string    `json:"phone" validate:"required,e164"`
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+}
+
+type PasswordResetTokenResponse struct {
+	ResetToken string    `json:"reset_token" validate:"required"`
+	ExpiresAt  time.Time `json:"expires_at" validate:"required"`
+}
+
+type MFAChallengeResponse struct {
+	MFARequired        bool      `json:"mfa_required"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	MFAToken           string    `json:"mfa_token" validate:"required"`
+	ExpiresAt          time.Time `json:"expires_at" validate:"required"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret" validate:"required"`
+	ProvisioningURI string `json:"provisioning_uri" validate:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" validate:"required"`
+}
@@ -13,13 +13,17 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kcharymyrat/e-commerce/internal/app"
 	"github.com/kcharymyrat/e-commerce/internal/config"
+	"github.com/kcharymyrat/e-commerce/internal/mail"
 	"github.com/kcharymyrat/e-commerce/internal/repository"
 	"github.com/kcharymyrat/e-commerce/internal/server"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/sms"
 	"github.com/kcharymyrat/e-commerce/internal/validation"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/viper"
 	"golang.org/x/text/language"
 
@@ -49,6 +53,7 @@ func main() {
 	cfg := config.Config{}
 
 	loadEnv(&logger)
+	setDefaults()
 
 	port := viper.GetInt("APP_PORT")
 	env := viper.GetString("ENV")
@@ -64,6 +69,58 @@ func main() {
 	redisAddr := viper.GetString("REDIS_ADDR")
 	redisPort := viper.GetInt("REDIS_PORT")
 
+	otpLength := viper.GetInt("OTP_LENGTH")
+	otpTTLSeconds := viper.GetInt("OTP_TTL_SECONDS")
+	otpMaxAttempts := viper.GetInt("OTP_MAX_ATTEMPTS")
+	otpResendCooldownSeconds := viper.GetInt("OTP_RESEND_COOLDOWN_SECONDS")
+
+	smsDriver := viper.GetString("SMS_DRIVER")
+	smsOutboxPath := viper.GetString("SMS_OUTBOX_PATH")
+
+	mailDriver := viper.GetString("MAIL_DRIVER")
+	mailFrom := viper.GetString("MAIL_FROM")
+	mailDropDir := viper.GetString("MAIL_DROP_DIR")
+	smtpHost := viper.GetString("SMTP_HOST")
+	smtpPort := viper.GetInt("SMTP_PORT")
+	smtpUser := viper.GetString("SMTP_USER")
+	smtpPass := viper.GetString("SMTP_PASS")
+
+	emailVerificationTokenTTLHours := viper.GetInt("EMAIL_VERIFICATION_TOKEN_TTL_HOURS")
+	emailVerificationResendCooldownSeconds := viper.GetInt("EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS")
+	emailVerificationLinkBaseURL := viper.GetString("EMAIL_VERIFICATION_LINK_BASE_URL")
+
+	passwordResetTokenTTLSeconds := viper.GetInt("PASSWORD_RESET_TOKEN_TTL_SECONDS")
+
+	mfaIssuer := viper.GetString("MFA_ISSUER")
+	mfaChallengeTTLSeconds := viper.GetInt("MFA_CHALLENGE_TTL_SECONDS")
+
+	referralCodeLength := viper.GetInt("REFERRAL_CODE_LENGTH")
+	referralShareBaseURL := viper.GetString("REFERRAL_SHARE_BASE_URL")
+	referralProductShareBaseURL := viper.GetString("REFERRAL_PRODUCT_SHARE_BASE_URL")
+	referralClicksPerMinute := viper.GetInt("REFERRAL_CLICKS_PER_MINUTE")
+
+	bonusPointsPerReferralSignup := viper.GetFloat64("BONUS_POINTS_PER_REFERRAL_SIGNUP")
+	bonusPointsPerProductPurchase := viper.GetFloat64("BONUS_POINTS_PER_PRODUCT_PURCHASE")
+	bonusDiscountPerReferralSignup := viper.GetFloat64("BONUS_DISCOUNT_PER_REFERRAL_SIGNUP")
+	bonusDiscountPerProductSignup := viper.GetFloat64("BONUS_DISCOUNT_PER_PRODUCT_SIGNUP")
+	bonusDiscountPerProductPurchase := viper.GetFloat64("BONUS_DISCOUNT_PER_PRODUCT_PURCHASE")
+	bonusPointValue := viper.GetFloat64("BONUS_POINT_VALUE")
+
+	loginMaxPhoneFailures := viper.GetInt("LOGIN_MAX_PHONE_FAILURES")
+	loginMaxIPFailures := viper.GetInt("LOGIN_MAX_IP_FAILURES")
+	loginFailureWindowSeconds := viper.GetInt("LOGIN_FAILURE_WINDOW_SECONDS")
+	loginBaseLockoutSeconds := viper.GetInt("LOGIN_BASE_LOCKOUT_SECONDS")
+	loginMaxLockoutSeconds := viper.GetInt("LOGIN_MAX_LOCKOUT_SECONDS")
+
+	trashRetentionDays := viper.GetInt("TRASH_RETENTION_DAYS")
+	trashPurgeIntervalMinutes := viper.GetInt("TRASH_PURGE_INTERVAL_MINUTES")
+	erasureCoolingOffDays := viper.GetInt("ERASURE_COOLING_OFF_DAYS")
+	erasureCheckIntervalMinutes := viper.GetInt("ERASURE_CHECK_INTERVAL_MINUTES")
+	searchSuggestRebuildIntervalMinutes := viper.GetInt("SEARCH_SUGGEST_REBUILD_INTERVAL_MINUTES")
+	searchClicksPerMinute := viper.GetInt("SEARCH_CLICKS_PER_MINUTE")
+	cacheTTLSeconds := viper.GetInt("CACHE_TTL_SECONDS")
+	requireIfMatch := viper.GetBool("REQUIRE_IF_MATCH")
+
 	flag.IntVar(&cfg.Port, "port", port, "API server port")
 	flag.StringVar(&cfg.Env, "env", env, "Environment (development|staging|production)")
 	flag.StringVar(&cfg.DB.DSN, "db-dsn", dbDsn, "PostgreSQL DSN")
@@ -81,6 +138,58 @@ func main() {
 	}
 	cfg.SecretKey = []byte(secretKey)
 
+	cfg.OTP.Length = otpLength
+	cfg.OTP.TTL = time.Duration(otpTTLSeconds) * time.Second
+	cfg.OTP.MaxAttempts = otpMaxAttempts
+	cfg.OTP.ResendCooldown = time.Duration(otpResendCooldownSeconds) * time.Second
+
+	cfg.PasswordReset.TokenTTL = time.Duration(passwordResetTokenTTLSeconds) * time.Second
+
+	cfg.Mail.From = mailFrom
+	cfg.Mail.SMTPHost = smtpHost
+	cfg.Mail.SMTPPort = smtpPort
+	cfg.Mail.SMTPUser = smtpUser
+	cfg.Mail.SMTPPass = smtpPass
+
+	cfg.EmailVerification.TokenTTL = time.Duration(emailVerificationTokenTTLHours) * time.Hour
+	cfg.EmailVerification.ResendCooldown = time.Duration(emailVerificationResendCooldownSeconds) * time.Second
+	cfg.EmailVerification.LinkBaseURL = emailVerificationLinkBaseURL
+
+	cfg.MFA.Issuer = mfaIssuer
+	cfg.MFA.ChallengeTTL = time.Duration(mfaChallengeTTLSeconds) * time.Second
+
+	cfg.Referral.CodeLength = referralCodeLength
+	cfg.Referral.ShareBaseURL = referralShareBaseURL
+	cfg.Referral.ProductShareBaseURL = referralProductShareBaseURL
+	cfg.Referral.ClicksPerMinute = referralClicksPerMinute
+
+	cfg.Bonus.PointsPerReferralSignup = decimal.NewFromFloat(bonusPointsPerReferralSignup)
+	cfg.Bonus.PointsPerProductPurchase = decimal.NewFromFloat(bonusPointsPerProductPurchase)
+	cfg.Bonus.DiscountPerReferralSignup = decimal.NewFromFloat(bonusDiscountPerReferralSignup)
+	cfg.Bonus.DiscountPerProductSignup = decimal.NewFromFloat(bonusDiscountPerProductSignup)
+	cfg.Bonus.DiscountPerProductPurchase = decimal.NewFromFloat(bonusDiscountPerProductPurchase)
+	cfg.Bonus.PointValue = decimal.NewFromFloat(bonusPointValue)
+
+	cfg.LoginThrottle.MaxPhoneFailures = loginMaxPhoneFailures
+	cfg.LoginThrottle.MaxIPFailures = loginMaxIPFailures
+	cfg.LoginThrottle.FailureWindow = time.Duration(loginFailureWindowSeconds) * time.Second
+	cfg.LoginThrottle.BaseLockout = time.Duration(loginBaseLockoutSeconds) * time.Second
+	cfg.LoginThrottle.MaxLockout = time.Duration(loginMaxLockoutSeconds) * time.Second
+
+	cfg.Trash.Retention = time.Duration(trashRetentionDays) * 24 * time.Hour
+	cfg.Trash.PurgeInterval = time.Duration(trashPurgeIntervalMinutes) * time.Minute
+	cfg.Erasure.CoolingOff = time.Duration(erasureCoolingOffDays) * 24 * time.Hour
+	cfg.Erasure.CheckInterval = time.Duration(erasureCheckIntervalMinutes) * time.Minute
+	cfg.Search.SuggestRebuildInterval = time.Duration(searchSuggestRebuildIntervalMinutes) * time.Minute
+	cfg.Search.ClicksPerMinute = searchClicksPerMinute
+	cfg.Cache.TTL = time.Duration(cacheTTLSeconds) * time.Second
+	cfg.Concurrency.RequireIfMatch = requireIfMatch
+
+	flag.StringVar(&cfg.SMS.Driver, "sms-driver", smsDriver, "SMS driver (log|file)")
+	flag.StringVar(&cfg.SMS.OutboxPath, "sms-outbox", smsOutboxPath, "File used by the file SMS driver")
+	flag.StringVar(&cfg.Mail.Driver, "mail-driver", mailDriver, "Mail driver (file|smtp)")
+	flag.StringVar(&cfg.Mail.DropDir, "mail-drop-dir", mailDropDir, "Directory used by the file mail driver")
+
 	flag.Parse()
 
 	db, err := openDB(&cfg)
@@ -96,6 +205,23 @@ func main() {
 	limiter := redis_rate.NewLimiter(rdb)
 	log.Info().Str("env", cfg.Env).Msg("redis connection and limiter established")
 
+	smsSender, err := sms.NewSMSSender(cfg.SMS.Driver, cfg.SMS.OutboxPath, &logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create SMS sender")
+	}
+	log.Info().Str("driver", cfg.SMS.Driver).Msg("sms sender configured")
+
+	mailer, err := mail.NewMailer(cfg.Mail.Driver, cfg.Mail.From, cfg.Mail.DropDir, mail.SMTPSettings{
+		Host:     cfg.Mail.SMTPHost,
+		Port:     cfg.Mail.SMTPPort,
+		Username: cfg.Mail.SMTPUser,
+		Password: cfg.Mail.SMTPPass,
+	})
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create mailer")
+	}
+	log.Info().Str("driver", cfg.Mail.Driver).Msg("mailer configured")
+
 	validator := validation.NewValidator()
 	valUniTrans := validation.NewUniversalTranslator()
 	i18nBundle := loadTranslations()
@@ -104,12 +230,14 @@ func main() {
 	app := app.NewApplication(
 		cfg,
 		&logger,
-		repository.NewRepositories(db),
+		repository.NewRepositories(db, rdb),
 		rdb,
 		limiter,
 		validator,
 		valUniTrans,
 		i18nBundle,
+		smsSender,
+		mailer,
 		&wg,
 	)
 
@@ -139,6 +267,10 @@ func main() {
 	validation.RegisterCustomRuTranslations(app, ruTrans)
 	validation.RegisterCustomTkTranslations(app, tkTrans)
 
+	services.StartTrashPurger(app)
+	services.StartErasureWorker(app)
+	services.StartSuggestionsRebuilder(app)
+
 	err = server.Serve(app)
 	if err != nil {
 		app.Logger.Fatal().Stack().Err(err).Msg("Server failed to start")
@@ -158,6 +290,45 @@ func loadEnv(logger *zerolog.Logger) {
 	}
 }
 
+func setDefaults() {
+	viper.SetDefault("OTP_LENGTH", 6)
+	viper.SetDefault("OTP_TTL_SECONDS", 300)
+	viper.SetDefault("OTP_MAX_ATTEMPTS", 5)
+	viper.SetDefault("OTP_RESEND_COOLDOWN_SECONDS", 60)
+	viper.SetDefault("SMS_DRIVER", "log")
+	viper.SetDefault("SMS_OUTBOX_PATH", "tmp/sms_outbox.log")
+	viper.SetDefault("MAIL_DRIVER", "file")
+	viper.SetDefault("MAIL_FROM", "no-reply@localhost")
+	viper.SetDefault("MAIL_DROP_DIR", "tmp/mail")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("EMAIL_VERIFICATION_TOKEN_TTL_HOURS", 24)
+	viper.SetDefault("EMAIL_VERIFICATION_RESEND_COOLDOWN_SECONDS", 60)
+	viper.SetDefault("EMAIL_VERIFICATION_LINK_BASE_URL", "http://localhost:3000/verify-email")
+	viper.SetDefault("PASSWORD_RESET_TOKEN_TTL_SECONDS", 900)
+	viper.SetDefault("MFA_ISSUER", "e-commerce")
+	viper.SetDefault("MFA_CHALLENGE_TTL_SECONDS", 300)
+	viper.SetDefault("REFERRAL_CODE_LENGTH", 8)
+	viper.SetDefault("REFERRAL_SHARE_BASE_URL", "http://localhost:3000/register")
+	viper.SetDefault("REFERRAL_PRODUCT_SHARE_BASE_URL", "http://localhost:3000/products")
+	viper.SetDefault("REFERRAL_CLICKS_PER_MINUTE", 30)
+	viper.SetDefault("BONUS_POINTS_PER_REFERRAL_SIGNUP", 100)
+	viper.SetDefault("BONUS_POINTS_PER_PRODUCT_PURCHASE", 50)
+	viper.SetDefault("BONUS_DISCOUNT_PER_REFERRAL_SIGNUP", 0.5)
+	viper.SetDefault("BONUS_DISCOUNT_PER_PRODUCT_SIGNUP", 0.25)
+	viper.SetDefault("BONUS_DISCOUNT_PER_PRODUCT_PURCHASE", 0.25)
+	viper.SetDefault("BONUS_POINT_VALUE", 0.01)
+	viper.SetDefault("LOGIN_MAX_PHONE_FAILURES", 5)
+	viper.SetDefault("LOGIN_MAX_IP_FAILURES", 20)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW_SECONDS", 3600)
+	viper.SetDefault("LOGIN_BASE_LOCKOUT_SECONDS", 30)
+	viper.SetDefault("LOGIN_MAX_LOCKOUT_SECONDS", 3600)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("TRASH_PURGE_INTERVAL_MINUTES", 60)
+	viper.SetDefault("ERASURE_COOLING_OFF_DAYS", 14)
+	viper.SetDefault("ERASURE_CHECK_INTERVAL_MINUTES", 60)
+	viper.SetDefault("SEARCH_CLICKS_PER_MINUTE", 30)
+}
+
 func openDB(cfg *config.Config) (*pgxpool.Pool, error) {
 	// Parse the connection pool configuration
 	poolConfig, err := pgxpool.ParseConfig(cfg.DB.DSN)
@@ -105,15 +105,6 @@ const docTemplate = `{
                         "name": "slugs",
                         "in": "query"
                     },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "sort_safe_list",
-                        "in": "query"
-                    },
                     {
                         "type": "array",
                         "items": {
@@ -345,6 +336,124 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/search": {
+            "get": {
+                "description": "Full-text search over product, category and brand names and descriptions, including their translations, tolerant of typos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Languages: en, ru, tk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search terms, websearch syntax",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated kinds: product, category, brand",
+                        "name": "kinds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Envelope"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search/suggest": {
+            "get": {
+                "description": "Type-ahead suggestions of products, categories and brands whose name, or a word of it, starts with q, most popular first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Suggest catalog entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Languages: en, ru, tk",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Typed prefix",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of suggestions, 1 to 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Envelope"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/users/{id}": {
             "get": {
                 "description": "Get user by id (uuid)",
@@ -368,7 +477,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "translations is empty for users",
                         "schema": {
                             "$ref": "#/definitions/types.DetailResponse-responses_UserPublicResponse"
                         }
@@ -523,6 +632,10 @@ const docTemplate = `{
                 }
             }
         },
+        "types.Envelope": {
+            "type": "object",
+            "additionalProperties": true
+        },
         "types.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -585,9 +698,16 @@ const docTemplate = `{
                 "last_page": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "description": "NextCursor and PrevCursor are set instead of the page numbers when a\nlist is paged by cursor.",
+                    "type": "string"
+                },
                 "page_size": {
                     "type": "integer"
                 },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "total_records": {
                     "type": "integer"
                 }
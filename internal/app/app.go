@@ -7,7 +7,9 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/go-redis/redis_rate/v10"
 	"github.com/kcharymyrat/e-commerce/internal/config"
+	"github.com/kcharymyrat/e-commerce/internal/mail"
 	"github.com/kcharymyrat/e-commerce/internal/repository"
+	"github.com/kcharymyrat/e-commerce/internal/sms"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
@@ -22,6 +24,8 @@ type Application struct {
 	Validator    *validator.Validate
 	ValUniTrans  *ut.UniversalTranslator
 	I18nBundle   *i18n.Bundle
+	SMSSender    sms.SMSSender
+	Mailer       mail.Mailer
 	Wg           *sync.WaitGroup
 }
 
@@ -34,6 +38,8 @@ func NewApplication(
 	validator *validator.Validate,
 	uniTrans *ut.UniversalTranslator,
 	i18nBundle *i18n.Bundle,
+	smsSender sms.SMSSender,
+	mailer mail.Mailer,
 	wg *sync.WaitGroup,
 ) *Application {
 	return &Application{
@@ -45,6 +51,8 @@ func NewApplication(
 		Validator:    validator,
 		ValUniTrans:  uniTrans,
 		I18nBundle:   i18nBundle,
+		SMSSender:    smsSender,
+		Mailer:       mailer,
 		Wg:           wg,
 	}
 }
@@ -10,6 +10,14 @@ func GeneratePasswordHash(plaintextPassword string) ([]byte, error) {
 	return bcrypt.GenerateFromPassword([]byte(plaintextPassword), 12)
 }
 
+// dummyPasswordHash is compared against when the account does not exist, so
+// that unknown phones take as long to reject as wrong passwords.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-for-timing"), 12)
+
+func CompareWithDummyPasswordHash(plaintextPassword string) {
+	_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(plaintextPassword))
+}
+
 func IsPasswordInputMatching(plaintextPassword string, passwordHash []byte) (bool, error) {
 	err := bcrypt.CompareHashAndPassword(passwordHash, []byte(plaintextPassword))
 	if err != nil {
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+func GenerateOTPCode(length int) (string, error) {
+	var sb strings.Builder
+	sb.Grow(length)
+
+	max := big.NewInt(10)
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+
+	return sb.String(), nil
+}
+
+// HashOTPCode binds the code to the phone and purpose so that a hash leaked
+// from redis can not be replayed for another account or flow.
+func HashOTPCode(code, phone, purpose string, secretKey []byte) string {
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(phone))
+	mac.Write([]byte{0})
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func IsOTPCodeMatching(code, phone, purpose string, codeHash string, secretKey []byte) bool {
+	expected := HashOTPCode(code, phone, purpose, secretKey)
+	return hmac.Equal([]byte(expected), []byte(codeHash))
+}
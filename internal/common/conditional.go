@@ -0,0 +1,92 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+// EntityETag returns a strong entity tag for the representation of an
+// entity at version, together with the translations shown with it. Every
+// update bumps a version, so the tag changes exactly when the
+// representation does.
+func EntityETag(id uuid.UUID, version int, translations []*data.Translation) string {
+	if len(translations) == 0 {
+		return fmt.Sprintf(`"%s-%d"`, id, version)
+	}
+
+	h := sha256.New()
+	for _, tr := range translations {
+		fmt.Fprintf(h, "%s-%d;", tr.ID, tr.Version)
+	}
+	return fmt.Sprintf(`"%s-%d-%s"`, id, version, hex.EncodeToString(h.Sum(nil))[:16])
+}
+
+// WeakETag returns a weak entity tag for body, for representations such as
+// lists that have no version of their own.
+func WeakETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(sum[:])[:32])
+}
+
+// LastModified returns when the entity or any of its translations last
+// changed.
+func LastModified(updatedAt time.Time, translations []*data.Translation) time.Time {
+	lastModified := updatedAt
+	for _, tr := range translations {
+		if tr.UpdatedAt.After(lastModified) {
+			lastModified = tr.UpdatedAt
+		}
+	}
+	return lastModified
+}
+
+// ValidatorHeaders returns the ETag and Last-Modified headers a response
+// passes to WriteJson and friends.
+func ValidatorHeaders(etag string, lastModified time.Time) http.Header {
+	headers := http.Header{}
+	headers.Set("ETag", etag)
+	headers.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	return headers
+}
+
+// VersionETag returns the entity tag admin endpoints give an entity at
+// version, and expect back in If-Match.
+func VersionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// VersionHeaders returns the ETag header of an entity at version.
+func VersionHeaders(version int) http.Header {
+	headers := http.Header{}
+	headers.Set("ETag", VersionETag(version))
+	return headers
+}
+
+// CheckIfMatch evaluates the If-Match header of a write to an entity at
+// version. Comparison is strong, so weak tags never match; "*" matches any
+// version. Without the header the write proceeds unless required is set.
+func CheckIfMatch(r *http.Request, version int, required bool) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if required {
+			return ErrPreconditionRequired
+		}
+		return nil
+	}
+
+	etag := VersionETag(version)
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
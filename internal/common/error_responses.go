@@ -88,6 +88,24 @@ func UnauthorizedResponse(
 	ErrorResponse(logger, w, r, http.StatusUnauthorized, message)
 }
 
+func ForbiddenResponse(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	message, e := localizer.Localize(&i18n.LocalizeConfig{
+		MessageID: "forbidden",
+	})
+
+	if e != nil {
+		ErrorResponse(logger, w, r, http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	ErrorResponse(logger, w, r, http.StatusForbidden, message)
+}
+
 func MethodNotAllowedResponse(
 	logger *zerolog.Logger,
 	localizer *i18n.Localizer,
@@ -12,6 +12,63 @@ var ErrRecordNotFound = errors.New("record not found")
 var ErrEditConflict = errors.New("edit conflict")
 var ErrInvalidSlug = errors.New("invalid slug")
 
+var (
+	ErrOTPInvalid          = errors.New("invalid or expired otp code")
+	ErrOTPAttemptsExceeded = errors.New("too many otp attempts")
+	ErrOTPResendCooldown   = errors.New("otp was sent recently")
+)
+
+var (
+	ErrPhoneUnchanged = errors.New("new phone is the current phone")
+	ErrPhoneTaken     = errors.New("phone is already in use")
+)
+
+var (
+	ErrPasswordResetTokenInvalid = errors.New("invalid or expired password reset token")
+	ErrPasswordMismatch          = errors.New("current password does not match")
+	ErrLoginLocked               = errors.New("login temporarily locked")
+	ErrInvalidCredentials        = errors.New("invalid credentials")
+)
+
+var (
+	ErrTOTPInvalid         = errors.New("invalid totp code")
+	ErrTOTPAlreadyEnabled  = errors.New("totp is already enabled")
+	ErrTOTPNotEnabled      = errors.New("totp is not enabled")
+	ErrMFAChallengeInvalid = errors.New("invalid or expired mfa challenge")
+)
+
+var (
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrSystemRole        = errors.New("system roles cannot be renamed or deleted")
+)
+
+var ErrReferralCodeInvalid = errors.New("invalid referral code")
+
+var (
+	ErrInsufficientBonusPoints = errors.New("insufficient bonus points")
+	ErrOrderAlreadyRedeemed    = errors.New("bonus points were already redeemed for this order")
+)
+
+var (
+	ErrPrivilegeEscalation = errors.New("only superusers may manage admins and superusers")
+	ErrSelfModeration      = errors.New("users cannot moderate themselves")
+	ErrModerationNoop      = errors.New("user is already in the requested state")
+	ErrUserBanned          = errors.New("user is banned")
+)
+
+var ErrUnknownCountry = errors.New("unknown country")
+
+var ErrErasurePending = errors.New("an erasure request is already pending")
+
+var (
+	ErrEmailMissing              = errors.New("user has no email address")
+	ErrEmailAlreadyVerified      = errors.New("email is already verified")
+	ErrEmailVerificationInvalid  = errors.New("invalid or expired email verification token")
+	ErrEmailVerificationCooldown = errors.New("verification email was sent recently")
+)
+
+var ErrInvalidCursor = errors.New("invalid or outdated cursor")
+
 var (
 	ErrIntegrityConstraintViolation = errors.New("integrity constraint violation")
 	ErrRestrictViolation            = errors.New("restrict violation")
@@ -52,3 +109,8 @@ func TransformPgErrToCustomError(pgErr *pgconn.PgError) error {
 		return fmt.Errorf("%w: %s", pgErr, pgErr.Detail)
 	}
 }
+
+var (
+	ErrPreconditionRequired = errors.New("if-match header is required")
+	ErrPreconditionFailed   = errors.New("if-match does not match the current version")
+)
@@ -2,6 +2,8 @@ package common
 
 import (
 	"math"
+	"net"
+	"net/http"
 
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
@@ -19,3 +21,13 @@ func CalculateMetadata(totalRecords, page, pageSize int) types.PaginationMetadat
 		TotalRecords: totalRecords,
 	}
 }
+
+// ClientIP returns the remote ip of the request without the port. It relies
+// on chi's RealIP middleware having already rewritten r.RemoteAddr.
+func ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
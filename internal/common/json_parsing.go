@@ -1,13 +1,17 @@
 package common
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"reflect"
 	"strings"
 
+	"github.com/kcharymyrat/e-commerce/internal/patch"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
 
@@ -70,6 +74,73 @@ func ReadJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
 	return nil
 }
 
+// IsPatchRequest reports whether the body of r is a JSON Merge Patch or a
+// JSON Patch rather than a plain JSON object.
+func IsPatchRequest(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == patch.MergePatchMediaType || mediaType == patch.JSONPatchMediaType
+}
+
+// ReadPatch applies the JSON Merge Patch or JSON Patch in the body of r to
+// dst. dst holds the current representation of the resource and is
+// replaced by the patched one, so members the patch removes or sets to
+// null end up as zero values.
+func ReadPatch(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	maxBytes := 1_048_576
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return fmt.Errorf("body must not be larger than %d bytes", maxBytes)
+		}
+		return err
+	}
+	if len(body) == 0 {
+		return errors.New("body must not be empty")
+	}
+
+	doc, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	patched, err := patch.Apply(mediaType, doc, body)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	v.SetZero()
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(dst)
+	if err != nil {
+		var unmarshalTypeError *json.UnmarshalTypeError
+
+		switch {
+		case errors.As(err, &unmarshalTypeError) && unmarshalTypeError.Field != "":
+			return fmt.Errorf("patched document has incorrect JSON type for field %q", unmarshalTypeError.Field)
+
+		case errors.As(err, &unmarshalTypeError):
+			return errors.New("patched document must be a JSON object")
+
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			fieldName := strings.TrimPrefix(err.Error(), "json: unknown field ")
+			return fmt.Errorf("patched document contains unknown key %s", fieldName)
+
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
 func WriteJson(
 	w http.ResponseWriter,
 	status int,
@@ -4,14 +4,19 @@ import (
 	"errors"
 	"net/http"
 	"regexp"
+	"strings"
 
-	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
 )
 
 func ReadUUIDParam(r *http.Request) (uuid.UUID, error) {
-	idStr := chi.URLParam(r, "id") // eg: c303282d-f2e6-46ca-a04a-35d3d873712d
+	return ReadNamedUUIDParam(r, "id")
+}
+
+func ReadNamedUUIDParam(r *http.Request, name string) (uuid.UUID, error) {
+	idStr := chi.URLParam(r, name) // eg: c303282d-f2e6-46ca-a04a-35d3d873712d
 
 	idUUID, err := uuid.Parse(idStr)
 	if err != nil {
@@ -36,3 +41,27 @@ func ReadSlugParam(r *http.Request) (string, error) {
 
 	return slug, nil
 }
+
+var referralCodeRegex = regexp.MustCompile(`^[A-Za-z0-9]{1,50}$`)
+
+func ReadReferralCodeParam(r *http.Request) (string, error) {
+	code := chi.URLParam(r, "code")
+
+	if !referralCodeRegex.MatchString(code) {
+		return "", errors.New("invalid referral code")
+	}
+
+	return code, nil
+}
+
+var countryCodeRegex = regexp.MustCompile(`^[A-Za-z]{2}$`)
+
+func ReadCountryCodeParam(r *http.Request) (string, error) {
+	code := chi.URLParam(r, "code")
+
+	if !countryCodeRegex.MatchString(code) {
+		return "", errors.New("invalid country code")
+	}
+
+	return strings.ToUpper(code), nil
+}
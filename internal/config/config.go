@@ -2,6 +2,8 @@ package config
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
@@ -17,4 +19,74 @@ type Config struct {
 		ConnectTimeout    time.Duration
 	}
 	SecretKey []byte
+	OTP       struct {
+		Length         int
+		TTL            time.Duration
+		MaxAttempts    int
+		ResendCooldown time.Duration
+	}
+	SMS struct {
+		Driver     string
+		OutboxPath string
+	}
+	Mail struct {
+		Driver   string
+		From     string
+		DropDir  string
+		SMTPHost string
+		SMTPPort int
+		SMTPUser string
+		SMTPPass string
+	}
+	EmailVerification struct {
+		TokenTTL       time.Duration
+		ResendCooldown time.Duration
+		LinkBaseURL    string
+	}
+	PasswordReset struct {
+		TokenTTL time.Duration
+	}
+	MFA struct {
+		Issuer       string
+		ChallengeTTL time.Duration
+	}
+	Referral struct {
+		CodeLength          int
+		ShareBaseURL        string
+		ProductShareBaseURL string
+		ClicksPerMinute     int
+	}
+	Bonus struct {
+		PointsPerReferralSignup    decimal.Decimal
+		PointsPerProductPurchase   decimal.Decimal
+		DiscountPerReferralSignup  decimal.Decimal
+		DiscountPerProductSignup   decimal.Decimal
+		DiscountPerProductPurchase decimal.Decimal
+		PointValue                 decimal.Decimal
+	}
+	LoginThrottle struct {
+		MaxPhoneFailures int
+		MaxIPFailures    int
+		FailureWindow    time.Duration
+		BaseLockout      time.Duration
+		MaxLockout       time.Duration
+	}
+	Trash struct {
+		Retention     time.Duration
+		PurgeInterval time.Duration
+	}
+	Erasure struct {
+		CoolingOff    time.Duration
+		CheckInterval time.Duration
+	}
+	Search struct {
+		SuggestRebuildInterval time.Duration
+		ClicksPerMinute        int
+	}
+	Cache struct {
+		TTL time.Duration
+	}
+	Concurrency struct {
+		RequireIfMatch bool
+	}
 }
@@ -11,3 +11,90 @@ const (
 	InvalidSlugErrMsg = "invalid slug"
 	InvalidIDErrMsg   = "invalid id"
 )
+
+const (
+	OTPPurposeRegister    = "register"
+	OTPPurposeLogin       = "login"
+	OTPPurposeReset       = "password_reset"
+	OTPPurposePhoneChange = "phone_change"
+)
+
+const (
+	LoginThrottlePhone = "phone"
+	LoginThrottleIP    = "ip"
+)
+
+const (
+	PermCategoryRead     = "category:read"
+	PermCategoryWrite    = "category:write"
+	PermLanguageRead     = "language:read"
+	PermLanguageWrite    = "language:write"
+	PermTranslationRead  = "translation:read"
+	PermTranslationWrite = "translation:write"
+	PermUserRead         = "user:read"
+	PermUserWrite        = "user:write"
+	PermUserBan          = "user:ban"
+	PermRoleRead         = "role:read"
+	PermRoleWrite        = "role:write"
+	PermLockoutManage    = "lockout:manage"
+	PermReferralRead     = "referral:read"
+	PermPurchaseWrite    = "purchase:write"
+	PermBonusRead        = "bonus:read"
+	PermBonusWrite       = "bonus:write"
+	PermCountryWrite     = "country:write"
+	PermAuditRead        = "audit:read"
+	PermMetricsRead      = "metrics:read"
+)
+
+// Cache-Control of public responses. The catalog changes rarely but should
+// show changes within minutes; countries barely change at all. Profiles
+// carry personal data, so shared caches must not keep them and browsers
+// revalidate them every time.
+const (
+	CacheControlCatalog   = "public, max-age=60, stale-while-revalidate=300"
+	CacheControlReference = "public, max-age=3600"
+	CacheControlSearch    = "public, max-age=30"
+	CacheControlProfile   = "private, no-cache"
+)
+
+// Tags of cached public responses, invalidated when the entities they are
+// named after change.
+const (
+	CacheTagCategories   = "categories"
+	CacheTagLanguages    = "languages"
+	CacheTagTranslations = "translations"
+	CacheTagCountries    = "countries"
+)
+
+const (
+	BonusKindPoints   = "bonus_points"
+	BonusKindDiscount = "discount_percent"
+)
+
+const (
+	BonusReasonReferralSignup          = "referral_signup"
+	BonusReasonProductReferralPurchase = "product_referral_purchase"
+	BonusReasonDiscountRule            = "discount_rule"
+	BonusReasonRedemption              = "redemption"
+	BonusReasonManualAdjustment        = "manual_adjustment"
+)
+
+const (
+	UserLevelStaff     = "staff"
+	UserLevelAdmin     = "admin"
+	UserLevelSuperuser = "superuser"
+)
+
+const (
+	AuditActionCreate      = "create"
+	AuditActionUpdate      = "update"
+	AuditActionDelete      = "delete"
+	AuditActionRestore     = "restore"
+	AuditActionUserBan     = "user.ban"
+	AuditActionUserUnban   = "user.unban"
+	AuditActionUserTrust   = "user.trust"
+	AuditActionUserUntrust = "user.untrust"
+	AuditActionUserPromote = "user.promote"
+	AuditActionUserDemote  = "user.demote"
+	AuditActionUserErase   = "user.erase"
+)
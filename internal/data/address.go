@@ -0,0 +1,73 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Country struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Code        string    `json:"code" db:"code"`
+	Name        string    `json:"name" db:"name"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	CreatedByID uuid.UUID `json:"created_by_id" db:"created_by_id"`
+	UpdatedByID uuid.UUID `json:"updated_by_id" db:"updated_by_id"`
+	Version     int       `json:"version" db:"version"`
+}
+
+type Customer struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	CreatedByID uuid.UUID `json:"created_by_id" db:"created_by_id"`
+	UpdatedByID uuid.UUID `json:"updated_by_id" db:"updated_by_id"`
+	Version     int       `json:"version" db:"version"`
+}
+
+type CustomerAddress struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	CustomerID    uuid.UUID `json:"customer_id" db:"customer_id"`
+	RecipientName string    `json:"recipient_name" db:"recipient_name"`
+	CountryCode   string    `json:"country_code" db:"country_code"`
+	CountryName   string    `json:"country_name" db:"-"`
+	Region        *string   `json:"region,omitempty" db:"region"`
+	City          string    `json:"city" db:"city"`
+	Street        string    `json:"street" db:"street"`
+	PostalCode    *string   `json:"postal_code,omitempty" db:"postal_code"`
+	Phone         string    `json:"phone" db:"phone"`
+	IsDefault     bool      `json:"is_default" db:"is_default"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	CreatedByID   uuid.UUID `json:"created_by_id" db:"created_by_id"`
+	UpdatedByID   uuid.UUID `json:"updated_by_id" db:"updated_by_id"`
+	Version       int       `json:"version" db:"version"`
+}
+
+// DeliveryAddress is a copy of a saved address taken when an order is
+// placed, so later edits to the address book do not change past orders.
+type DeliveryAddress struct {
+	RecipientName string  `json:"recipient_name"`
+	CountryCode   string  `json:"country_code"`
+	CountryName   string  `json:"country_name"`
+	Region        *string `json:"region,omitempty"`
+	City          string  `json:"city"`
+	Street        string  `json:"street"`
+	PostalCode    *string `json:"postal_code,omitempty"`
+	Phone         string  `json:"phone"`
+}
+
+func (a *CustomerAddress) Snapshot() DeliveryAddress {
+	return DeliveryAddress{
+		RecipientName: a.RecipientName,
+		CountryCode:   a.CountryCode,
+		CountryName:   a.CountryName,
+		Region:        a.Region,
+		City:          a.City,
+		Street:        a.Street,
+		PostalCode:    a.PostalCode,
+		Phone:         a.Phone,
+	}
+}
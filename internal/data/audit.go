@@ -0,0 +1,49 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEntry struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	ActorID   *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`
+	Action    string          `json:"action" db:"action"`
+	TableName string          `json:"table_name" db:"table_name"`
+	EntityID  *uuid.UUID      `json:"entity_id,omitempty" db:"entity_id"`
+	Before    json.RawMessage `json:"before,omitempty" db:"before"`
+	After     json.RawMessage `json:"after,omitempty" db:"after"`
+	Reason    *string         `json:"reason,omitempty" db:"reason"`
+	RequestID *string         `json:"request_id,omitempty" db:"request_id"`
+	IP        *string         `json:"ip,omitempty" db:"ip"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFunc returns the audit entry of a change. Repositories call it once
+// the change is applied, inside the transaction that also stores the entry,
+// so that it sees values the database assigned, such as a new id.
+type AuditFunc func() (*AuditEntry, error)
+
+// UserModerationState is the part of a user that moderation actions change.
+// It is what the audit log records as before and after of those actions.
+type UserModerationState struct {
+	IsActive    bool `json:"is_active"`
+	IsBanned    bool `json:"is_banned"`
+	IsTrusted   bool `json:"is_trusted"`
+	IsStaff     bool `json:"is_staff"`
+	IsAdmin     bool `json:"is_admin"`
+	IsSuperuser bool `json:"is_superuser"`
+}
+
+func (u *User) ModerationState() UserModerationState {
+	return UserModerationState{
+		IsActive:    u.IsActive,
+		IsBanned:    u.IsBanned,
+		IsTrusted:   u.IsTrusted,
+		IsStaff:     u.IsStaff,
+		IsAdmin:     u.IsAdmin,
+		IsSuperuser: u.IsSuperuser,
+	}
+}
@@ -0,0 +1,25 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BonusLedgerEntry records one change of a user's bonus points or dynamic
+// discount and the balance it left behind.
+type BonusLedgerEntry struct {
+	ID                uuid.UUID       `json:"id" db:"id"`
+	UserID            uuid.UUID       `json:"user_id" db:"user_id"`
+	Kind              string          `json:"kind" db:"kind"`
+	Delta             decimal.Decimal `json:"delta" db:"delta"`
+	BalanceAfter      decimal.Decimal `json:"balance_after" db:"balance_after"`
+	Reason            string          `json:"reason" db:"reason"`
+	Note              *string         `json:"note,omitempty" db:"note"`
+	OrderID           *uuid.UUID      `json:"order_id,omitempty" db:"order_id"`
+	ReferralID        *uuid.UUID      `json:"referral_id,omitempty" db:"referral_id"`
+	ProductReferralID *uuid.UUID      `json:"product_referral_id,omitempty" db:"product_referral_id"`
+	ActorID           *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+}
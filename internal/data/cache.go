@@ -0,0 +1,11 @@
+package data
+
+import "net/http"
+
+// CacheEntry is a cached response, replayed as is on a hit. Header keeps
+// only the headers describing the body, such as its type and validators.
+type CacheEntry struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
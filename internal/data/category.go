@@ -18,6 +18,7 @@ type Category struct {
 	CreatedByID uuid.UUID  `json:"created_by_id" db:"created_by_id" validate:"required,uuid"`
 	UpdatedByID uuid.UUID  `json:"updated_by_id" db:"updated_by_id" validate:"required,uuid"`
 	Version     int        `json:"version" db:"version" validate:"required,number,min=1"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type CategoryWithTranslations struct {
@@ -0,0 +1,10 @@
+package data
+
+import "time"
+
+type LoginLockout struct {
+	Kind           string     `json:"kind"`
+	Key            string     `json:"key"`
+	FailedAttempts int        `json:"failed_attempts"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
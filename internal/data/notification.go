@@ -0,0 +1,30 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferences are the kinds of email a user agreed to receive.
+// Users that never changed them get DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	OrderUpdates  bool      `json:"order_updates" db:"order_updates"`
+	Promotions    bool      `json:"promotions" db:"promotions"`
+	ReviewReplies bool      `json:"review_replies" db:"review_replies"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	Version       int       `json:"version" db:"version"`
+}
+
+// DefaultNotificationPreferences matches the column defaults of
+// user_notification_preferences.
+func DefaultNotificationPreferences(userID uuid.UUID) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:        userID,
+		OrderUpdates:  true,
+		Promotions:    false,
+		ReviewReplies: true,
+	}
+}
@@ -0,0 +1,11 @@
+package data
+
+import "time"
+
+type OTP struct {
+	Phone     string    `json:"phone"`
+	Purpose   string    `json:"purpose"`
+	CodeHash  string    `json:"-"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErasureRequest is a user's request to have their personal data erased.
+// It is pending until either CancelledAt or ExecutedAt is set.
+type ErasureRequest struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	RequestedAt  time.Time  `json:"requested_at" db:"requested_at"`
+	ExecuteAfter time.Time  `json:"execute_after" db:"execute_after"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	ExecutedAt   *time.Time `json:"executed_at,omitempty" db:"executed_at"`
+}
+
+// SessionRecord is a session as shown in a data export. The refresh token
+// is a credential and is left out.
+type SessionRecord struct {
+	ID        string    `json:"id" db:"id"`
+	IsRevoked bool      `json:"is_revoked" db:"is_revoked"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// PurchaseRecord is one product of a paid order of the user.
+type PurchaseRecord struct {
+	OrderID    uuid.UUID  `json:"order_id" db:"order_id"`
+	ProductID  uuid.UUID  `json:"product_id" db:"product_id"`
+	Quantity   int        `json:"quantity" db:"quantity"`
+	ReferralID *uuid.UUID `json:"referral_id,omitempty" db:"referral_id"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type ProductReview struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	ProductID  uuid.UUID       `json:"product_id" db:"product_id"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id"`
+	Rating     decimal.Decimal `json:"rating" db:"rating"`
+	ReviewText *string         `json:"review_text,omitempty" db:"review_text"`
+	ImageUrl   *string         `json:"image_url,omitempty" db:"image_url"`
+	VideoUrl   *string         `json:"video_url,omitempty" db:"video_url"`
+	IsApproved bool            `json:"is_approved" db:"is_approved"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+	Version    int             `json:"version" db:"version"`
+}
+
+// UserDataExport is everything the store keeps about one user, as handed
+// out by the personal data export.
+type UserDataExport struct {
+	ExportedAt              time.Time                `json:"exported_at"`
+	Profile                 *User                    `json:"profile"`
+	Addresses               []*CustomerAddress       `json:"addresses"`
+	Sessions                []*SessionRecord         `json:"sessions"`
+	Purchases               []*PurchaseRecord        `json:"purchases"`
+	Reviews                 []*ProductReview         `json:"reviews"`
+	Referral                *UserReferral            `json:"referral,omitempty"`
+	ProductReferrals        []*ProductReferral       `json:"product_referrals"`
+	BonusHistory            []*BonusLedgerEntry      `json:"bonus_history"`
+	ErasureRequests         []*ErasureRequest        `json:"erasure_requests"`
+	NotificationPreferences *NotificationPreferences `json:"notification_preferences"`
+}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserReferral struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Code      string    `json:"code" db:"code"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Version   int       `json:"version" db:"version"`
+}
+
+// ReferralLeaderboardEntry is one referrer ranked by their counters.
+type ReferralLeaderboardEntry struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Phone          string    `json:"phone"`
+	FirstName      *string   `json:"first_name,omitempty"`
+	LastName       *string   `json:"last_name,omitempty"`
+	RefSignups     int       `json:"ref_signups"`
+	ProdRefSignups int       `json:"prod_ref_signups"`
+	ProdRefBought  int       `json:"prod_ref_bought"`
+	TotalReferrals int       `json:"total_referrals"`
+}
+
+type ProductReferral struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	ProductID uuid.UUID `json:"product_id" db:"product_id"`
+	Code      string    `json:"code" db:"code"`
+	Clicks    int       `json:"clicks" db:"clicks"`
+	Signups   int       `json:"signups" db:"signups"`
+	Purchases int       `json:"purchases" db:"purchases"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Version   int       `json:"version" db:"version"`
+}
+
+// PaidOrderLine is one product of a paid order as reported for purchase
+// attribution. ReferralCode is the product share code the buyer arrived
+// with, if the storefront kept one.
+type PaidOrderLine struct {
+	ProductID    uuid.UUID
+	Quantity     int
+	ReferralCode *string
+}
@@ -0,0 +1,27 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name" validate:"required,min=2,max=50"`
+	Description *string    `json:"description,omitempty" db:"description" validate:"omitempty,max=500"`
+	IsSystem    bool       `json:"is_system" db:"is_system"`
+	Permissions []string   `json:"permissions" db:"-"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	CreatedByID *uuid.UUID `json:"created_by_id,omitempty" db:"created_by_id"`
+	UpdatedByID *uuid.UUID `json:"updated_by_id,omitempty" db:"updated_by_id"`
+	Version     int        `json:"version" db:"version"`
+}
+
+type Permission struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Code        string    `json:"code" db:"code"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
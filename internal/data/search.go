@@ -0,0 +1,28 @@
+package data
+
+import "github.com/google/uuid"
+
+// CatalogRef identifies a catalog entity of any kind.
+type CatalogRef struct {
+	Kind string    `json:"kind"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// SearchHit is one catalog entity matching a search. Name is translated when
+// a translation exists; Snippet holds the matched words wrapped in <mark>.
+type SearchHit struct {
+	CatalogRef
+	Name    string  `json:"name"`
+	Slug    string  `json:"slug"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+// Suggestion is one type-ahead entry, named in the language it was indexed
+// for.
+type Suggestion struct {
+	CatalogRef
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
+	Popularity float64 `json:"-"`
+}
@@ -0,0 +1,25 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTOTP struct {
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	SecretEncrypted []byte     `json:"-" db:"secret_encrypted"`
+	IsEnabled       bool       `json:"is_enabled" db:"is_enabled"`
+	LastUsedStep    int64      `json:"-" db:"last_used_step"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	EnabledAt       *time.Time `json:"enabled_at,omitempty" db:"enabled_at"`
+}
+
+// MFAChallenge is the state kept between the password step and the second
+// factor step of the admin login.
+type MFAChallenge struct {
+	UserID   uuid.UUID
+	Enroll   bool
+	Attempts int
+}
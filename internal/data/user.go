@@ -17,6 +17,8 @@ type User struct {
 	Patronomic          *string         `json:"patronomic,omitempty"  db:"patronomic" validate:"omitempty,max=50,alpha"`
 	DOB                 *time.Time      `json:"dob,omitempty" db:"dob" validate:"omitempty,gte=1900-01-01"`
 	Email               *string         `json:"email,omitempty" db:"email" validate:"omitempty,email"`
+	EmailVerifiedAt     *time.Time      `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	PhoneVerifiedAt     *time.Time      `json:"phone_verified_at,omitempty" db:"phone_verified_at"`
 	IsActive            bool            `json:"is_active" db:"is_active" validate:"required"`
 	IsBanned            bool            `json:"is_banned" db:"is_banned" validate:"required"`
 	IsTrusted           bool            `json:"is_trusted" db:"is_trusted" validate:"required"`
@@ -38,4 +40,6 @@ type User struct {
 	CreatedByID         *uuid.UUID      `json:"created_by_id,omitempty" db:"created_by_id" validate:"omitempty,uuid"`
 	UpdatedByID         *uuid.UUID      `json:"updated_by_id,omitempty" db:"updated_by_id" validate:"omitempty,uuid"`
 	Version             int             `json:"version" db:"version" validate:"required,number,min=1"`
+	DeletedAt           *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`
+	ErasedAt            *time.Time      `json:"erased_at,omitempty" db:"erased_at"`
 }
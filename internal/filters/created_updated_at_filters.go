@@ -1,7 +1,6 @@
 package filters
 
 import (
-	"fmt"
 	"time"
 )
 
@@ -12,30 +11,9 @@ type CreatedUpdatedAtFilter struct {
 	UpdatedAtUpTo *time.Time `json:"updated_at_up_to" validate:"omitempty,gtfield=CreatedAtFrom"`
 }
 
-func AddCreatedUpdateAtFilterToSQL(
-	f *CreatedUpdatedAtFilter, query *string, argCounter *int, args []interface{},
-) {
-	if f.CreatedAtFrom != nil {
-		*query += fmt.Sprintf(" AND created_at >= $%d", *argCounter)
-		args = append(args, *f.CreatedAtFrom)
-		*argCounter++
-	}
-
-	if f.CreatedAtUpTo != nil {
-		*query += fmt.Sprintf(" AND created_at <= $%d", *argCounter)
-		args = append(args, *f.CreatedAtUpTo)
-		*argCounter++
-	}
-
-	if f.UpdatedAtFrom != nil {
-		*query += fmt.Sprintf(" AND updated_at >= $%d", *argCounter)
-		args = append(args, *f.UpdatedAtFrom)
-		*argCounter++
-	}
-
-	if f.UpdatedAtUpTo != nil {
-		*query += fmt.Sprintf(" AND updated_at <= $%d", *argCounter)
-		args = append(args, *f.UpdatedAtUpTo)
-		*argCounter++
-	}
+func (f *CreatedUpdatedAtFilter) Apply(q *Query) {
+	Gte(q, "created_at", f.CreatedAtFrom)
+	Lte(q, "created_at", f.CreatedAtUpTo)
+	Gte(q, "updated_at", f.UpdatedAtFrom)
+	Lte(q, "updated_at", f.UpdatedAtUpTo)
 }
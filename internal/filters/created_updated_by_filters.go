@@ -1,8 +1,6 @@
 package filters
 
 import (
-	"fmt"
-
 	"github.com/google/uuid"
 )
 
@@ -11,18 +9,7 @@ type CreatedUpdatedByFilter struct {
 	UpdatedByIDs []uuid.UUID `json:"updated_by_ids" validate:"omitempty,dive,uuid"`
 }
 
-func AddCreatedUpdateByFilterToSQL(
-	f *CreatedUpdatedByFilter, query *string, argCounter *int, args []interface{},
-) {
-	if len(f.CreatedByIDs) > 0 {
-		*query += fmt.Sprintf(" AND created_by = ANY($%d)", *argCounter)
-		args = append(args, f.CreatedByIDs)
-		*argCounter++
-	}
-
-	if len(f.UpdatedByIDs) > 0 {
-		*query += fmt.Sprintf(" AND updated_by_id = ANY($%d)", *argCounter)
-		args = append(args, f.UpdatedByIDs)
-		*argCounter++
-	}
+func (f *CreatedUpdatedByFilter) Apply(q *Query) {
+	AnyOf(q, "created_by_id", f.CreatedByIDs)
+	AnyOf(q, "updated_by_id", f.UpdatedByIDs)
 }
@@ -0,0 +1,235 @@
+package filters
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+)
+
+// cursorPurpose keeps a cursor from being accepted anywhere else that signs
+// with the same secret key.
+const cursorPurpose = "list_cursor"
+
+// CursorFilter selects keyset pagination: pages are addressed by an opaque
+// cursor instead of a page number, so deep pages cost as much as the first.
+// Giving either field switches a list from page to cursor mode.
+type CursorFilter struct {
+	Cursor *string `json:"cursor,omitempty" validate:"omitempty,max=2048"`
+	Limit  *int    `json:"limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+}
+
+// cursorPosition is the signed content of a cursor: the sort key values of
+// the row to continue from, and the sorts they belong to.
+type cursorPosition struct {
+	Sorts    []string  `json:"s"`
+	Values   []*string `json:"v"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// Keyset pages a query by the values of its sort keys, always ending in id,
+// rather than by offset.
+type Keyset struct {
+	keys     []SortKey
+	sorts    []string
+	limit    int
+	position *cursorPosition
+	secret   []byte
+}
+
+// NewKeyset returns the keyset for a list request, or nil when f asks for
+// page mode. It fails with common.ErrInvalidCursor when the cursor was not
+// issued by us or was issued for different sorts.
+func NewKeyset(s *SortListFilter, f *CursorFilter, secret []byte) (*Keyset, error) {
+	if f.Cursor == nil && f.Limit == nil {
+		return nil, nil
+	}
+
+	k := &Keyset{limit: DefaultPageSize, secret: secret, sorts: []string{}}
+	if f.Limit != nil {
+		k.limit = *f.Limit
+	}
+
+	for _, sort := range s.Sorts {
+		if _, ok := s.SortRegistry.Parse(sort); ok {
+			k.sorts = append(k.sorts, normalizeSort(sort))
+		}
+	}
+	k.keys = withTieBreaker(s.Keys())
+
+	if f.Cursor != nil {
+		position, err := k.decode(*f.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(position.Sorts, k.sorts) || len(position.Values) != len(k.keys) {
+			return nil, common.ErrInvalidCursor
+		}
+		k.position = position
+	}
+
+	return k, nil
+}
+
+// Columns returns the two bookkeeping columns every list query selects
+// first: the total row count in page mode, and the sort key values of the
+// row in cursor mode, where counting would defeat the point. It may be
+// called on a nil Keyset.
+func (k *Keyset) Columns() string {
+	if k == nil {
+		return "count(*) OVER(), NULL::text[]"
+	}
+
+	exprs := make([]string, len(k.keys))
+	for i, key := range k.keys {
+		exprs[i] = "(" + key.Expr + ")::text"
+	}
+	return "0, ARRAY[" + strings.Join(exprs, ", ") + "]"
+}
+
+// Apply continues q after the cursor position, orders it by the sort keys
+// and fetches one row more than the limit to learn whether more follow.
+// Going backward the order is reversed; KeysetPage restores it.
+func (k *Keyset) Apply(q *Query) {
+	keys := k.keys
+	if k.position != nil && k.position.Backward {
+		keys = make([]SortKey, len(k.keys))
+		for i, key := range k.keys {
+			keys[i] = key.reversed()
+		}
+	}
+
+	if k.position != nil {
+		q.Where(after(q, keys, k.position.Values))
+	}
+	for _, key := range keys {
+		q.OrderBy(key.Term())
+	}
+	q.Limit(k.limit + 1)
+}
+
+// after returns the condition matching rows that come strictly after values
+// in the order of keys: equal on the first i keys and past the value of key
+// i, for some i. Values are compared as text and cast by the database to
+// the type of the key.
+func after(q *Query, keys []SortKey, values []*string) string {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			placeholders[i] = q.Arg(*value)
+		}
+	}
+
+	branches := []string{}
+	for i, key := range keys {
+		conditions := []string{}
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				conditions = append(conditions, keys[j].Expr+" IS NULL")
+			} else {
+				conditions = append(conditions, keys[j].Expr+" = "+placeholders[j])
+			}
+		}
+
+		switch {
+		case values[i] == nil && key.nullsLast():
+			// Nothing sorts past NULL when NULLs come last.
+			continue
+		case values[i] == nil:
+			conditions = append(conditions, key.Expr+" IS NOT NULL")
+		default:
+			operator := " > "
+			if key.Desc {
+				operator = " < "
+			}
+			condition := key.Expr + operator + placeholders[i]
+			if key.nullable() && key.nullsLast() {
+				condition = "(" + condition + " OR " + key.Expr + " IS NULL)"
+			}
+			conditions = append(conditions, condition)
+		}
+
+		branches = append(branches, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	if len(branches) == 0 {
+		return "FALSE"
+	}
+	return "(" + strings.Join(branches, " OR ") + ")"
+}
+
+// KeysetPage cuts rows fetched with k down to the requested page and builds
+// its metadata. values[i] holds the sort key values selected for rows[i].
+func KeysetPage[T any](k *Keyset, rows []T, values [][]*string) ([]T, types.PaginationMetadata) {
+	hasMore := len(rows) > k.limit
+	if hasMore {
+		rows = rows[:k.limit]
+		values = values[:k.limit]
+	}
+
+	backward := k.position != nil && k.position.Backward
+	if backward {
+		slices.Reverse(rows)
+		slices.Reverse(values)
+	}
+
+	metadata := types.PaginationMetadata{PageSize: k.limit}
+	if len(rows) == 0 {
+		return rows, metadata
+	}
+
+	// Going backward, the page we came from is always ahead; going forward,
+	// there is a page behind unless this is the first one.
+	if hasMore || backward {
+		metadata.NextCursor = k.encode(values[len(values)-1], false)
+	}
+	if (backward && hasMore) || (!backward && k.position != nil) {
+		metadata.PrevCursor = k.encode(values[0], true)
+	}
+
+	return rows, metadata
+}
+
+func (k *Keyset) encode(values []*string, backward bool) string {
+	payload, _ := json.Marshal(cursorPosition{Sorts: k.sorts, Values: values, Backward: backward})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signCursor(encoded, k.secret)
+}
+
+func (k *Keyset) decode(cursor string) (*cursorPosition, error) {
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, common.ErrInvalidCursor
+	}
+
+	expected := signCursor(encoded, k.secret)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, common.ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, common.ErrInvalidCursor
+	}
+
+	var position cursorPosition
+	err = json.Unmarshal(payload, &position)
+	if err != nil {
+		return nil, common.ErrInvalidCursor
+	}
+
+	return &position, nil
+}
+
+func signCursor(encodedPayload string, secretKey []byte) string {
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write([]byte(cursorPurpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
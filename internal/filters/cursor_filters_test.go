@@ -0,0 +1,133 @@
+package filters
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kcharymyrat/e-commerce/internal/common"
+)
+
+var testSecret = []byte("test-secret")
+
+func strPtr(s string) *string { return &s }
+
+func mustKeyset(t *testing.T, sorts []string, f CursorFilter) *Keyset {
+	t.Helper()
+
+	k, err := NewKeyset(&SortListFilter{Sorts: sorts, SortRegistry: testSorts}, &f, testSecret)
+	if err != nil {
+		t.Fatalf("NewKeyset: %v", err)
+	}
+	return k
+}
+
+func TestNewKeysetPageMode(t *testing.T) {
+	k, err := NewKeyset(&SortListFilter{SortRegistry: testSorts}, &CursorFilter{}, testSecret)
+	if k != nil || err != nil {
+		t.Fatalf("got %v, %v; want nil, nil", k, err)
+	}
+	if got := k.Columns(); got != "count(*) OVER(), NULL::text[]" {
+		t.Errorf("Columns() = %q", got)
+	}
+}
+
+func TestKeysetFirstPage(t *testing.T) {
+	limit := 2
+	k := mustKeyset(t, []string{"-created_at", "email:nulls_first"}, CursorFilter{Limit: &limit})
+
+	if got, want := k.Columns(), "0, ARRAY[(created_at)::text, (email)::text, (id)::text]"; got != want {
+		t.Errorf("Columns() = %q, want %q", got, want)
+	}
+
+	q := NewQuery(base)
+	k.Apply(q)
+	assertQuery(t, q,
+		base+" ORDER BY created_at DESC, email ASC NULLS FIRST, id ASC LIMIT $1",
+		[]any{3},
+	)
+}
+
+func TestKeysetAfterCursor(t *testing.T) {
+	limit := 2
+	first := mustKeyset(t, []string{"-created_at", "email"}, CursorFilter{Limit: &limit})
+
+	rows := []string{"a", "b", "c"}
+	values := [][]*string{
+		{strPtr("2024-03-01"), strPtr("a@x.tm"), strPtr("1")},
+		{strPtr("2024-02-01"), nil, strPtr("2")},
+		{strPtr("2024-01-01"), strPtr("c@x.tm"), strPtr("3")},
+	}
+	page, metadata := KeysetPage(first, rows, values)
+	if !reflect.DeepEqual(page, []string{"a", "b"}) {
+		t.Fatalf("page = %v", page)
+	}
+	if metadata.NextCursor == "" || metadata.PrevCursor != "" || metadata.PageSize != 2 {
+		t.Fatalf("metadata = %+v", metadata)
+	}
+
+	next := mustKeyset(t, []string{"-created_at", "email"}, CursorFilter{Cursor: &metadata.NextCursor, Limit: &limit})
+	q := NewQuery(base)
+	next.Apply(q)
+	assertQuery(t, q,
+		base+" WHERE ((created_at < $1) OR (created_at = $1 AND email IS NULL AND id > $2))"+
+			" ORDER BY created_at DESC, email ASC, id ASC LIMIT $3",
+		[]any{"2024-02-01", "2", 3},
+	)
+
+	page, metadata = KeysetPage(next, rows[2:], values[2:])
+	if !reflect.DeepEqual(page, []string{"c"}) {
+		t.Fatalf("page = %v", page)
+	}
+	if metadata.NextCursor != "" || metadata.PrevCursor == "" {
+		t.Fatalf("metadata = %+v", metadata)
+	}
+
+	prev := mustKeyset(t, []string{"-created_at", "email"}, CursorFilter{Cursor: &metadata.PrevCursor, Limit: &limit})
+	q = NewQuery(base)
+	prev.Apply(q)
+	assertQuery(t, q,
+		base+" WHERE (((created_at > $1 OR created_at IS NULL)) OR (created_at = $1 AND email < $2)"+
+			" OR (created_at = $1 AND email = $2 AND id < $3))"+
+			" ORDER BY created_at ASC, email DESC, id DESC LIMIT $4",
+		[]any{"2024-01-01", "c@x.tm", "3", 3},
+	)
+
+	// Backward pages arrive reversed and are put back in order.
+	page, metadata = KeysetPage(prev, []string{"b", "a"}, [][]*string{values[1], values[0]})
+	if !reflect.DeepEqual(page, []string{"a", "b"}) {
+		t.Fatalf("page = %v", page)
+	}
+	if metadata.NextCursor == "" || metadata.PrevCursor != "" {
+		t.Fatalf("metadata = %+v", metadata)
+	}
+}
+
+func TestNewKeysetRejectsCursors(t *testing.T) {
+	limit := 1
+	k := mustKeyset(t, []string{"name"}, CursorFilter{Limit: &limit})
+	_, metadata := KeysetPage(k, []int{1, 2}, [][]*string{{strPtr("a"), strPtr("1")}, {strPtr("b"), strPtr("2")}})
+
+	tests := []struct {
+		name   string
+		sorts  []string
+		cursor string
+	}{
+		{"garbage", []string{"name"}, "not-a-cursor"},
+		{"tampered", []string{"name"}, "x" + metadata.NextCursor},
+		{"other sorts", []string{"-name"}, metadata.NextCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyset(
+				&SortListFilter{Sorts: tt.sorts, SortRegistry: testSorts},
+				&CursorFilter{Cursor: &tt.cursor},
+				testSecret,
+			)
+			if !errors.Is(err, common.ErrInvalidCursor) {
+				t.Errorf("got %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
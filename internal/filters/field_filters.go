@@ -0,0 +1,280 @@
+package filters
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// FieldType is the type the values of a filterable field are parsed as.
+type FieldType int
+
+const (
+	StringField FieldType = iota
+	IntField
+	DecimalField
+	BoolField
+	TimeField
+	UUIDField
+)
+
+// Operators of the filter[field][op] syntax. A parameter without an
+// operator, filter[field]=v, means eq.
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpIn       = "in"
+	OpNin      = "nin"
+	OpContains = "contains"
+	OpNull     = "null"
+)
+
+// Reasons a filter parameter is rejected. They double as the message ids
+// of the localized errors.
+const (
+	FilterUnknownField    = "filter_unknown_field"
+	FilterUnknownOperator = "filter_unknown_operator"
+	FilterInvalidValue    = "filter_invalid_value"
+)
+
+// Field is one filterable field of a resource.
+type Field struct {
+	Column   string
+	Type     FieldType
+	Nullable bool
+}
+
+// Operators returns the operators field accepts: equality and lists for
+// every type, ranges for ordered types, contains for strings and null for
+// nullable fields.
+func (f Field) Operators() []string {
+	ops := []string{OpEq, OpNe, OpIn, OpNin}
+	switch f.Type {
+	case IntField, DecimalField, TimeField:
+		ops = append(ops, OpGt, OpGte, OpLt, OpLte)
+	case StringField:
+		ops = append(ops, OpContains)
+	}
+	if f.Nullable {
+		ops = append(ops, OpNull)
+	}
+	return ops
+}
+
+// FieldSchema maps the field names a resource accepts in filter[field][op]
+// to their columns and types.
+type FieldSchema map[string]Field
+
+// Keys returns the accepted field names in alphabetical order.
+func (s FieldSchema) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Condition is one parsed filter parameter. Value is typed after the field:
+// a slice for in and nin, a bool for null.
+type Condition struct {
+	Column string
+	Op     string
+	Value  any
+}
+
+// FilterError is a rejected filter parameter. Allowed lists the accepted
+// fields or operators, when that is what was wrong.
+type FilterError struct {
+	Param   string
+	Reason  string
+	Allowed []string
+}
+
+var filterParamRX = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// ParseFieldFilters reads every filter[field][op] parameter of qs, checking
+// it against schema. Other parameters are left alone.
+func ParseFieldFilters(qs url.Values, schema FieldSchema) ([]Condition, []FilterError) {
+	conditions := []Condition{}
+	errs := []FilterError{}
+
+	params := make([]string, 0, len(qs))
+	for param := range qs {
+		if strings.HasPrefix(param, "filter[") {
+			params = append(params, param)
+		}
+	}
+	slices.Sort(params)
+
+	for _, param := range params {
+		match := filterParamRX.FindStringSubmatch(param)
+		if match == nil {
+			errs = append(errs, FilterError{Param: param, Reason: FilterUnknownField, Allowed: schema.Keys()})
+			continue
+		}
+
+		field, ok := schema[match[1]]
+		if !ok {
+			errs = append(errs, FilterError{Param: param, Reason: FilterUnknownField, Allowed: schema.Keys()})
+			continue
+		}
+
+		op := match[2]
+		if op == "" {
+			op = OpEq
+		}
+		if !slices.Contains(field.Operators(), op) {
+			errs = append(errs, FilterError{Param: param, Reason: FilterUnknownOperator, Allowed: field.Operators()})
+			continue
+		}
+
+		for _, raw := range qs[param] {
+			value, err := parseFilterValue(field.Type, op, raw)
+			if err != nil {
+				errs = append(errs, FilterError{Param: param, Reason: FilterInvalidValue})
+				break
+			}
+			conditions = append(conditions, Condition{Column: field.Column, Op: op, Value: value})
+		}
+	}
+
+	return conditions, errs
+}
+
+func parseFilterValue(fieldType FieldType, op string, raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+
+	switch op {
+	case OpNull:
+		return strconv.ParseBool(raw)
+	case OpContains:
+		if raw == "" || len(raw) > 100 {
+			return nil, fmt.Errorf("contains needs 1 to 100 characters")
+		}
+		return raw, nil
+	case OpIn, OpNin:
+		parts := strings.Split(raw, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		if len(parts) > 100 {
+			return nil, fmt.Errorf("at most 100 values")
+		}
+		return parseFilterList(fieldType, parts)
+	}
+
+	return parseFilterScalar(fieldType, raw)
+}
+
+func parseFilterScalar(fieldType FieldType, raw string) (any, error) {
+	switch fieldType {
+	case IntField:
+		return strconv.Atoi(raw)
+	case DecimalField:
+		return decimal.NewFromString(raw)
+	case BoolField:
+		return strconv.ParseBool(raw)
+	case TimeField:
+		return time.Parse(time.RFC3339, raw)
+	case UUIDField:
+		return uuid.Parse(raw)
+	default:
+		if len(raw) > 255 {
+			return nil, fmt.Errorf("value is longer than 255 characters")
+		}
+		return raw, nil
+	}
+}
+
+// parseFilterList parses an in or nin list into a slice of the field type,
+// which the database driver sends as a typed array.
+func parseFilterList(fieldType FieldType, parts []string) (any, error) {
+	switch fieldType {
+	case IntField:
+		return parseAll(parts, strconv.Atoi)
+	case DecimalField:
+		return parseAll(parts, decimal.NewFromString)
+	case BoolField:
+		return parseAll(parts, strconv.ParseBool)
+	case TimeField:
+		return parseAll(parts, func(s string) (time.Time, error) { return time.Parse(time.RFC3339, s) })
+	case UUIDField:
+		return parseAll(parts, uuid.Parse)
+	default:
+		return parseAll(parts, func(s string) (string, error) {
+			value, err := parseFilterScalar(StringField, s)
+			if err != nil {
+				return "", err
+			}
+			return value.(string), nil
+		})
+	}
+}
+
+func parseAll[T any](parts []string, parse func(string) (T, error)) ([]T, error) {
+	values := make([]T, len(parts))
+	for i, part := range parts {
+		value, err := parse(part)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// FieldFilter holds the conditions parsed from filter[field][op]
+// parameters. Handlers fill it with ParseFieldFilters.
+type FieldFilter struct {
+	Conditions []Condition `json:"-" validate:"-"`
+}
+
+// Apply adds every condition to q.
+func (f *FieldFilter) Apply(q *Query) {
+	for _, c := range f.Conditions {
+		switch c.Op {
+		case OpEq:
+			q.Where(c.Column+" = ?", c.Value)
+		case OpNe:
+			q.Where(c.Column+" IS DISTINCT FROM ?", c.Value)
+		case OpGt:
+			q.Where(c.Column+" > ?", c.Value)
+		case OpGte:
+			q.Where(c.Column+" >= ?", c.Value)
+		case OpLt:
+			q.Where(c.Column+" < ?", c.Value)
+		case OpLte:
+			q.Where(c.Column+" <= ?", c.Value)
+		case OpIn:
+			q.Where(c.Column+" = ANY(?)", c.Value)
+		case OpNin:
+			q.Where("NOT ("+c.Column+" = ANY(?))", c.Value)
+		case OpContains:
+			q.Where(c.Column+` ILIKE ? ESCAPE '\'`, "%"+escapeLike(c.Value.(string))+"%")
+		case OpNull:
+			if c.Value.(bool) {
+				q.Where(c.Column + " IS NULL")
+			} else {
+				q.Where(c.Column + " IS NOT NULL")
+			}
+		}
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
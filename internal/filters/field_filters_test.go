@@ -0,0 +1,114 @@
+package filters
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var testFields = FieldSchema{
+	"id":         {Column: "id", Type: UUIDField},
+	"slug":       {Column: "slug", Type: StringField},
+	"email":      {Column: "email", Type: StringField, Nullable: true},
+	"stock":      {Column: "stock", Type: IntField},
+	"price":      {Column: "price", Type: DecimalField},
+	"is_active":  {Column: "is_active", Type: BoolField},
+	"created_at": {Column: "created_at", Type: TimeField},
+}
+
+func TestParseFieldFilters(t *testing.T) {
+	id := uuid.MustParse("3f2504e0-4f89-11d3-9a0c-0305e82c3301")
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	qs := url.Values{
+		"filter[slug]":            {"phones"},
+		"filter[slug][in]":        {"a, b"},
+		"filter[price][gte]":      {"10.50"},
+		"filter[stock][nin]":      {"1,2"},
+		"filter[is_active][eq]":   {"true"},
+		"filter[email][null]":     {"false"},
+		"filter[id]":              {id.String()},
+		"filter[created_at][lt]":  {"2024-05-01T10:00:00Z"},
+		"filter[email][contains]": {"gmail"},
+		"page":                    {"2"},
+	}
+
+	conditions, errs := ParseFieldFilters(qs, testFields)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %+v", errs)
+	}
+
+	want := []Condition{
+		{Column: "created_at", Op: OpLt, Value: createdAt},
+		{Column: "email", Op: OpContains, Value: "gmail"},
+		{Column: "email", Op: OpNull, Value: false},
+		{Column: "id", Op: OpEq, Value: id},
+		{Column: "is_active", Op: OpEq, Value: true},
+		{Column: "price", Op: OpGte, Value: decimal.RequireFromString("10.50")},
+		{Column: "slug", Op: OpEq, Value: "phones"},
+		{Column: "slug", Op: OpIn, Value: []string{"a", "b"}},
+		{Column: "stock", Op: OpNin, Value: []int{1, 2}},
+	}
+	if !reflect.DeepEqual(conditions, want) {
+		t.Errorf("conditions:\n got: %#v\nwant: %#v", conditions, want)
+	}
+}
+
+func TestParseFieldFiltersErrors(t *testing.T) {
+	tests := []struct {
+		param   string
+		value   string
+		reason  string
+		allowed []string
+	}{
+		{"filter[password_hash]", "x", FilterUnknownField, testFields.Keys()},
+		{"filter[slug][gte][x]", "x", FilterUnknownField, testFields.Keys()},
+		{"filter[slug][gte]", "x", FilterUnknownOperator, []string{OpEq, OpNe, OpIn, OpNin, OpContains}},
+		{"filter[stock][null]", "true", FilterUnknownOperator, []string{OpEq, OpNe, OpIn, OpNin, OpGt, OpGte, OpLt, OpLte}},
+		{"filter[stock][gt]", "ten", FilterInvalidValue, nil},
+		{"filter[price][in]", "1,x", FilterInvalidValue, nil},
+		{"filter[email][null]", "maybe", FilterInvalidValue, nil},
+		{"filter[created_at]", "2024-05-01", FilterInvalidValue, nil},
+		{"filter[email][contains]", "", FilterInvalidValue, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			_, errs := ParseFieldFilters(url.Values{tt.param: {tt.value}}, testFields)
+			want := []FilterError{{Param: tt.param, Reason: tt.reason, Allowed: tt.allowed}}
+			if !reflect.DeepEqual(errs, want) {
+				t.Errorf("got %+v, want %+v", errs, want)
+			}
+		})
+	}
+}
+
+func TestFieldFilterApply(t *testing.T) {
+	f := FieldFilter{Conditions: []Condition{
+		{Column: "slug", Op: OpEq, Value: "a"},
+		{Column: "slug", Op: OpNe, Value: "b"},
+		{Column: "stock", Op: OpGt, Value: 1},
+		{Column: "stock", Op: OpGte, Value: 2},
+		{Column: "stock", Op: OpLt, Value: 9},
+		{Column: "stock", Op: OpLte, Value: 8},
+		{Column: "slug", Op: OpIn, Value: []string{"c", "d"}},
+		{Column: "stock", Op: OpNin, Value: []int{5}},
+		{Column: "email", Op: OpContains, Value: "50%_off"},
+		{Column: "email", Op: OpNull, Value: true},
+		{Column: "parent_id", Op: OpNull, Value: false},
+	}}
+
+	q := NewQuery(base)
+	f.Apply(q)
+
+	assertQuery(t, q,
+		base+" WHERE slug = $1 AND slug IS DISTINCT FROM $2 AND stock > $3 AND stock >= $4"+
+			" AND stock < $5 AND stock <= $6 AND slug = ANY($7) AND NOT (stock = ANY($8))"+
+			` AND email ILIKE $9 ESCAPE '\' AND email IS NULL AND parent_id IS NOT NULL`,
+		[]any{"a", "b", 1, 2, 9, 8, []string{"c", "d"}, []int{5}, `%50\%\_off%`},
+	)
+}
@@ -0,0 +1,171 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const base = "SELECT id FROM t"
+
+func TestCreatedUpdatedAtFilterApply(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	upTo := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("empty", func(t *testing.T) {
+		q := NewQuery(base)
+		(&CreatedUpdatedAtFilter{}).Apply(q)
+		assertQuery(t, q, base, []any{})
+	})
+
+	t.Run("all bounds", func(t *testing.T) {
+		q := NewQuery(base)
+		f := CreatedUpdatedAtFilter{
+			CreatedAtFrom: &from,
+			CreatedAtUpTo: &upTo,
+			UpdatedAtFrom: &from,
+			UpdatedAtUpTo: &upTo,
+		}
+		f.Apply(q)
+
+		assertQuery(t, q,
+			base+" WHERE created_at >= $1 AND created_at <= $2 AND updated_at >= $3 AND updated_at <= $4",
+			[]any{from, upTo, from, upTo},
+		)
+	})
+
+	t.Run("keeps placeholders after earlier conditions", func(t *testing.T) {
+		q := NewQuery(base)
+		q.Where("name = ?", "x")
+		(&CreatedUpdatedAtFilter{UpdatedAtUpTo: &upTo}).Apply(q)
+
+		assertQuery(t, q, base+" WHERE name = $1 AND updated_at <= $2", []any{"x", upTo})
+	})
+}
+
+func TestCreatedUpdatedByFilterApply(t *testing.T) {
+	createdBy := []uuid.UUID{uuid.MustParse("3f2504e0-4f89-11d3-9a0c-0305e82c3301")}
+	updatedBy := []uuid.UUID{uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")}
+
+	t.Run("empty", func(t *testing.T) {
+		q := NewQuery(base)
+		(&CreatedUpdatedByFilter{}).Apply(q)
+		assertQuery(t, q, base, []any{})
+	})
+
+	t.Run("both", func(t *testing.T) {
+		q := NewQuery(base)
+		f := CreatedUpdatedByFilter{CreatedByIDs: createdBy, UpdatedByIDs: updatedBy}
+		f.Apply(q)
+
+		assertQuery(t, q,
+			base+" WHERE created_by_id = ANY($1) AND updated_by_id = ANY($2)",
+			[]any{createdBy, updatedBy},
+		)
+	})
+}
+
+var testSorts = SortRegistry{
+	"name":       "name",
+	"email":      "email",
+	"created_at": "created_at",
+	"bonus":      "bonus_points",
+}
+
+func TestSortRegistryTerm(t *testing.T) {
+	tests := []struct {
+		sort   string
+		want   string
+		wantOK bool
+	}{
+		{"name", "name ASC", true},
+		{"-created_at", "created_at DESC", true},
+		{" Name ", "name ASC", true},
+		{"bonus", "bonus_points ASC", true},
+		{"-bonus", "bonus_points DESC", true},
+		{"email:nulls_first", "email ASC NULLS FIRST", true},
+		{"-email:NULLS_LAST", "email DESC NULLS LAST", true},
+		{"bonus_points", "", false},
+		{"email:nulls", "", false},
+		{"name; DROP TABLE t", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			got, ok := testSorts.Term(tt.sort)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Term(%q) = %q, %v; want %q, %v", tt.sort, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSortRegistryKeys(t *testing.T) {
+	got := testSorts.Keys()
+	want := []string{"bonus", "created_at", "email", "name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSortListFilterInvalid(t *testing.T) {
+	f := SortListFilter{Sorts: []string{"name", "password_hash", "-bonus", "email:first"}, SortRegistry: testSorts}
+	got := f.Invalid()
+	want := []string{"password_hash", "email:first"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSortListFilterApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		sorts   []string
+		wantSQL string
+	}{
+		{"no sorts", nil, base},
+		{"ascending", []string{"name"}, base + " ORDER BY name ASC, id ASC"},
+		{"descending", []string{"-created_at"}, base + " ORDER BY created_at DESC, id ASC"},
+		{"alias and nulls", []string{"-bonus", "email:nulls_last"}, base + " ORDER BY bonus_points DESC, email ASC NULLS LAST, id ASC"},
+		{"unknown sorts are skipped", []string{"name; DROP TABLE t", "-name"}, base + " ORDER BY name DESC, id ASC"},
+		{"only unknown sorts", []string{"password_hash"}, base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery(base)
+			(&SortListFilter{Sorts: tt.sorts, SortRegistry: testSorts}).Apply(q)
+			assertQuery(t, q, tt.wantSQL, []any{})
+		})
+	}
+}
+
+func TestPaginationFilterApply(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name         string
+		filter       PaginationFilter
+		wantPage     int
+		wantPageSize int
+		wantArgs     []any
+	}{
+		{"defaults", PaginationFilter{}, DefaultPage, DefaultPageSize, []any{DefaultPageSize, 0}},
+		{"first page", PaginationFilter{Page: intPtr(1), PageSize: intPtr(10)}, 1, 10, []any{10, 0}},
+		{"later page", PaginationFilter{Page: intPtr(4), PageSize: intPtr(25)}, 4, 25, []any{25, 75}},
+		{"page only", PaginationFilter{Page: intPtr(2)}, 2, DefaultPageSize, []any{DefaultPageSize, DefaultPageSize}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery(base)
+			page, pageSize := tt.filter.Apply(q)
+			if page != tt.wantPage || pageSize != tt.wantPageSize {
+				t.Errorf("got page %d size %d, want page %d size %d", page, pageSize, tt.wantPage, tt.wantPageSize)
+			}
+			assertQuery(t, q, base+" LIMIT $1 OFFSET $2", tt.wantArgs)
+		})
+	}
+}
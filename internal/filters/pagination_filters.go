@@ -1,34 +1,32 @@
 package filters
 
-import "fmt"
+const (
+	DefaultPage     = 1
+	DefaultPageSize = 20
+)
 
 type PaginationFilter struct {
 	Page     *int `json:"page,omitempty" validate:"omitempty,gte=1,lte=10_000_000"`
 	PageSize *int `json:"page_size,omitempty" validate:"omitempty,gte=1,lte=100"`
 }
 
-func AddPaginationFilterToSQL(
-	f *PaginationFilter, query *string, argCounter *int, args []interface{},
-) {
-	fallbackPageSize := 20 // FIXME: make a constant number
+// PageAndSize returns the requested page and page size, falling back to
+// DefaultPage and DefaultPageSize.
+func (f *PaginationFilter) PageAndSize() (int, int) {
+	page, pageSize := DefaultPage, DefaultPageSize
+	if f.Page != nil {
+		page = *f.Page
+	}
 	if f.PageSize != nil {
-		*query += fmt.Sprintf(" LIMIT $%d", *argCounter)
-		args = append(args, *f.PageSize)
-		*argCounter++
-		fallbackPageSize = *f.PageSize
-	} else {
-		f.PageSize = &fallbackPageSize
-		*query += fmt.Sprintf(" LIMIT %d", fallbackPageSize)
+		pageSize = *f.PageSize
 	}
+	return page, pageSize
+}
 
-	defaultPage := 1 // FIXME: make a constant number
-	if f.Page != nil {
-		offset := fallbackPageSize * (*f.Page - 1)
-		*query += fmt.Sprintf(" OFFSET $%d", *argCounter)
-		args = append(args, offset)
-		*argCounter++
-	} else {
-		f.Page = &defaultPage
-		*query += fmt.Sprintf(" OFFSET %d", *f.Page)
-	}
+// Apply limits q to the requested page and returns the page and page size
+// it used, for the pagination metadata.
+func (f *PaginationFilter) Apply(q *Query) (int, int) {
+	page, pageSize := f.PageAndSize()
+	q.Paginate(page, pageSize)
+	return page, pageSize
 }
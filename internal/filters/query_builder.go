@@ -0,0 +1,131 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Query assembles a SELECT from a base statement plus optional WHERE
+// conditions, ORDER BY terms and LIMIT/OFFSET. Placeholders are numbered as
+// arguments are added, so filters can be applied in any order without
+// passing a counter around.
+type Query struct {
+	base       string
+	conditions []string
+	orderBy    []string
+	pagination string
+	args       []any
+}
+
+// NewQuery starts a query from base, a statement without WHERE, ORDER BY or
+// LIMIT clauses.
+func NewQuery(base string) *Query {
+	return &Query{base: base}
+}
+
+// Arg adds value to the arguments and returns its placeholder. Use it for
+// conditions that refer to the same value more than once.
+func (q *Query) Arg(value any) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// Where adds a condition; conditions are joined with AND. Every ? in
+// condition is replaced with the placeholder of the next value. It panics
+// when the number of ? and values differ, as that is a programming error.
+func (q *Query) Where(condition string, values ...any) *Query {
+	parts := strings.Split(condition, "?")
+	if len(parts)-1 != len(values) {
+		panic(fmt.Sprintf("filters: %q expects %d values, got %d", condition, len(parts)-1, len(values)))
+	}
+
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for i, value := range values {
+		b.WriteString(q.Arg(value))
+		b.WriteString(parts[i+1])
+	}
+
+	q.conditions = append(q.conditions, b.String())
+	return q
+}
+
+// OrderBy appends ORDER BY terms such as "created_at DESC".
+func (q *Query) OrderBy(terms ...string) *Query {
+	q.orderBy = append(q.orderBy, terms...)
+	return q
+}
+
+// HasOrder reports whether any ORDER BY term was added.
+func (q *Query) HasOrder() bool {
+	return len(q.orderBy) > 0
+}
+
+// Paginate limits the query to page (starting at 1) of pageSize rows.
+func (q *Query) Paginate(page, pageSize int) *Query {
+	limit := q.Arg(pageSize)
+	offset := q.Arg((page - 1) * pageSize)
+	q.pagination = fmt.Sprintf("LIMIT %s OFFSET %s", limit, offset)
+	return q
+}
+
+// Limit caps the query at n rows, for callers that page without an offset.
+func (q *Query) Limit(n int) *Query {
+	q.pagination = "LIMIT " + q.Arg(n)
+	return q
+}
+
+// Build returns the SQL and its arguments.
+func (q *Query) Build() (string, []any) {
+	var b strings.Builder
+	b.WriteString(q.base)
+
+	if len(q.conditions) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(q.conditions, " AND "))
+	}
+
+	if len(q.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(q.orderBy, ", "))
+	}
+
+	if q.pagination != "" {
+		b.WriteString(" ")
+		b.WriteString(q.pagination)
+	}
+
+	args := q.args
+	if args == nil {
+		args = []any{}
+	}
+	return b.String(), args
+}
+
+// Eq adds "column = value" when value is set.
+func Eq[T any](q *Query, column string, value *T) {
+	if value != nil {
+		q.Where(column+" = ?", *value)
+	}
+}
+
+// Gte adds "column >= value" when value is set.
+func Gte[T any](q *Query, column string, value *T) {
+	if value != nil {
+		q.Where(column+" >= ?", *value)
+	}
+}
+
+// Lte adds "column <= value" when value is set.
+func Lte[T any](q *Query, column string, value *T) {
+	if value != nil {
+		q.Where(column+" <= ?", *value)
+	}
+}
+
+// AnyOf adds "column = ANY(values)" when values is not empty.
+func AnyOf[T any](q *Query, column string, values []T) {
+	if len(values) > 0 {
+		q.Where(column+" = ANY(?)", values)
+	}
+}
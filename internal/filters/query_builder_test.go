@@ -0,0 +1,101 @@
+package filters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func assertQuery(t *testing.T, q *Query, wantSQL string, wantArgs []any) {
+	t.Helper()
+
+	sql, args := q.Build()
+	if sql != wantSQL {
+		t.Errorf("sql:\n got: %q\nwant: %q", sql, wantSQL)
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args:\n got: %#v\nwant: %#v", args, wantArgs)
+	}
+}
+
+func TestQueryBuild(t *testing.T) {
+	t.Run("base only", func(t *testing.T) {
+		q := NewQuery("SELECT id FROM users")
+		assertQuery(t, q, "SELECT id FROM users", []any{})
+	})
+
+	t.Run("conditions are joined with AND", func(t *testing.T) {
+		q := NewQuery("SELECT id FROM users")
+		q.Where("deleted_at IS NULL")
+		q.Where("phone = ?", "+99365000000")
+		q.Where("ref_signups BETWEEN ? AND ?", 1, 5)
+
+		assertQuery(t, q,
+			"SELECT id FROM users WHERE deleted_at IS NULL AND phone = $1 AND ref_signups BETWEEN $2 AND $3",
+			[]any{"+99365000000", 1, 5},
+		)
+	})
+
+	t.Run("arg placeholder can be reused", func(t *testing.T) {
+		q := NewQuery("SELECT id FROM users")
+		q.Where("is_active = ?", true)
+		p := q.Arg("john")
+		q.Where("(phone = " + p + " OR email = " + p + ")")
+
+		assertQuery(t, q,
+			"SELECT id FROM users WHERE is_active = $1 AND (phone = $2 OR email = $2)",
+			[]any{true, "john"},
+		)
+	})
+
+	t.Run("order and pagination come last", func(t *testing.T) {
+		q := NewQuery("SELECT id FROM users")
+		q.Paginate(3, 10)
+		q.OrderBy("created_at DESC", "id")
+		q.Where("is_staff = ?", false)
+
+		assertQuery(t, q,
+			"SELECT id FROM users WHERE is_staff = $3 ORDER BY created_at DESC, id LIMIT $1 OFFSET $2",
+			[]any{10, 20, false},
+		)
+	})
+}
+
+func TestQueryHasOrder(t *testing.T) {
+	q := NewQuery("SELECT id FROM users")
+	if q.HasOrder() {
+		t.Fatal("new query reports an order")
+	}
+	q.OrderBy("id")
+	if !q.HasOrder() {
+		t.Fatal("query with ORDER BY reports no order")
+	}
+}
+
+func TestQueryWherePanicsOnArgMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+
+	NewQuery("SELECT id FROM users").Where("phone = ? OR email = ?", "x")
+}
+
+func TestComparisonHelpers(t *testing.T) {
+	name := "phones"
+	low, high := 1, 9
+	var missing *string
+
+	q := NewQuery("SELECT id FROM categories")
+	Eq(q, "name", &name)
+	Eq(q, "slug", missing)
+	Gte(q, "position", &low)
+	Lte(q, "position", &high)
+	AnyOf(q, "LOWER(slug)", []string{"a", "b"})
+	AnyOf(q, "parent_id", []string{})
+
+	assertQuery(t, q,
+		"SELECT id FROM categories WHERE name = $1 AND position >= $2 AND position <= $3 AND LOWER(slug) = ANY($4)",
+		[]any{"phones", 1, 9, []string{"a", "b"}},
+	)
+}
@@ -1,27 +1,159 @@
 package filters
 
 import (
-	"fmt"
+	"slices"
 	"strings"
 )
 
+// SortRegistry maps the sort keys a resource accepts to the SQL expressions
+// they order by. Keys are part of the API, so the expressions behind them
+// can change without breaking clients.
+type SortRegistry map[string]string
+
+// Keys returns the accepted sort keys in alphabetical order.
+func (r SortRegistry) Keys() []string {
+	keys := make([]string, 0, len(r))
+	for key := range r {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// SortKey is one parsed ORDER BY term.
+type SortKey struct {
+	Expr string
+	Desc bool
+	// Nulls is "FIRST", "LAST" or empty for the database default, which
+	// puts NULLs last in ascending and first in descending order.
+	Nulls string
+}
+
+// Term renders k as an ORDER BY term.
+func (k SortKey) Term() string {
+	term := k.Expr + " ASC"
+	if k.Desc {
+		term = k.Expr + " DESC"
+	}
+	if k.Nulls != "" {
+		term += " NULLS " + k.Nulls
+	}
+	return term
+}
+
+func (k SortKey) nullsLast() bool {
+	if k.Nulls == "" {
+		return !k.Desc
+	}
+	return k.Nulls == "LAST"
+}
+
+// nullable reports whether the key can be NULL. Only id, the primary key
+// of every listed table, is known not to be.
+func (k SortKey) nullable() bool {
+	return k.Expr != "id"
+}
+
+// reversed returns k with direction and NULL placement flipped.
+func (k SortKey) reversed() SortKey {
+	flipped := SortKey{Expr: k.Expr, Desc: !k.Desc}
+	if flipped.nullsLast() == k.nullsLast() {
+		flipped.Nulls = "FIRST"
+		if !k.nullsLast() {
+			flipped.Nulls = "LAST"
+		}
+	}
+	return flipped
+}
+
+// Parse turns a sort such as "name", "-created_at" or "email:nulls_last"
+// into a SortKey. A leading "-" sorts descending; the ":nulls_first" and
+// ":nulls_last" suffixes place NULLs explicitly. It reports false when the
+// key is not registered or the suffix is unknown.
+func (r SortRegistry) Parse(sort string) (SortKey, bool) {
+	key, modifier, _ := strings.Cut(normalizeSort(sort), ":")
+
+	desc := strings.HasPrefix(key, "-")
+	expr, ok := r[strings.TrimPrefix(key, "-")]
+	if !ok {
+		return SortKey{}, false
+	}
+
+	parsed := SortKey{Expr: expr, Desc: desc}
+	switch modifier {
+	case "":
+	case "nulls_first":
+		parsed.Nulls = "FIRST"
+	case "nulls_last":
+		parsed.Nulls = "LAST"
+	default:
+		return SortKey{}, false
+	}
+
+	return parsed, true
+}
+
+// Term is Parse followed by SortKey.Term.
+func (r SortRegistry) Term(sort string) (string, bool) {
+	key, ok := r.Parse(sort)
+	if !ok {
+		return "", false
+	}
+	return key.Term(), true
+}
+
+func normalizeSort(sort string) string {
+	return strings.TrimSpace(strings.ToLower(sort))
+}
+
+// withTieBreaker appends id to keys unless they already order by it, so
+// that rows with equal sort values keep a stable order.
+func withTieBreaker(keys []SortKey) []SortKey {
+	for _, key := range keys {
+		if key.Expr == "id" {
+			return keys
+		}
+	}
+	return append(keys, SortKey{Expr: "id"})
+}
+
 type SortListFilter struct {
-	Sorts        []string `json:"sorts" validate:"omitempty,dive,max=50"`
-	SortSafeList []string `json:"sort_safe_list" validate:"omitempty,dive,max=50"`
-}
-
-func AddSortListFilterToSQL(f *SortListFilter, query *string) {
-	if len(f.Sorts) > 0 {
-		*query += " ORDER BY"
-		for _, sort := range f.Sorts {
-			direction := "ASC"
-			sortField := strings.TrimSpace(strings.ToLower(sort))
-			if strings.HasPrefix(sort, "-") {
-				direction = "DESC"
-				sortField = strings.TrimPrefix(sort, "-")
-			}
-			*query += fmt.Sprintf(" %s %s,", sortField, direction)
+	Sorts        []string     `json:"sorts" validate:"omitempty,dive,max=50"`
+	SortRegistry SortRegistry `json:"-" validate:"-"`
+}
+
+// Invalid returns the sorts that SortRegistry does not accept.
+func (f *SortListFilter) Invalid() []string {
+	invalid := []string{}
+	for _, sort := range f.Sorts {
+		if _, ok := f.SortRegistry.Parse(sort); !ok {
+			invalid = append(invalid, sort)
+		}
+	}
+	return invalid
+}
+
+// Keys returns the sorts that SortRegistry accepts, in request order.
+func (f *SortListFilter) Keys() []SortKey {
+	keys := []SortKey{}
+	for _, sort := range f.Sorts {
+		if key, ok := f.SortRegistry.Parse(sort); ok {
+			keys = append(keys, key)
 		}
-		*query += " id ASC"
+	}
+	return keys
+}
+
+// Apply orders q by the requested sorts, with id as the final tie breaker.
+// Sorts the registry does not accept are skipped; handlers reject them
+// during validation.
+func (f *SortListFilter) Apply(q *Query) {
+	keys := f.Keys()
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, key := range withTieBreaker(keys) {
+		q.OrderBy(key.Term())
 	}
 }
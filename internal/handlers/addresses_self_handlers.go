@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/mappers"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func GetCustomerProfileSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		customer, ok := readSelfCustomer(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		addresses, err := services.ListCustomerAddressesService(app, customer.ID)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.CustomerToProfileSelfResponseMapper(customer, addresses)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"customer": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func ListAddressesSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		customer, ok := readSelfCustomer(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		addresses, err := services.ListCustomerAddressesService(app, customer.ID)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		results := make([]*responses.CustomerAddressSelfResponse, 0, len(addresses))
+		for _, address := range addresses {
+			results = append(results, mappers.CustomerAddressToSelfResponseMapper(address))
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"results": results}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func CreateAddressSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		input := requests.CustomerAddressCreateReq{}
+		err := common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		customer, ok := readSelfCustomer(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		address := mappers.CreateCustomerAddressInputToAddressMapper(&input, customer.ID, accessClaims.UserID)
+		err = services.CreateCustomerAddressService(app, address)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		headers := make(http.Header)
+		headers.Set("Location", fmt.Sprintf("api/v1/me/addresses/%s", address.ID))
+
+		res := mappers.CustomerAddressToSelfResponseMapper(address)
+		err = common.WriteJson(w, http.StatusCreated, types.Envelope{"address": res}, headers)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func GetAddressSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		address, ok := readSelfAddress(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		res := mappers.CustomerAddressToSelfResponseMapper(address)
+		err := common.WriteJson(w, http.StatusOK, types.Envelope{"address": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func UpdateAddressSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		input := requests.CustomerAddressUpdateReq{}
+		err := common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		address, ok := readSelfAddress(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		mappers.UpdateCustomerAddressInputToAddressMapper(&input, address, accessClaims.UserID)
+		err = services.UpdateCustomerAddressService(app, address)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.CustomerAddressToSelfResponseMapper(address)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"address": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func SetDefaultAddressSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		customer, ok := readSelfCustomer(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		err = services.SetDefaultCustomerAddressService(app, customer.ID, id, accessClaims.UserID)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		address, err := services.GetCustomerAddressService(app, customer.ID, id)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.CustomerAddressToSelfResponseMapper(address)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"address": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func DeleteAddressSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		customer, ok := readSelfCustomer(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		err = services.DeleteCustomerAddressService(app, customer.ID, id)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"message": "address successfully deleted"}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// readSelfCustomer loads the customer profile of the access token's user,
+// creating it on first use.
+func readSelfCustomer(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*data.Customer, bool) {
+	accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+	customer, err := services.GetOrCreateCustomerService(app, accessClaims.UserID)
+	if err != nil {
+		HandleCountryErrors(app.Logger, localizer, w, r, err)
+		return nil, false
+	}
+
+	return customer, true
+}
+
+func readSelfAddress(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*data.CustomerAddress, bool) {
+	id, err := common.ReadUUIDParam(r)
+	if err != nil {
+		common.BadRequestResponse(app.Logger, localizer, w, r, err)
+		return nil, false
+	}
+
+	customer, ok := readSelfCustomer(app, localizer, w, r)
+	if !ok {
+		return nil, false
+	}
+
+	address, err := services.GetCustomerAddressService(app, customer.ID, id)
+	if err != nil {
+		HandleCountryErrors(app.Logger, localizer, w, r, err)
+		return nil, false
+	}
+
+	return address, true
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func ListAuditLogAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters := requests.AuditLogFilters{}
+
+		qs := r.URL.Query()
+		filters.ActorID = common.ReadQueryUUID(qs, "actor_id")
+		filters.Action = common.ReadQueryStr(qs, "action")
+		filters.TableName = common.ReadQueryStr(qs, "table_name")
+		filters.EntityID = common.ReadQueryUUID(qs, "entity_id")
+		filters.RequestID = common.ReadQueryStr(qs, "request_id")
+		filters.CreatedAtFrom = common.ReadQueryTime(qs, "created_at_from")
+		filters.CreatedAtUpTo = common.ReadQueryTime(qs, "created_at_up_to")
+		filters.Page = common.ReadQueryInt(qs, "page")
+		filters.PageSize = common.ReadQueryInt(qs, "page_size")
+
+		err := app.Validator.Struct(&filters)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		entries, metadata, err := services.ListAuditLogService(app, &filters)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{
+			"metadata": metadata,
+			"results":  entries,
+		}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func GetBonusSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		writeBonusHistory(app, localizer, w, r, user.ID, &responses.BonusBalanceResponse{
+			BonusPoints:     user.BonusPoints,
+			DiscountPercent: user.DynDiscPercent,
+		})
+	}
+}
+
+// RedeemBonusAdminHandler spends bonus points on an order. It is called by
+// the checkout, which knows the order belongs to the buyer; customers can not
+// redeem directly because there is no orders table to check ownership against.
+func RedeemBonusAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		input := requests.BonusRedeemReq{}
+		err := common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		amount, err := services.RedeemBonusPointsService(
+			app, input.BuyerID, accessClaims.UserID, input.OrderID, input.Points,
+		)
+		if err != nil {
+			HandleBonusErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := &responses.BonusRedemptionResponse{
+			RedeemedPoints: input.Points,
+			Amount:         amount,
+		}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"redemption": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func GetUserBonusAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		user, err := services.GetUserByIDService(app, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		writeBonusHistory(app, localizer, w, r, user.ID, &responses.BonusBalanceResponse{
+			BonusPoints:     user.BonusPoints,
+			DiscountPercent: user.DynDiscPercent,
+		})
+	}
+}
+
+func AdjustUserBonusAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		input := requests.BonusAdjustReq{}
+		err = common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		err = services.AdjustBonusAdminService(app, id, accessClaims.UserID, &input)
+		if err != nil {
+			HandleBonusErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"message": "bonus successfully adjusted"}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func writeBonusHistory(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	userID uuid.UUID,
+	balance *responses.BonusBalanceResponse,
+) {
+	valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+
+	filters := requests.BonusHistoryFilters{}
+
+	qs := r.URL.Query()
+	filters.Page = common.ReadQueryInt(qs, "page")
+	filters.PageSize = common.ReadQueryInt(qs, "page_size")
+
+	err := app.Validator.Struct(&filters)
+	if err != nil {
+		errs := err.(validator.ValidationErrors)
+		translatedErrs := make(map[string]string)
+		for _, e := range errs {
+			translatedErrs[e.Field()] = e.Translate(valTrans)
+		}
+		common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+		return
+	}
+
+	entries, metadata, err := services.ListBonusHistoryService(app, userID, &filters)
+	if err != nil {
+		common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		return
+	}
+
+	err = common.WriteJson(w, http.StatusOK, types.Envelope{
+		"balance":  balance,
+		"metadata": metadata,
+		"results":  entries,
+	}, nil)
+	if err != nil {
+		common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// GetCacheStatsAdminHandler reports the response cache counters of this
+// instance: hits, misses, invalidations and errors, in total and per tag
+// as "<tag>.hits" and so on.
+func GetCacheStatsAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		stats := services.CacheStatsService(app)
+
+		err := common.WriteJson(w, http.StatusOK, types.Envelope{"cache": stats}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
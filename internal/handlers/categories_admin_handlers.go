@@ -11,6 +11,7 @@ import (
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/mappers"
@@ -24,6 +25,7 @@ func CreateCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		var categoryInput requests.CategoryAdminCreate
 		err := common.ReadJSON(w, r, &categoryInput)
@@ -32,7 +34,7 @@ func CreateCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		category := mappers.CreateCategoryInputToCategoryMapper(&categoryInput)
+		category := mappers.CreateCategoryInputToCategoryMapper(&categoryInput, accessClaims.UserID)
 		err = app.Validator.Struct(category)
 		if err != nil {
 			errs := err.(validator.ValidationErrors)
@@ -44,7 +46,7 @@ func CreateCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.CreateCategoryService(app, category)
+		err = services.CreateCategoryService(app, category, newAuditEntry(r, constants.AuditActionCreate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
@@ -118,7 +120,7 @@ func GetCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 		err = common.WriteJson(w, http.StatusOK, types.Envelope{
 			"category":     categoryManagerResponse,
 			"translations": trMapWrapper,
-		}, nil)
+		}, common.VersionHeaders(category.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -135,6 +137,9 @@ func ListCategoriesManagerHandler(app *app.Application) http.HandlerFunc {
 		filters := requests.CategoriesAdminFilters{}
 
 		readCategoryAdminQueryParams(&filters, r.URL.Query())
+		if !readFieldFilters(app, localizer, w, r, requests.CategoryFields, &filters.FieldFilter) {
+			return
+		}
 
 		err := app.Validator.Struct(filters)
 		if err != nil {
@@ -200,6 +205,7 @@ func UpdateCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		slug, err := common.ReadSlugParam(r)
 		if err != nil {
@@ -220,6 +226,10 @@ func UpdateCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
+		if !checkIfMatch(app, localizer, w, r, category.Version, mappers.CategoryToCategoryManagerResponseMapper(category)) {
+			return
+		}
+
 		input := requests.CategoryAdminUpdate{}
 
 		err = common.ReadJSON(w, r, &input)
@@ -239,18 +249,22 @@ func UpdateCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.UpdateCategoryService(app, &input, category)
+		err = services.UpdateCategoryService(app, &input, category, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, err)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"category": category}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"category": category}, common.VersionHeaders(category.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -261,6 +275,7 @@ func PartialUpdateCategoryManagerHandler(app *app.Application) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		slug, err := common.ReadSlugParam(r)
 		if err != nil {
@@ -281,37 +296,53 @@ func PartialUpdateCategoryManagerHandler(app *app.Application) http.HandlerFunc
 			return
 		}
 
-		input := requests.CategoryAdminPartialUpdate{}
-
-		err = common.ReadJSON(w, r, &input)
-		if err != nil {
-			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+		if !checkIfMatch(app, localizer, w, r, category.Version, mappers.CategoryToCategoryManagerResponseMapper(category)) {
 			return
 		}
 
-		err = app.Validator.Struct(category)
-		if err != nil {
-			errs := err.(validator.ValidationErrors)
-			translatedErrs := make(map[string]string)
-			for _, e := range errs {
-				translatedErrs[e.Field()] = e.Translate(valTrans)
+		if common.IsPatchRequest(r) {
+			input := mappers.CategoryToCategoryAdminUpdateMapper(category)
+			if !readPatchInput(app, valTrans, localizer, w, r, input) {
+				return
 			}
-			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
-			return
-		}
+			err = services.UpdateCategoryService(app, input, category, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		} else {
+			input := requests.CategoryAdminPartialUpdate{}
 
-		err = services.PartialUpdateCategoryService(app, &input, category)
+			err = common.ReadJSON(w, r, &input)
+			if err != nil {
+				common.BadRequestResponse(app.Logger, localizer, w, r, err)
+				return
+			}
+
+			err = app.Validator.Struct(input)
+			if err != nil {
+				errs := err.(validator.ValidationErrors)
+				translatedErrs := make(map[string]string)
+				for _, e := range errs {
+					translatedErrs[e.Field()] = e.Translate(valTrans)
+				}
+				common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+				return
+			}
+
+			err = services.PartialUpdateCategoryService(app, &input, category, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		}
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, err)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"category": category}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"category": category}, common.VersionHeaders(category.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -329,7 +360,22 @@ func DeleteCategoryManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.DeleteCategoryServiceBySlug(app, slug)
+		category, err := services.GetCategoryBySlugService(app, slug)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, category.Version, mappers.CategoryToCategoryManagerResponseMapper(category)) {
+			return
+		}
+
+		err = services.DeleteCategoryServiceBySlug(app, category, newAuditEntry(r, constants.AuditActionDelete, nil))
 		if err != nil {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
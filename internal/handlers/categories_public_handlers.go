@@ -37,6 +37,9 @@ func ListCategoriesPublicHandler(app *app.Application) http.HandlerFunc {
 		filters := requests.CategoriesAdminFilters{}
 
 		readCategoryAdminQueryParams(&filters, r.URL.Query())
+		if !readFieldFilters(app, localizer, w, r, requests.CategoryFields, &filters.FieldFilter) {
+			return
+		}
 
 		err := app.Validator.Struct(filters)
 		if err != nil {
@@ -112,7 +115,11 @@ func GetCategoryPublicHandler(app *app.Application) http.HandlerFunc {
 		categoryPublicResponse := mappers.CategoryToCategoryPublicResponseMapper(catWithTrs.Category)
 
 		detailResponse := types.NewDetailResponse(categoryPublicResponse, catWithTrs.Translations)
-		err = common.WriteDetailJson(w, http.StatusOK, detailResponse, nil)
+		headers := common.ValidatorHeaders(
+			common.EntityETag(catWithTrs.Category.ID, catWithTrs.Category.Version, catWithTrs.Translations),
+			common.LastModified(catWithTrs.Category.UpdatedAt, catWithTrs.Translations),
+		)
+		err = common.WriteDetailJson(w, http.StatusOK, detailResponse, headers)
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
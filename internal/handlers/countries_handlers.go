@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/mappers"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func ListCountriesPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		countries, err := services.ListCountriesService(app)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		results := make([]*responses.CountryPublicResponse, 0, len(countries))
+		for _, country := range countries {
+			results = append(results, mappers.CountryToCountryPublicResponseMapper(country))
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"results": results}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func CreateCountryAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		input := requests.CountryAdminCreate{}
+		err := common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		country := mappers.CreateCountryInputToCountryMapper(&input, accessClaims.UserID)
+		err = services.CreateCountryService(app, country)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		headers := make(http.Header)
+		headers.Set("Location", fmt.Sprintf("api/v1/countries/%s", country.Code))
+
+		res := mappers.CountryToCountryAdminResponseMapper(country)
+		err = common.WriteJson(w, http.StatusCreated, types.Envelope{"country": res}, headers)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func UpdateCountryAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		code, err := common.ReadCountryCodeParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		input := requests.CountryAdminUpdate{}
+		err = common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		country, err := services.GetCountryByCodeService(app, code)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, country.Version, mappers.CountryToCountryAdminResponseMapper(country)) {
+			return
+		}
+
+		country.Name = input.Name
+		country.UpdatedByID = accessClaims.UserID
+		err = services.UpdateCountryService(app, country)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.CountryToCountryAdminResponseMapper(country)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"country": res}, common.VersionHeaders(country.Version))
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func DeleteCountryAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		code, err := common.ReadCountryCodeParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		country, err := services.GetCountryByCodeService(app, code)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, country.Version, mappers.CountryToCountryAdminResponseMapper(country)) {
+			return
+		}
+
+		err = services.DeleteCountryService(app, code)
+		if err != nil {
+			HandleCountryErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"message": "country successfully deleted"}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/mappers"
@@ -23,6 +24,7 @@ func CreateLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		input := requests.LanguageAdminCreate{}
 		err := common.ReadJSON(w, r, &input)
@@ -31,7 +33,7 @@ func CreateLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		language := mappers.CreateLanguageInputToLanguageMapper(&input)
+		language := mappers.CreateLanguageInputToLanguageMapper(&input, accessClaims.UserID)
 		err = app.Validator.Struct(language)
 		if err != nil {
 			errs := err.(validator.ValidationErrors)
@@ -43,7 +45,7 @@ func CreateLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.CreateLanguageService(app, language)
+		err = services.CreateLanguageService(app, language, newAuditEntry(r, constants.AuditActionCreate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
@@ -90,7 +92,7 @@ func GetLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 
 		languageResponse := mappers.LanguageToLanguageManagerResponseMapper(language)
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": languageResponse}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": languageResponse}, common.VersionHeaders(language.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -143,6 +145,7 @@ func UpdateLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -151,7 +154,7 @@ func UpdateLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 		}
 
 		input := requests.LanguageAdminUpdate{}
-		err = common.ReadJSON(w, r, input)
+		err = common.ReadJSON(w, r, &input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
@@ -177,22 +180,30 @@ func UpdateLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 				common.NotFoundResponse(app.Logger, localizer, w, r)
 			default:
 				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
-				return
 			}
+			return
 		}
 
-		err = services.UpdateLanguageService(app, &input, language)
+		if !checkIfMatch(app, localizer, w, r, language.Version, mappers.LanguageToLanguageManagerResponseMapper(language)) {
+			return
+		}
+
+		err = services.UpdateLanguageService(app, &input, language, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, err)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": language}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": language}, common.VersionHeaders(language.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -203,27 +214,11 @@ func PartialUpdateLanguageManagerHandler(app *app.Application) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
-		}
-
-		input := requests.LanguageAdminPartialUpdate{}
-		err = common.ReadJSON(w, r, input)
-		if err != nil {
-			common.BadRequestResponse(app.Logger, localizer, w, r, err)
-			return
-		}
-
-		err = app.Validator.Struct(input)
-		if err != nil {
-			errs := err.(validator.ValidationErrors)
-			transErrs := make(map[string]string)
-			for _, e := range errs {
-				transErrs[e.Field()] = e.Translate(valTrans)
-			}
-			common.FailedValidationResponse(app.Logger, w, r, transErrs)
 			return
 		}
 
@@ -238,18 +233,52 @@ func PartialUpdateLanguageManagerHandler(app *app.Application) http.HandlerFunc
 			return
 		}
 
-		err = services.PartialUpdateLanguageService(app, &input, language)
+		if !checkIfMatch(app, localizer, w, r, language.Version, mappers.LanguageToLanguageManagerResponseMapper(language)) {
+			return
+		}
+
+		if common.IsPatchRequest(r) {
+			input := mappers.LanguageToLanguageAdminUpdateMapper(language)
+			if !readPatchInput(app, valTrans, localizer, w, r, input) {
+				return
+			}
+			err = services.UpdateLanguageService(app, input, language, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		} else {
+			input := requests.LanguageAdminPartialUpdate{}
+			err = common.ReadJSON(w, r, &input)
+			if err != nil {
+				common.BadRequestResponse(app.Logger, localizer, w, r, err)
+				return
+			}
+
+			err = app.Validator.Struct(input)
+			if err != nil {
+				errs := err.(validator.ValidationErrors)
+				transErrs := make(map[string]string)
+				for _, e := range errs {
+					transErrs[e.Field()] = e.Translate(valTrans)
+				}
+				common.FailedValidationResponse(app.Logger, w, r, transErrs)
+				return
+			}
+
+			err = services.PartialUpdateLanguageService(app, &input, language, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		}
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, err)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": language}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": language}, common.VersionHeaders(language.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -267,7 +296,22 @@ func DeleteLanguageManagerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.DeleteLanguageService(app, id)
+		language, err := services.GetLanguageService(app, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, language.Version, mappers.LanguageToLanguageManagerResponseMapper(language)) {
+			return
+		}
+
+		err = services.DeleteLanguageService(app, language, newAuditEntry(r, constants.AuditActionDelete, nil))
 		if err != nil {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
@@ -110,7 +110,11 @@ func GetLanguagePublicHandler(app *app.Application) http.HandlerFunc {
 		languageResponse := mappers.LanguageToLanguagePublicResponseMapper(language)
 
 		detailResponse := types.NewDetailResponse(languageResponse, nil)
-		err = common.WriteDetailJson(w, http.StatusOK, detailResponse, nil)
+		headers := common.ValidatorHeaders(
+			common.EntityETag(language.ID, language.Version, nil),
+			common.LastModified(language.UpdatedAt, nil),
+		)
+		err = common.WriteDetailJson(w, http.StatusOK, detailResponse, headers)
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -3,20 +3,14 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"time"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
-	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/app"
-	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
-	"github.com/kcharymyrat/e-commerce/internal/data"
 	"github.com/kcharymyrat/e-commerce/internal/services"
-	"github.com/kcharymyrat/e-commerce/internal/types"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
 
@@ -25,7 +19,7 @@ func LoginAdminHandler(app *app.Application) http.HandlerFunc {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
 
-		input := requests.UserLoginReq{}
+		input := &requests.UserLoginReq{}
 		err := common.ReadJSON(w, r, input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
@@ -43,116 +37,39 @@ func LoginAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		user, err := services.GetUserByPhoneService(app, input.Phone)
-		if err != nil {
-			switch {
-			case errors.Is(err, common.ErrRecordNotFound):
-				common.NotFoundResponse(app.Logger, localizer, w, r)
-			default:
-				common.BadRequestResponse(app.Logger, localizer, w, r, err)
-			}
-			return
-		}
+		ip := common.ClientIP(r)
 
-		ok, err := auth.IsPasswordInputMatching(input.Password, user.PasswordHash)
+		lockout, err := services.CheckLoginLockoutService(app, input.Phone, ip)
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
-		if !ok {
-			common.UnauthorizedResponse(app.Logger, localizer, w, r)
-			return
-		}
-
-		if !user.IsActive || user.IsBanned {
-			app.Logger.Info().Msg("user is not active or banned")
-			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+		if lockout > 0 {
+			HandleLoginErrors(app.Logger, localizer, w, r, common.ErrLoginLocked, lockout)
 			return
 		}
 
-		if !user.IsStaff && !user.IsAdmin && !user.IsSuperuser {
+		user, err := services.AuthenticateWithPasswordService(app, input.Phone, input.Password)
+		if err == nil && !user.IsStaff && !user.IsAdmin && !user.IsSuperuser {
 			app.Logger.Info().Msg("user is not staff")
-			common.UnauthorizedResponse(app.Logger, localizer, w, r)
-			return
-		}
-
-		accessToken, accessClaims, err := auth.GenerateJWT(
-			user.ID,
-			user.Phone,
-			user.FirstName,
-			user.LastName,
-			user.Patronomic,
-			user.IsActive,
-			user.IsBanned,
-			user.IsStaff,
-			user.IsAdmin,
-			user.IsSuperuser,
-			5*time.Minute,
-			app.Config.SecretKey,
-			app.Logger,
-		)
-		if err != nil {
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
-			return
-		}
-
-		refreshToken, refreshClaims, err := auth.GenerateJWT(
-			user.ID,
-			user.Phone,
-			user.FirstName,
-			user.LastName,
-			user.Patronomic,
-			user.IsActive,
-			user.IsBanned,
-			user.IsStaff,
-			user.IsAdmin,
-			user.IsSuperuser,
-			48*time.Hour,
-			app.Config.SecretKey,
-			app.Logger,
-		)
-		if err != nil {
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
-			return
+			err = common.ErrInvalidCredentials
 		}
-
-		session := data.Session{
-			ID:           uuid.MustParse(refreshClaims.RegisteredClaims.ID),
-			UserPhone:    user.Phone,
-			RefreshToken: refreshToken,
-			IsRevoked:    false,
-			ExpiresAt:    refreshClaims.RegisteredClaims.ExpiresAt.Time,
-		}
-		err = services.CreateSessionService(app, &session)
 		if err != nil {
-			app.Logger.Error().Err(err).Msg("failed to create session")
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			if errors.Is(err, common.ErrInvalidCredentials) {
+				e := services.RegisterLoginFailureService(app, input.Phone, ip)
+				if e != nil {
+					app.Logger.Error().Err(e).Msg("failed to register login failure")
+				}
+			}
+			HandleLoginErrors(app.Logger, localizer, w, r, err, 0)
 			return
 		}
 
-		res := responses.LoginResponse{
-			SessionID:             session.ID,
-			AccessToken:           accessToken,
-			RefreshToken:          refreshToken,
-			AccessTokenExpiresAt:  accessClaims.RegisteredClaims.ExpiresAt.Time,
-			RefreshTokenExpiresAt: refreshClaims.RegisteredClaims.ExpiresAt.Time,
-			User: responses.ShortUserResponse{
-				ID:          user.ID,
-				Phone:       user.Phone,
-				FirstName:   user.FirstName,
-				LastName:    user.LastName,
-				Patronomic:  user.Patronomic,
-				IsActive:    user.IsActive,
-				IsBanned:    user.IsBanned,
-				IsStaff:     user.IsStaff,
-				IsAdmin:     user.IsAdmin,
-				IsSuperuser: user.IsSuperuser,
-			},
-		}
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		err = services.ResetLoginFailuresService(app, input.Phone)
 		if err != nil {
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			app.Logger.Error().Err(err).Msg("failed to reset login failures")
 		}
 
+		completeLogin(app, localizer, w, r, user)
 	}
 }
@@ -7,14 +7,11 @@ import (
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/app"
-	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
-	"github.com/kcharymyrat/e-commerce/internal/data"
 	"github.com/kcharymyrat/e-commerce/internal/services"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
@@ -43,110 +40,138 @@ func LoginWithPasswordPublicHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		user, err := services.GetUserByPhoneService(app, input.Phone)
+		ip := common.ClientIP(r)
+
+		lockout, err := services.CheckLoginLockoutService(app, input.Phone, ip)
 		if err != nil {
-			switch {
-			case errors.Is(err, common.ErrRecordNotFound):
-				common.NotFoundResponse(app.Logger, localizer, w, r)
-			default:
-				common.BadRequestResponse(app.Logger, localizer, w, r, err)
-			}
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+		if lockout > 0 {
+			HandleLoginErrors(app.Logger, localizer, w, r, common.ErrLoginLocked, lockout)
 			return
 		}
 
-		ok, err := auth.IsPasswordInputMatching(input.Password, user.PasswordHash)
+		user, err := services.AuthenticateWithPasswordService(app, input.Phone, input.Password)
 		if err != nil {
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			if errors.Is(err, common.ErrInvalidCredentials) {
+				e := services.RegisterLoginFailureService(app, input.Phone, ip)
+				if e != nil {
+					app.Logger.Error().Err(e).Msg("failed to register login failure")
+				}
+			}
+			HandleLoginErrors(app.Logger, localizer, w, r, err, 0)
 			return
 		}
-		if !ok {
-			common.UnauthorizedResponse(app.Logger, localizer, w, r)
-			return
+
+		err = services.ResetLoginFailuresService(app, input.Phone)
+		if err != nil {
+			app.Logger.Error().Err(err).Msg("failed to reset login failures")
 		}
 
-		if !user.IsActive || user.IsBanned {
-			app.Logger.Info().Msg("user is not active or banned")
-			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+		completeLogin(app, localizer, w, r, user)
+
+	}
+}
+
+func RequestLoginOTPPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.UserSMSLoginReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		accessToken, accessClaims, err := auth.GenerateJWT(
-			user.ID,
-			user.Phone,
-			user.FirstName,
-			user.LastName,
-			user.Patronomic,
-			user.IsActive,
-			user.IsBanned,
-			user.IsStaff,
-			user.IsAdmin,
-			user.IsSuperuser,
-			5*time.Minute,
-			app.Config.SecretKey,
-			app.Logger,
-		)
+		err = app.Validator.Struct(input)
 		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		res := responses.OTPSentResponse{
+			Phone:     input.Phone,
+			ExpiresAt: time.Now().Add(app.Config.OTP.TTL),
+		}
+
+		user, err := services.GetUserByPhoneService(app, input.Phone)
+		if err != nil && !errors.Is(err, common.ErrRecordNotFound) {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		refreshToken, refreshClaims, err := auth.GenerateJWT(
-			user.ID,
-			user.Phone,
-			user.FirstName,
-			user.LastName,
-			user.Patronomic,
-			user.IsActive,
-			user.IsBanned,
-			user.IsStaff,
-			user.IsAdmin,
-			user.IsSuperuser,
-			48*time.Hour,
-			app.Config.SecretKey,
-			app.Logger,
-		)
+		// Unknown, inactive and banned phones get the same answer as valid
+		// ones so the endpoint can not be used to enumerate accounts.
+		if user != nil && user.IsActive && !user.IsBanned {
+			otp, err := services.IssueOTPService(app, localizer, user.Phone, constants.OTPPurposeLogin)
+			if err != nil {
+				HandleOTPErrors(app.Logger, localizer, w, r, err)
+				return
+			}
+			res.ExpiresAt = otp.ExpiresAt
+		}
+
+		err = common.WriteJson(w, http.StatusAccepted, types.Envelope{"result": res}, nil)
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func LoginWithOTPPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.UserOTPVerifyReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		session := data.Session{
-			ID:           uuid.MustParse(refreshClaims.RegisteredClaims.ID),
-			UserPhone:    user.Phone,
-			RefreshToken: refreshToken,
-			IsRevoked:    false,
-			ExpiresAt:    refreshClaims.RegisteredClaims.ExpiresAt.Time,
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
 		}
-		err = services.CreateSessionService(app, &session)
+
+		err = services.VerifyOTPService(app, input.Phone, constants.OTPPurposeLogin, input.Code)
 		if err != nil {
-			app.Logger.Error().Err(err).Msg("failed to create session")
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			HandleOTPErrors(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		res := responses.LoginResponse{
-			SessionID:             session.ID,
-			AccessToken:           accessToken,
-			RefreshToken:          refreshToken,
-			AccessTokenExpiresAt:  accessClaims.RegisteredClaims.ExpiresAt.Time,
-			RefreshTokenExpiresAt: refreshClaims.RegisteredClaims.ExpiresAt.Time,
-			User: responses.ShortUserResponse{
-				ID:          user.ID,
-				Phone:       user.Phone,
-				FirstName:   user.FirstName,
-				LastName:    user.LastName,
-				Patronomic:  user.Patronomic,
-				IsActive:    user.IsActive,
-				IsBanned:    user.IsBanned,
-				IsStaff:     user.IsStaff,
-				IsAdmin:     user.IsAdmin,
-				IsSuperuser: user.IsSuperuser,
-			},
-		}
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		user, err := services.GetUserByPhoneService(app, input.Phone)
 		if err != nil {
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		if !user.IsActive || user.IsBanned {
+			app.Logger.Info().Msg("user is not active or banned")
+			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			return
 		}
 
+		completeLogin(app, localizer, w, r, user)
 	}
 }
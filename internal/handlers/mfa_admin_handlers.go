@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// EnrollMFAAdminHandler lets a user whose login challenge requires
+// enrollment create a totp secret before the first login completes.
+func EnrollMFAAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.MFAEnrollReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		challenge, err := services.GetMFAChallengeService(app, input.MFAToken)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+		if !challenge.Enroll {
+			HandleMFAErrors(app.Logger, localizer, w, r, common.ErrTOTPAlreadyEnabled)
+			return
+		}
+
+		user, err := services.GetUserByIDService(app, challenge.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				HandleMFAErrors(app.Logger, localizer, w, r, common.ErrMFAChallengeInvalid)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		secret, uri, err := services.StartTOTPEnrollmentService(app, user)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := responses.TOTPEnrollmentResponse{
+			Secret:          secret,
+			ProvisioningURI: uri,
+		}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// VerifyMFAAdminHandler completes a challenged login, admin or public, with
+// a totp or recovery code. For enrollment challenges it also enables the
+// new secret and returns the recovery codes once.
+func VerifyMFAAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.MFAVerifyReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		err = services.AttemptMFAChallengeService(app, input.MFAToken)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		challenge, err := services.GetMFAChallengeService(app, input.MFAToken)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		user, err := services.GetUserByIDService(app, challenge.UserID)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				HandleMFAErrors(app.Logger, localizer, w, r, common.ErrMFAChallengeInvalid)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		var recoveryCodes []string
+		if challenge.Enroll {
+			recoveryCodes, err = services.ConfirmTOTPEnrollmentService(app, user, input.Code)
+		} else {
+			err = services.VerifySecondFactorService(app, user, input.Code)
+		}
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = services.DeleteMFAChallengeService(app, input.MFAToken)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if !user.IsActive || user.IsBanned {
+			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			return
+		}
+
+		res, err := issueLoginTokens(app, user)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		envelope := types.Envelope{"result": res}
+		if recoveryCodes != nil {
+			envelope["recovery_codes"] = recoveryCodes
+		}
+
+		err = common.WriteJson(w, http.StatusOK, envelope, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
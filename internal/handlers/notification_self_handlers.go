@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/mappers"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func GetNotificationPreferencesSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		prefs, err := services.GetNotificationPreferencesService(app, accessClaims.UserID)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"notification_preferences": prefs}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func UpdateNotificationPreferencesSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		prefs, err := services.GetNotificationPreferencesService(app, accessClaims.UserID)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if common.IsPatchRequest(r) {
+			input := mappers.NotificationPreferencesToNotificationPreferencesUpdate(prefs)
+			if !readPatchInput(app, valTrans, localizer, w, r, input) {
+				return
+			}
+			err = services.UpdateNotificationPreferencesService(app, input, prefs)
+		} else {
+			input := requests.NotificationPreferencesPartialUpdate{}
+			err = common.ReadJSON(w, r, &input)
+			if err != nil {
+				common.BadRequestResponse(app.Logger, localizer, w, r, err)
+				return
+			}
+
+			err = services.PartialUpdateNotificationPreferencesService(app, &input, prefs)
+		}
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"notification_preferences": prefs}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
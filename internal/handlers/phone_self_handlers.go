@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/mappers"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// RequestPhoneChangeSelfHandler sends a code to the new phone of the user.
+// The phone only changes once the code is confirmed.
+func RequestPhoneChangeSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.PhoneChangeReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		otp, err := services.RequestPhoneChangeService(app, localizer, user, input.Phone)
+		if err != nil {
+			HandlePhoneChangeErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := responses.OTPSentResponse{
+			Phone:     input.Phone,
+			ExpiresAt: otp.ExpiresAt,
+		}
+		err = common.WriteJson(w, http.StatusAccepted, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// VerifyPhoneChangeSelfHandler confirms the code sent to the new phone and
+// moves the user to it. Sessions opened with the old phone are revoked, so
+// the user has to log in again with the new one.
+func VerifyPhoneChangeSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.PhoneChangeVerifyReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		err = services.ChangePhoneService(app, user, input.Phone, input.Code)
+		if err != nil {
+			HandlePhoneChangeErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		user, ok = readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		res := mappers.UserToUserSelfResponse(user)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
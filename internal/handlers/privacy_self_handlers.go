@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// ExportUserDataSelfHandler sends everything stored about the user as a
+// downloadable JSON document, or as a ZIP archive holding that document
+// with ?format=zip.
+func ExportUserDataSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		format := "json"
+		if f := common.ReadQueryStr(r.URL.Query(), "format"); f != nil {
+			format = *f
+		}
+		if format != "json" && format != "zip" {
+			common.BadRequestResponse(app.Logger, localizer, w, r, errors.New("format must be json or zip"))
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		export, err := services.ExportUserDataService(app, user)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		filename := fmt.Sprintf("user-data-%s-%s", user.ID, export.ExportedAt.Format("20060102150405"))
+		envelope := types.Envelope{"export": export}
+
+		if format == "json" {
+			headers := make(http.Header)
+			headers.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+
+			err = common.WriteJson(w, http.StatusOK, envelope, headers)
+			if err != nil {
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		js, err := json.MarshalIndent(envelope, "", "  ")
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+		w.WriteHeader(http.StatusOK)
+
+		// The status line is already sent, so a failure from here on can
+		// only be logged.
+		zw := zip.NewWriter(w)
+		f, err := zw.Create("export.json")
+		if err == nil {
+			_, err = f.Write(js)
+		}
+		if err == nil {
+			err = zw.Close()
+		}
+		if err != nil {
+			app.Logger.Error().Err(err).Msg("failed to write data export archive")
+		}
+	}
+}
+
+func GetErasureSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		request, err := services.GetPendingErasureService(app, user.ID)
+		if err != nil {
+			HandlePrivacyErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"erasure_request": request}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// RequestErasureSelfHandler schedules the erasure of the user's personal
+// data. It is carried out once the cooling-off period has passed and can
+// be cancelled until then.
+func RequestErasureSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := requests.ErasureReq{}
+		err := common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		request, err := services.RequestErasureService(app, user, input.Password)
+		if err != nil {
+			HandlePrivacyErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusAccepted, types.Envelope{"erasure_request": request}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func CancelErasureSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		request, err := services.CancelErasureService(app, user.ID)
+		if err != nil {
+			HandlePrivacyErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"erasure_request": request}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
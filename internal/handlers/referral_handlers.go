@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func GetReferralSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		referral, err := services.GetOrCreateReferralService(app, user.ID)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := &responses.ReferralSelfResponse{
+			Code:           referral.Code,
+			ShareURL:       services.ReferralShareURL(app, referral.Code),
+			RefSignups:     user.RefSignups,
+			TotalReferrals: user.TotalRefferals,
+		}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"referral": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func ListReferralLeaderboardAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters := requests.ReferralLeaderboardFilters{}
+
+		qs := r.URL.Query()
+		filters.Page = common.ReadQueryInt(qs, "page")
+		filters.PageSize = common.ReadQueryInt(qs, "page_size")
+
+		err := app.Validator.Struct(&filters)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		entries, metadata, err := services.ListReferralLeaderboardService(app, &filters)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{
+			"metadata": metadata,
+			"results":  entries,
+		}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/app"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
@@ -41,12 +44,39 @@ func RegisterUserWithPasswordPublicHandler(app *app.Application) http.HandlerFun
 			return
 		}
 
+		// The account stays inactive until the phone number is confirmed
+		// with the otp sent below.
 		user := data.User{
 			Phone:    input.Phone,
 			Password: input.Password,
-			IsActive: true,
+			IsActive: false,
 		}
-		err = services.CreateUserService(app, &user)
+
+		if input.ReferralCode != nil {
+			referral, err := services.GetReferralByCodeService(app, *input.ReferralCode)
+			if err != nil {
+				HandleReferralErrors(app.Logger, localizer, w, r, err)
+				return
+			}
+			user.InvitedByID = &referral.UserID
+			user.InvRefID = &referral.ID
+		}
+
+		if input.ProductReferralCode != nil {
+			referral, err := services.GetProductReferralByCodeService(app, *input.ProductReferralCode)
+			if err != nil {
+				HandleReferralErrors(app.Logger, localizer, w, r, err)
+				return
+			}
+			// A personal referral code, when given too, decides who invited
+			// the user; the product link is still credited for the signup.
+			if user.InvitedByID == nil {
+				user.InvitedByID = &referral.UserID
+			}
+			user.InvProdRefID = &referral.ID
+		}
+
+		err = services.CreateUserService(app, &user, nil)
 
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
@@ -58,7 +88,11 @@ func RegisterUserWithPasswordPublicHandler(app *app.Application) http.HandlerFun
 			return
 		}
 
-		// TODO: Handle referrals in here
+		_, err = services.IssueOTPService(app, localizer, user.Phone, constants.OTPPurposeRegister)
+		if err != nil {
+			HandleOTPErrors(app.Logger, localizer, w, r, err)
+			return
+		}
 
 		headers := make(http.Header)
 		headers.Set("Location", fmt.Sprintf("api/v1/users/%s", user.ID))
@@ -71,3 +105,125 @@ func RegisterUserWithPasswordPublicHandler(app *app.Application) http.HandlerFun
 
 	}
 }
+
+func ResendRegistrationOTPPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.UserSMSLoginReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		res := responses.OTPSentResponse{
+			Phone:     input.Phone,
+			ExpiresAt: time.Now().Add(app.Config.OTP.TTL),
+		}
+
+		user, err := services.GetUserByPhoneService(app, input.Phone)
+		if err != nil && !errors.Is(err, common.ErrRecordNotFound) {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if user != nil && user.PhoneVerifiedAt == nil && !user.IsBanned {
+			otp, err := services.IssueOTPService(app, localizer, user.Phone, constants.OTPPurposeRegister)
+			if err != nil {
+				HandleOTPErrors(app.Logger, localizer, w, r, err)
+				return
+			}
+			res.ExpiresAt = otp.ExpiresAt
+		}
+
+		err = common.WriteJson(w, http.StatusAccepted, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func VerifyRegistrationPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := &requests.UserOTPVerifyReq{}
+		err := common.ReadJSON(w, r, input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		user, err := services.GetUserByPhoneService(app, input.Phone)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				HandleOTPErrors(app.Logger, localizer, w, r, common.ErrOTPInvalid)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		// Registration codes only confirm phones that were never confirmed;
+		// they must not turn deactivated accounts back on.
+		if user.PhoneVerifiedAt != nil {
+			HandleOTPErrors(app.Logger, localizer, w, r, common.ErrOTPInvalid)
+			return
+		}
+
+		err = services.VerifyOTPService(app, input.Phone, constants.OTPPurposeRegister, input.Code)
+		if err != nil {
+			HandleOTPErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = services.ActivateUserService(app, user.ID)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				HandleOTPErrors(app.Logger, localizer, w, r, common.ErrOTPInvalid)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+		user.IsActive = true
+
+		res, err := issueLoginTokens(app, user)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// @Summary Search the catalog
+// @Description Full-text search over product, category and brand names and descriptions, including their translations, tolerant of typos
+// @Tags search
+// @Param Accept-Language header string false "Languages: en, ru, tk"
+// @Param q query string true "Search terms, websearch syntax"
+// @Param kinds query string false "Comma separated kinds: product, category, brand"
+// @Param page query int false "Page"
+// @Param page_size query int false "Page size"
+// @Produce json
+// @Router /api/v1/search [get]
+// @Success 200 {object} types.Envelope
+// @Failure 422 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+func SearchCatalogPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		langCode := common.GetAcceptLanguageHeader(r)
+
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters := requests.CatalogSearchFilters{}
+
+		qs := r.URL.Query()
+		filters.Query = strings.TrimSpace(qs.Get("q"))
+		filters.Kinds = common.ReadQueryCSStrs(qs, "kinds")
+		filters.Page = common.ReadQueryInt(qs, "page")
+		filters.PageSize = common.ReadQueryInt(qs, "page_size")
+
+		err := app.Validator.Struct(&filters)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		hits, metadata, err := services.SearchCatalogService(app, &filters, langCode)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{
+			"metadata": metadata,
+			"results":  hits,
+		}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// @Summary Suggest catalog entries
+// @Description Type-ahead suggestions of products, categories and brands whose name, or a word of it, starts with q, most popular first
+// @Tags search
+// @Param Accept-Language header string false "Languages: en, ru, tk"
+// @Param q query string true "Typed prefix"
+// @Param limit query int false "Number of suggestions, 1 to 20"
+// @Produce json
+// @Router /api/v1/search/suggest [get]
+// @Success 200 {object} types.Envelope
+// @Failure 422 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+func SuggestPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		langCode := common.GetAcceptLanguageHeader(r)
+
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters := requests.SearchSuggestFilters{}
+
+		qs := r.URL.Query()
+		filters.Query = strings.TrimSpace(qs.Get("q"))
+		filters.Limit = common.ReadQueryInt(qs, "limit")
+
+		err := app.Validator.Struct(&filters)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		suggestions, err := services.SuggestService(app, &filters, langCode)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"suggestions": suggestions}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// RegisterSearchClickPublicHandler is called by the storefront when a
+// visitor opens a search result or a suggestion.
+func RegisterSearchClickPublicHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input := requests.SearchClickReq{}
+		err := common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		err = services.RegisterSearchClickService(app, &input)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusNoContent, nil, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func StartTOTPEnrollmentSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		secret, uri, err := services.StartTOTPEnrollmentService(app, user)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := responses.TOTPEnrollmentResponse{
+			Secret:          secret,
+			ProvisioningURI: uri,
+		}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func ConfirmTOTPEnrollmentSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input, ok := readTOTPCodeReq(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		codes, err := services.ConfirmTOTPEnrollmentService(app, user, input.Code)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := responses.RecoveryCodesResponse{RecoveryCodes: codes}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func DisableTOTPSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input, ok := readTOTPCodeReq(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		err := services.DisableTOTPService(app, user, input.Code)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"message": "two-factor authentication successfully disabled"}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func RegenerateRecoveryCodesSelfHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		input, ok := readTOTPCodeReq(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		user, ok := readSelfUser(app, localizer, w, r)
+		if !ok {
+			return
+		}
+
+		err := services.VerifySecondFactorService(app, user, input.Code)
+		if err != nil {
+			HandleMFAErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		codes, err := services.RegenerateRecoveryCodesService(app, user.ID)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := responses.RecoveryCodesResponse{RecoveryCodes: codes}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+// readSelfUser loads the user of the access token. It writes the error
+// response itself and reports whether the handler may continue.
+func readSelfUser(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*data.User, bool) {
+	accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+	user, err := services.GetUserByIDService(app, accessClaims.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+		default:
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+		return nil, false
+	}
+
+	return user, true
+}
+
+func readTOTPCodeReq(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*requests.TOTPCodeReq, bool) {
+	valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+
+	input := &requests.TOTPCodeReq{}
+	err := common.ReadJSON(w, r, input)
+	if err != nil {
+		common.BadRequestResponse(app.Logger, localizer, w, r, err)
+		return nil, false
+	}
+
+	err = app.Validator.Struct(input)
+	if err != nil {
+		errs := err.(validator.ValidationErrors)
+		translatedErrs := make(map[string]string)
+		for _, e := range errs {
+			translatedErrs[e.Field()] = e.Translate(valTrans)
+		}
+		common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+		return nil, false
+	}
+
+	return input, true
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/mappers"
@@ -23,6 +24,7 @@ func CreateTranslationMangerHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		var input requests.TranslationAdminCreate
 		err := common.ReadJSON(w, r, &input)
@@ -31,7 +33,7 @@ func CreateTranslationMangerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		tr := mappers.CreateTranslationInputToTranslationMapper(&input)
+		tr := mappers.CreateTranslationInputToTranslationMapper(&input, accessClaims.UserID)
 		err = app.Validator.Struct(tr)
 		if err != nil {
 			errs := err.(validator.ValidationErrors)
@@ -43,7 +45,7 @@ func CreateTranslationMangerHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.CreateTranslationService(app, tr)
+		err = services.CreateTranslationService(app, tr, newAuditEntry(r, constants.AuditActionCreate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
@@ -90,7 +92,7 @@ func GetTranslationHandler(app *app.Application) http.HandlerFunc {
 		}
 
 		trResponse := mappers.TranslationToTranslationManagerResponseMappper(tr)
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"translation": trResponse}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"translation": trResponse}, common.VersionHeaders(tr.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
@@ -105,6 +107,9 @@ func ListTranslationsHandler(app *app.Application) http.HandlerFunc {
 
 		filters := requests.TranslationsAdminFilters{}
 		readTranslationAdminQueryParams(&filters, r.URL.Query())
+		if !readFieldFilters(app, localizer, w, r, requests.TranslationFields, &filters.FieldFilter) {
+			return
+		}
 
 		err := app.Validator.Struct(filters)
 		if err != nil {
@@ -119,11 +124,16 @@ func ListTranslationsHandler(app *app.Application) http.HandlerFunc {
 
 		trList, metadata, err := services.ListTranslationsService(app, &filters)
 		if err != nil {
-			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			switch {
+			case errors.Is(err, common.ErrInvalidCursor):
+				localizedErrorResponse(app.Logger, localizer, w, r, http.StatusBadRequest, "invalid_cursor")
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
 			return
 		}
 
-		trListRes := make([]*responses.TranslationAdminResponse, len(trList))
+		trListRes := make([]*responses.TranslationAdminResponse, 0, len(trList))
 		for _, tr := range trList {
 			trRes := mappers.TranslationToTranslationManagerResponseMappper(tr)
 			trListRes = append(trListRes, trRes)
@@ -144,6 +154,7 @@ func UpdateTranslationHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -152,7 +163,7 @@ func UpdateTranslationHandler(app *app.Application) http.HandlerFunc {
 		}
 
 		input := requests.TranslationAdminUpdate{}
-		err = common.ReadJSON(w, r, input)
+		err = common.ReadJSON(w, r, &input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
@@ -174,22 +185,32 @@ func UpdateTranslationHandler(app *app.Application) http.HandlerFunc {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
 				common.NotFoundResponse(app.Logger, localizer, w, r)
-				return
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			}
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, tr.Version, mappers.TranslationToTranslationManagerResponseMappper(tr)) {
+			return
 		}
 
-		err = services.UpdateTranslationService(app, &input, tr)
+		err = services.UpdateTranslationService(app, &input, tr, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, err)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"translation": tr}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"translation": tr}, common.VersionHeaders(tr.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -200,6 +221,7 @@ func PartialUpdateTranslationHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -207,45 +229,63 @@ func PartialUpdateTranslationHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		input := requests.TranslationAdminPartialUpdate{}
-		err = common.ReadJSON(w, r, input)
-		if err != nil {
-			common.BadRequestResponse(app.Logger, localizer, w, r, err)
-			return
-		}
-
 		tr, err := services.GetTranslationService(app, id)
 		if err != nil {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
 				common.NotFoundResponse(app.Logger, localizer, w, r)
-				return
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			}
+			return
 		}
 
-		err = app.Validator.Struct(input)
-		if err != nil {
-			errs := err.(validator.ValidationErrors)
-			transErrs := make(map[string]string)
-			for _, e := range errs {
-				transErrs[e.Field()] = e.Translate(valTrans)
-			}
-			common.FailedValidationResponse(app.Logger, w, r, transErrs)
+		if !checkIfMatch(app, localizer, w, r, tr.Version, mappers.TranslationToTranslationManagerResponseMappper(tr)) {
 			return
 		}
 
-		err = services.PartialUpdateTranslationService(app, &input, tr)
+		if common.IsPatchRequest(r) {
+			input := mappers.TranslationToTranslationAdminUpdateMapper(tr)
+			if !readPatchInput(app, valTrans, localizer, w, r, input) {
+				return
+			}
+			err = services.UpdateTranslationService(app, input, tr, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		} else {
+			input := requests.TranslationAdminPartialUpdate{}
+			err = common.ReadJSON(w, r, &input)
+			if err != nil {
+				common.BadRequestResponse(app.Logger, localizer, w, r, err)
+				return
+			}
+
+			err = app.Validator.Struct(input)
+			if err != nil {
+				errs := err.(validator.ValidationErrors)
+				transErrs := make(map[string]string)
+				for _, e := range errs {
+					transErrs[e.Field()] = e.Translate(valTrans)
+				}
+				common.FailedValidationResponse(app.Logger, w, r, transErrs)
+				return
+			}
+
+			err = services.PartialUpdateTranslationService(app, &input, tr, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		}
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, err)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"translation": tr}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"translation": tr}, common.VersionHeaders(tr.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -263,7 +303,22 @@ func DeleteTranslationHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.DeleteTranslationService(app, id)
+		tr, err := services.GetTranslationService(app, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, tr.Version, mappers.TranslationToTranslationManagerResponseMappper(tr)) {
+			return
+		}
+
+		err = services.DeleteTranslationService(app, tr, newAuditEntry(r, constants.AuditActionDelete, nil))
 		if err != nil {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
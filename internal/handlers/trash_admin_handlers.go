@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/mappers"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func ListDeletedCategoriesManagerHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters, ok := readTrashFilters(app, w, r)
+		if !ok {
+			return
+		}
+
+		categories, metadata, err := services.ListDeletedCategoriesService(app, filters)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		results := make([]*responses.CategoryAdminResponse, 0, len(categories))
+		for _, category := range categories {
+			results = append(results, mappers.CategoryToCategoryManagerResponseMapper(category))
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{
+			"metadata": metadata,
+			"results":  results,
+		}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func RestoreCategoryManagerHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		slug, err := common.ReadSlugParam(r)
+		if err != nil {
+			common.NotFoundResponse(app.Logger, localizer, w, r)
+			return
+		}
+
+		category, err := services.RestoreCategoryService(app, slug, accessClaims.UserID, newAuditEntry(r, constants.AuditActionRestore, nil))
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		res := mappers.CategoryToCategoryManagerResponseMapper(category)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"category": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func ListDeletedLanguagesManagerHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters, ok := readTrashFilters(app, w, r)
+		if !ok {
+			return
+		}
+
+		languages, metadata, err := services.ListDeletedLanguagesService(app, filters)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		results := make([]*responses.LanguageAdminResponse, 0, len(languages))
+		for _, language := range languages {
+			results = append(results, mappers.LanguageToLanguageManagerResponseMapper(language))
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{
+			"metadata": metadata,
+			"results":  results,
+		}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func RestoreLanguageManagerHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		language, err := services.RestoreLanguageService(app, id, accessClaims.UserID, newAuditEntry(r, constants.AuditActionRestore, nil))
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.NotFoundResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		res := mappers.LanguageToLanguageManagerResponseMapper(language)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"language": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func ListDeletedUsersAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+
+		filters, ok := readTrashFilters(app, w, r)
+		if !ok {
+			return
+		}
+
+		users, metadata, err := services.ListDeletedUsersService(app, filters)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		results := make([]*responses.UserAdminResponse, 0, len(users))
+		for _, user := range users {
+			results = append(results, mappers.UserToUserAdminResponse(user))
+		}
+
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{
+			"metadata": metadata,
+			"results":  results,
+		}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func RestoreUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		user, err := services.RestoreUserService(app, accessClaims, id, newAuditEntry(r, constants.AuditActionRestore, nil))
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.UserToUserAdminResponse(user)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func readTrashFilters(
+	app *app.Application,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*requests.TrashAdminFilters, bool) {
+	valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+
+	filters := &requests.TrashAdminFilters{}
+
+	qs := r.URL.Query()
+	filters.Page = common.ReadQueryInt(qs, "page")
+	filters.PageSize = common.ReadQueryInt(qs, "page_size")
+
+	err := app.Validator.Struct(filters)
+	if err != nil {
+		errs := err.(validator.ValidationErrors)
+		translatedErrs := make(map[string]string)
+		for _, e := range errs {
+			translatedErrs[e.Field()] = e.Translate(valTrans)
+		}
+		common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+		return nil, false
+	}
+
+	return filters, true
+}
@@ -25,17 +25,18 @@ func CreateUserAdminHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		// TODO: authentication
 
 		var input requests.UserAdminCreate
-		err := common.ReadJSON(w, r, input)
+		err := common.ReadJSON(w, r, &input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
-		user := mappers.UserCreateAdminToUser(&input)
+		user := mappers.UserCreateAdminToUser(&input, accessClaims.UserID)
 		err = app.Validator.Struct(user)
 		if err != nil {
 			errs := err.(validator.ValidationErrors)
@@ -47,7 +48,7 @@ func CreateUserAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.CreateUserService(app, user)
+		err = services.CreateUserService(app, user, newAuditEntry(r, constants.AuditActionCreate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				err = common.TransformPgErrToCustomError(pgErr)
@@ -92,7 +93,7 @@ func GetUsersAdminHandler(app *app.Application) http.HandlerFunc {
 		}
 
 		res := mappers.UserToUserAdminResponse(user)
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, common.VersionHeaders(user.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -106,6 +107,9 @@ func ListUsersAdminHandler(app *app.Application) http.HandlerFunc {
 
 		filters := requests.UsersAdminFilters{}
 		readUserAdminQueryParams(&filters, r.URL.Query())
+		if !readFieldFilters(app, localizer, w, r, requests.UserFields, &filters.FieldFilter) {
+			return
+		}
 
 		err := app.Validator.Struct(filters)
 		if err != nil {
@@ -123,6 +127,8 @@ func ListUsersAdminHandler(app *app.Application) http.HandlerFunc {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
 				common.NotFoundResponse(app.Logger, localizer, w, r)
+			case errors.Is(err, common.ErrInvalidCursor):
+				localizedErrorResponse(app.Logger, localizer, w, r, http.StatusBadRequest, "invalid_cursor")
 			default:
 				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			}
@@ -151,6 +157,7 @@ func UpdateUserAdminHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -159,7 +166,7 @@ func UpdateUserAdminHandler(app *app.Application) http.HandlerFunc {
 		}
 
 		input := requests.UserAdminUpdate{}
-		err = common.ReadJSON(w, r, input)
+		err = common.ReadJSON(w, r, &input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
@@ -187,19 +194,33 @@ func UpdateUserAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.UpdateUsersAdminService(app, &input, user)
+		err = services.CheckUserManageableService(accessClaims, user)
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, user.Version, mappers.UserToUserAdminResponse(user)) {
+			return
+		}
+
+		err = services.UpdateUsersAdminService(app, &input, user, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				e := common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, e)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
 		res := mappers.UserToUserAdminResponse(user)
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, common.VersionHeaders(user.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -211,6 +232,7 @@ func PartialUpdateUserAdminHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -218,24 +240,6 @@ func PartialUpdateUserAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		input := requests.UserAdminPartialUpdate{}
-		err = common.ReadJSON(w, r, input)
-		if err != nil {
-			common.BadRequestResponse(app.Logger, localizer, w, r, err)
-			return
-		}
-
-		err = app.Validator.Struct(input)
-		if err != nil {
-			errs := err.(validator.ValidationErrors)
-			translatedErrs := make(map[string]string)
-			for _, e := range errs {
-				translatedErrs[e.Field()] = e.Translate(valTrans)
-			}
-			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
-			return
-		}
-
 		user, err := services.GetUserByIDService(app, id)
 		if err != nil {
 			switch {
@@ -247,19 +251,59 @@ func PartialUpdateUserAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.PartialUpdateUsersAdminService(app, &input, user)
+		err = services.CheckUserManageableService(accessClaims, user)
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, user.Version, mappers.UserToUserAdminResponse(user)) {
+			return
+		}
+
+		if common.IsPatchRequest(r) {
+			input := mappers.UserToUserAdminUpdate(user)
+			if !readPatchInput(app, valTrans, localizer, w, r, input) {
+				return
+			}
+			err = services.UpdateUsersAdminService(app, input, user, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		} else {
+			input := requests.UserAdminPartialUpdate{}
+			err = common.ReadJSON(w, r, &input)
+			if err != nil {
+				common.BadRequestResponse(app.Logger, localizer, w, r, err)
+				return
+			}
+
+			err = app.Validator.Struct(input)
+			if err != nil {
+				errs := err.(validator.ValidationErrors)
+				translatedErrs := make(map[string]string)
+				for _, e := range errs {
+					translatedErrs[e.Field()] = e.Translate(valTrans)
+				}
+				common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+				return
+			}
+
+			err = services.PartialUpdateUsersAdminService(app, &input, user, accessClaims.UserID, newAuditEntry(r, constants.AuditActionUpdate, nil))
+		}
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				e := common.TransformPgErrToCustomError(pgErr)
 				HandlePGErrors(app.Logger, localizer, w, r, e)
 				return
 			}
+			if errors.Is(err, common.ErrEditConflict) {
+				common.EditConflictResponse(app.Logger, localizer, w, r)
+				return
+			}
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 			return
 		}
 
 		res := mappers.UserToUserAdminResponse(user)
-		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, common.VersionHeaders(user.Version))
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -271,6 +315,7 @@ func DeleteUserAdminHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -278,7 +323,23 @@ func DeleteUserAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.DeleteUserService(app, id)
+		user, err := services.GetUserByIDService(app, id)
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = services.CheckUserManageableService(accessClaims, user)
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		if !checkIfMatch(app, localizer, w, r, user.Version, mappers.UserToUserAdminResponse(user)) {
+			return
+		}
+
+		err = services.DeleteUserService(app, user, newAuditEntry(r, constants.AuditActionDelete, nil))
 		if err != nil {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
@@ -289,6 +350,8 @@ func DeleteUserAdminHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
+		deletedAt := time.Now()
+		user.DeletedAt = &deletedAt
 		err = common.WriteJson(w, http.StatusOK, types.Envelope{"message": "user successfully deleted"}, nil)
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
@@ -296,13 +359,15 @@ func DeleteUserAdminHandler(app *app.Application) http.HandlerFunc {
 	}
 }
 
-// TODO: revoke token when user is banned
+// RenewAccessTokenReqHandler issues a new access token for a refresh token
+// whose session is still valid. It does not need an access token, which
+// has usually expired by the time a client renews it.
 func RenewAccessTokenReqHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
 
-		input := requests.RenewAccessTokenReq{}
+		input := &requests.RenewAccessTokenReq{}
 		err := common.ReadJSON(w, r, input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
@@ -326,13 +391,6 @@ func RenewAccessTokenReqHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
-
-		if accessClaims.Phone != refreshClaims.Phone {
-			common.UnauthorizedResponse(app.Logger, localizer, w, r)
-			return
-		}
-
 		session, err := services.GetSessionByRefreshTokenService(app, input.RefreshToken)
 		if err != nil {
 			switch {
@@ -364,6 +422,25 @@ func RenewAccessTokenReqHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
+		// The new token carries the current account flags, not the ones the
+		// refresh token was issued with.
+		err = services.SyncUserClaimsService(app, refreshClaims)
+		if err != nil {
+			switch {
+			case errors.Is(err, common.ErrRecordNotFound):
+				common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			default:
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			}
+			return
+		}
+
+		if !refreshClaims.IsActive || refreshClaims.IsBanned {
+			app.Logger.Error().Msg("user is not active or banned")
+			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			return
+		}
+
 		accessToken, accessClaims, err := auth.GenerateJWT(
 			refreshClaims.UserID,
 			refreshClaims.Phone,
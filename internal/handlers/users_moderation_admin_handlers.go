@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/mappers"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type moderationFunc func(
+	app *app.Application, actor *auth.UserClaims, id uuid.UUID, entry *data.AuditEntry,
+) (*data.User, error)
+
+func BanUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return moderateUserHandler(app, constants.AuditActionUserBan, services.BanUserService)
+}
+
+func UnbanUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return moderateUserHandler(app, constants.AuditActionUserUnban, services.UnbanUserService)
+}
+
+func TrustUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return moderateUserHandler(app, constants.AuditActionUserTrust, services.TrustUserService)
+}
+
+func UntrustUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return moderateUserHandler(app, constants.AuditActionUserUntrust, services.UntrustUserService)
+}
+
+func PromoteUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return changeUserLevelHandler(app, constants.AuditActionUserPromote, services.PromoteUserService)
+}
+
+func DemoteUserAdminHandler(app *app.Application) http.HandlerFunc {
+	return changeUserLevelHandler(app, constants.AuditActionUserDemote, services.DemoteUserService)
+}
+
+func moderateUserHandler(app *app.Application, action string, moderate moderationFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		input := requests.UserModerationReq{}
+		err = common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		user, err := moderate(app, accessClaims, id, newAuditEntry(r, action, &input.Reason))
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.UserToUserAdminResponse(user)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
+
+func changeUserLevelHandler(
+	app *app.Application,
+	action string,
+	change func(*app.Application, *auth.UserClaims, uuid.UUID, string, *data.AuditEntry) (*data.User, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
+		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
+
+		id, err := common.ReadUUIDParam(r)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		input := requests.UserLevelChangeReq{}
+		err = common.ReadJSON(w, r, &input)
+		if err != nil {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		err = app.Validator.Struct(input)
+		if err != nil {
+			errs := err.(validator.ValidationErrors)
+			translatedErrs := make(map[string]string)
+			for _, e := range errs {
+				translatedErrs[e.Field()] = e.Translate(valTrans)
+			}
+			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+			return
+		}
+
+		user, err := change(app, accessClaims, id, input.Level, newAuditEntry(r, action, &input.Reason))
+		if err != nil {
+			HandleModerationErrors(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := mappers.UserToUserAdminResponse(user)
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"user": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+}
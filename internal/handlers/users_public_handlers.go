@@ -51,7 +51,11 @@ func GetUserPublicHandler(app *app.Application) http.HandlerFunc {
 		res := mappers.UserToUserPublicResponse(user)
 
 		detailResponse := types.NewDetailResponse(res, []*data.Translation{})
-		err = common.WriteDetailJson(w, http.StatusOK, detailResponse, nil)
+		headers := common.ValidatorHeaders(
+			common.EntityETag(user.ID, user.Version, nil),
+			common.LastModified(user.UpdatedAt, nil),
+		)
+		err = common.WriteDetailJson(w, http.StatusOK, detailResponse, headers)
 		if err != nil {
 			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
 		}
@@ -9,8 +9,10 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
 	"github.com/kcharymyrat/e-commerce/internal/mappers"
 	"github.com/kcharymyrat/e-commerce/internal/services"
 	"github.com/kcharymyrat/e-commerce/internal/types"
@@ -51,6 +53,7 @@ func UpdateUserSelfHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -59,7 +62,7 @@ func UpdateUserSelfHandler(app *app.Application) http.HandlerFunc {
 		}
 
 		input := requests.UserSelfUpdate{}
-		err = common.ReadJSON(w, r, input)
+		err = common.ReadJSON(w, r, &input)
 		if err != nil {
 			common.BadRequestResponse(app.Logger, localizer, w, r, err)
 			return
@@ -87,7 +90,7 @@ func UpdateUserSelfHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.UpdateUsersSelfService(app, &input, user)
+		err = services.UpdateUsersSelfService(app, &input, user, accessClaims.UserID, nil)
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				e := common.TransformPgErrToCustomError(pgErr)
@@ -111,6 +114,7 @@ func PartialUpdateUserSelfHandler(app *app.Application) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		valTrans := r.Context().Value(constants.ValTransKey).(ut.Translator)
 		localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
+		accessClaims := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims)
 
 		id, err := common.ReadUUIDParam(r)
 		if err != nil {
@@ -118,24 +122,6 @@ func PartialUpdateUserSelfHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		input := requests.UserSelfPartialUpdate{}
-		err = common.ReadJSON(w, r, input)
-		if err != nil {
-			common.BadRequestResponse(app.Logger, localizer, w, r, err)
-			return
-		}
-
-		err = app.Validator.Struct(input)
-		if err != nil {
-			errs := err.(validator.ValidationErrors)
-			translatedErrs := make(map[string]string)
-			for _, e := range errs {
-				translatedErrs[e.Field()] = e.Translate(valTrans)
-			}
-			common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
-			return
-		}
-
 		user, err := services.GetUserByIDService(app, id)
 		if err != nil {
 			switch {
@@ -147,7 +133,33 @@ func PartialUpdateUserSelfHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.PartialUpdateUsersSelfService(app, &input, user)
+		if common.IsPatchRequest(r) {
+			input := mappers.UserToUserSelfUpdate(user)
+			if !readPatchInput(app, valTrans, localizer, w, r, input) {
+				return
+			}
+			err = services.UpdateUsersSelfService(app, input, user, accessClaims.UserID, nil)
+		} else {
+			input := requests.UserSelfPartialUpdate{}
+			err = common.ReadJSON(w, r, &input)
+			if err != nil {
+				common.BadRequestResponse(app.Logger, localizer, w, r, err)
+				return
+			}
+
+			err = app.Validator.Struct(input)
+			if err != nil {
+				errs := err.(validator.ValidationErrors)
+				translatedErrs := make(map[string]string)
+				for _, e := range errs {
+					translatedErrs[e.Field()] = e.Translate(valTrans)
+				}
+				common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+				return
+			}
+
+			err = services.PartialUpdateUsersSelfService(app, &input, user, accessClaims.UserID, nil)
+		}
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok {
 				e := common.TransformPgErrToCustomError(pgErr)
@@ -178,7 +190,7 @@ func DeleteUserSelfHandler(app *app.Application) http.HandlerFunc {
 			return
 		}
 
-		err = services.DeleteUserService(app, id)
+		err = services.DeleteUserService(app, &data.User{ID: id}, nil)
 		if err != nil {
 			switch {
 			case errors.Is(err, common.ErrRecordNotFound):
@@ -2,11 +2,29 @@ package handlers
 
 import (
 	"errors"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	chiMiddleware "github.com/go-chi/chi/middleware"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+	"github.com/kcharymyrat/e-commerce/internal/patch"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+	"github.com/kcharymyrat/e-commerce/internal/types"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/rs/zerolog"
 )
@@ -77,6 +95,559 @@ func HandlePGErrors(
 	}
 }
 
+func localizedErrorResponse(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	messageId string,
+) {
+	message, e := localizer.Localize(&i18n.LocalizeConfig{
+		MessageID: messageId,
+	})
+
+	if e != nil {
+		common.ErrorResponse(logger, w, r, http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	common.ErrorResponse(logger, w, r, status, message)
+}
+
+// checkIfMatch enforces the If-Match header of an admin write to an entity
+// at version. It answers 428 when the header is required but missing, and
+// 412 with current, the entity as the client should now see it, when the
+// header names another version. It returns whether the write may proceed.
+func checkIfMatch(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	version int,
+	current any,
+) bool {
+	err := common.CheckIfMatch(r, version, app.Config.Concurrency.RequireIfMatch)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, common.ErrPreconditionRequired):
+		localizedErrorResponse(app.Logger, localizer, w, r, http.StatusPreconditionRequired, "precondition_required")
+	default:
+		message, e := localizer.Localize(&i18n.LocalizeConfig{MessageID: "precondition_failed"})
+		if e != nil {
+			common.ErrorResponse(app.Logger, w, r, http.StatusInternalServerError, e.Error())
+			return false
+		}
+
+		err = common.WriteJson(w, http.StatusPreconditionFailed, types.Envelope{
+			"code":    http.StatusPreconditionFailed,
+			"error":   message,
+			"current": current,
+		}, common.VersionHeaders(version))
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+	}
+	return false
+}
+
+// readPatchInput applies the JSON Merge Patch or JSON Patch in the body of
+// r to input, the full update request of the entity as it is stored, and
+// validates the result. A patch that cannot be applied to the entity is a
+// 409. It sends the error response and returns false when either fails.
+func readPatchInput(
+	app *app.Application,
+	valTrans ut.Translator,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	input any,
+) bool {
+	err := common.ReadPatch(w, r, input)
+	if err != nil {
+		if !errors.Is(err, patch.ErrConflict) {
+			common.BadRequestResponse(app.Logger, localizer, w, r, err)
+			return false
+		}
+
+		message, e := localizer.Localize(&i18n.LocalizeConfig{
+			MessageID: "patch_conflict",
+			TemplateData: map[string]interface{}{
+				"details": err.Error(),
+			},
+		})
+		if e != nil {
+			common.ErrorResponse(app.Logger, w, r, http.StatusInternalServerError, e.Error())
+			return false
+		}
+		common.ErrorResponse(app.Logger, w, r, http.StatusConflict, message)
+		return false
+	}
+
+	err = app.Validator.Struct(input)
+	if err != nil {
+		errs := err.(validator.ValidationErrors)
+		translatedErrs := make(map[string]string)
+		for _, e := range errs {
+			translatedErrs[e.Field()] = e.Translate(valTrans)
+		}
+		common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+		return false
+	}
+
+	return true
+}
+
+// readFieldFilters parses the filter[field][op] parameters of r against
+// schema into f. When any of them is rejected it sends a validation error
+// keyed by parameter and returns false.
+func readFieldFilters(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	schema filters.FieldSchema,
+	f *filters.FieldFilter,
+) bool {
+	conditions, errs := filters.ParseFieldFilters(r.URL.Query(), schema)
+	if len(errs) > 0 {
+		translatedErrs := make(map[string]string)
+		for _, e := range errs {
+			message, err := localizer.Localize(&i18n.LocalizeConfig{
+				MessageID: e.Reason,
+				TemplateData: map[string]interface{}{
+					"allowed": strings.Join(e.Allowed, ", "),
+				},
+			})
+			if err != nil {
+				common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+				return false
+			}
+			translatedErrs[e.Param] = message
+		}
+		common.FailedValidationResponse(app.Logger, w, r, translatedErrs)
+		return false
+	}
+
+	f.Conditions = conditions
+	return true
+}
+
+func HandleOTPErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrOTPInvalid):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "otp_invalid")
+	case errors.Is(err, common.ErrOTPAttemptsExceeded):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusTooManyRequests, "otp_attempts_exceeded")
+	case errors.Is(err, common.ErrOTPResendCooldown):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusTooManyRequests, "otp_resend_cooldown")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+// HandlePhoneChangeErrors writes the response for a failed phone change;
+// otp failures are left to HandleOTPErrors.
+func HandlePhoneChangeErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrPhoneUnchanged):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "phone_unchanged")
+	case errors.Is(err, common.ErrPhoneTaken):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusConflict, "phone_taken")
+	default:
+		HandleOTPErrors(logger, localizer, w, r, err)
+	}
+}
+
+func HandlePasswordErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrPasswordMismatch):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "password_mismatch")
+	case errors.Is(err, common.ErrPasswordResetTokenInvalid):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "password_reset_token_invalid")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+// HandleLoginErrors writes the response for a failed password login. Lockouts
+// get 429 with Retry-After, everything else the same 401.
+func HandleLoginErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	retryAfter time.Duration,
+) {
+	switch {
+	case errors.Is(err, common.ErrLoginLocked):
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		localizedErrorResponse(logger, localizer, w, r, http.StatusTooManyRequests, "login_locked")
+	case errors.Is(err, common.ErrInvalidCredentials):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusUnauthorized, "invalid_credentials")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandleMFAErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrTOTPInvalid):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "totp_invalid")
+	case errors.Is(err, common.ErrTOTPAlreadyEnabled):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusConflict, "totp_already_enabled")
+	case errors.Is(err, common.ErrTOTPNotEnabled):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "totp_not_enabled")
+	case errors.Is(err, common.ErrMFAChallengeInvalid):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusUnauthorized, "mfa_challenge_invalid")
+	case errors.Is(err, common.ErrOTPAttemptsExceeded):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusTooManyRequests, "otp_attempts_exceeded")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandleRoleErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	if pgErr, ok := err.(*pgconn.PgError); ok {
+		HandlePGErrors(logger, localizer, w, r, common.TransformPgErrToCustomError(pgErr))
+		return
+	}
+
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound):
+		common.NotFoundResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrEditConflict):
+		common.EditConflictResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrSystemRole):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "role_system_immutable")
+	case errors.Is(err, common.ErrUnknownPermission):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "unknown_permission")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandleReferralErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrReferralCodeInvalid):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "referral_code_invalid")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandleBonusErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	if pgErr, ok := err.(*pgconn.PgError); ok {
+		HandlePGErrors(logger, localizer, w, r, common.TransformPgErrToCustomError(pgErr))
+		return
+	}
+
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound):
+		common.NotFoundResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrInsufficientBonusPoints):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "insufficient_bonus_points")
+	case errors.Is(err, common.ErrOrderAlreadyRedeemed):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusConflict, "order_already_redeemed")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+// HandleCountryErrors handles errors of the country and address book
+// services.
+func HandleCountryErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	if pgErr, ok := err.(*pgconn.PgError); ok {
+		HandlePGErrors(logger, localizer, w, r, common.TransformPgErrToCustomError(pgErr))
+		return
+	}
+
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound):
+		common.NotFoundResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrEditConflict):
+		common.EditConflictResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrUnknownCountry):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "unknown_country")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandleModerationErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	if pgErr, ok := err.(*pgconn.PgError); ok {
+		HandlePGErrors(logger, localizer, w, r, common.TransformPgErrToCustomError(pgErr))
+		return
+	}
+
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound):
+		common.NotFoundResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrEditConflict):
+		common.EditConflictResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrPrivilegeEscalation):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusForbidden, "privilege_escalation")
+	case errors.Is(err, common.ErrSelfModeration):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "self_moderation")
+	case errors.Is(err, common.ErrUserBanned):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "user_banned")
+	case errors.Is(err, common.ErrModerationNoop):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusConflict, "moderation_noop")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandlePrivacyErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrRecordNotFound):
+		common.NotFoundResponse(logger, localizer, w, r)
+	case errors.Is(err, common.ErrPasswordMismatch):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "password_mismatch")
+	case errors.Is(err, common.ErrErasurePending):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusConflict, "erasure_already_requested")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+func HandleEmailVerificationErrors(
+	logger *zerolog.Logger,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	switch {
+	case errors.Is(err, common.ErrEmailMissing):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "email_missing")
+	case errors.Is(err, common.ErrEmailAlreadyVerified):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusConflict, "email_already_verified")
+	case errors.Is(err, common.ErrEmailVerificationInvalid):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusBadRequest, "email_verification_invalid")
+	case errors.Is(err, common.ErrEmailVerificationCooldown):
+		localizedErrorResponse(logger, localizer, w, r, http.StatusTooManyRequests, "email_verification_cooldown")
+	default:
+		common.ServerErrorResponse(logger, localizer, w, r, err)
+	}
+}
+
+// newAuditEntry starts an audit entry for the request: the acting user,
+// the request id set by chiMiddleware.RequestID and the client address
+// resolved by chiMiddleware.RealIP.
+func newAuditEntry(r *http.Request, action string, reason *string) *data.AuditEntry {
+	entry := &data.AuditEntry{
+		Action: action,
+		Reason: reason,
+	}
+
+	if accessClaims, ok := r.Context().Value(types.UserClaimsKey{}).(*auth.UserClaims); ok {
+		entry.ActorID = &accessClaims.UserID
+	}
+
+	if requestID := chiMiddleware.GetReqID(r.Context()); requestID != "" {
+		entry.RequestID = &requestID
+	}
+
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if ip != "" {
+		entry.IP = &ip
+	}
+
+	return entry
+}
+
+// completeLogin answers a login whose first factor has been checked.
+// Accounts with a second factor, or required to have one, only get a
+// challenge token, which VerifyMFAAdminHandler exchanges for tokens. This
+// applies to every login path, so privileged tokens always carry the
+// second factor.
+func completeLogin(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+	user *data.User,
+) {
+	mfaRequired, enroll, err := services.MFAStatusService(app, user)
+	if err != nil {
+		common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		return
+	}
+
+	if mfaRequired {
+		token, expiresAt, err := services.CreateMFAChallengeService(app, user, enroll)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+			return
+		}
+
+		res := responses.MFAChallengeResponse{
+			MFARequired:        true,
+			EnrollmentRequired: enroll,
+			MFAToken:           token,
+			ExpiresAt:          expiresAt,
+		}
+		err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+		if err != nil {
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+		return
+	}
+
+	res, err := issueLoginTokens(app, user)
+	if err != nil {
+		common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		return
+	}
+
+	err = common.WriteJson(w, http.StatusOK, types.Envelope{"result": res}, nil)
+	if err != nil {
+		common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+	}
+}
+
+// issueLoginTokens creates an access/refresh token pair for user and stores
+// the refresh token as a new session.
+func issueLoginTokens(app *app.Application, user *data.User) (*responses.LoginResponse, error) {
+	accessToken, accessClaims, err := auth.GenerateJWT(
+		user.ID,
+		user.Phone,
+		user.FirstName,
+		user.LastName,
+		user.Patronomic,
+		user.IsActive,
+		user.IsBanned,
+		user.IsStaff,
+		user.IsAdmin,
+		user.IsSuperuser,
+		5*time.Minute,
+		app.Config.SecretKey,
+		app.Logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshClaims, err := auth.GenerateJWT(
+		user.ID,
+		user.Phone,
+		user.FirstName,
+		user.LastName,
+		user.Patronomic,
+		user.IsActive,
+		user.IsBanned,
+		user.IsStaff,
+		user.IsAdmin,
+		user.IsSuperuser,
+		48*time.Hour,
+		app.Config.SecretKey,
+		app.Logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session := data.Session{
+		ID:           uuid.MustParse(refreshClaims.RegisteredClaims.ID),
+		UserPhone:    user.Phone,
+		RefreshToken: refreshToken,
+		IsRevoked:    false,
+		ExpiresAt:    refreshClaims.RegisteredClaims.ExpiresAt.Time,
+	}
+	err = services.CreateSessionService(app, &session)
+	if err != nil {
+		app.Logger.Error().Err(err).Msg("failed to create session")
+		return nil, err
+	}
+
+	return &responses.LoginResponse{
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		AccessTokenExpiresAt:  accessClaims.RegisteredClaims.ExpiresAt.Time,
+		RefreshTokenExpiresAt: refreshClaims.RegisteredClaims.ExpiresAt.Time,
+		User: responses.ShortUserResponse{
+			ID:          user.ID,
+			Phone:       user.Phone,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Patronomic:  user.Patronomic,
+			IsActive:    user.IsActive,
+			IsBanned:    user.IsBanned,
+			IsStaff:     user.IsStaff,
+			IsAdmin:     user.IsAdmin,
+			IsSuperuser: user.IsSuperuser,
+		},
+	}, nil
+}
+
 func readCategoryAdminQueryParams(input *requests.CategoriesAdminFilters, qs url.Values) {
 	input.Names = common.ReadQueryCSStrs(qs, "names")
 	input.Slugs = common.ReadQueryCSStrs(qs, "slugs")
@@ -89,9 +660,7 @@ func readCategoryAdminQueryParams(input *requests.CategoriesAdminFilters, qs url
 	input.CreatedByIDs = common.ReadQueryCSUUIDs(qs, "created_by_ids")
 	input.UpdatedByIDs = common.ReadQueryCSUUIDs(qs, "updated_by_ids")
 	input.Sorts = common.ReadQueryCSStrs(qs, "sorts")
-	input.SortSafeList = []string{
-		"id", "name", "created_at", "updated_at", "-id", "-name", "-created_at", "-updated_at",
-	}
+	input.SortRegistry = requests.CategorySorts
 	input.Page = common.ReadQueryInt(qs, "page")
 	input.PageSize = common.ReadQueryInt(qs, "page_size")
 }
@@ -114,11 +683,11 @@ func readTranslationAdminQueryParams(input *requests.TranslationsAdminFilters, q
 	input.CreatedByIDs = common.ReadQueryCSUUIDs(qs, "created_by_ids")
 	input.UpdatedByIDs = common.ReadQueryCSUUIDs(qs, "updated_by_ids")
 	input.Sorts = common.ReadQueryCSStrs(qs, "sorts")
-	input.SortSafeList = []string{
-		"id", "language_code", "entity_id", "table_name", "-id", "-language_code", "-entity_ids", "-table_name",
-	}
+	input.SortRegistry = requests.TranslationSorts
 	input.Page = common.ReadQueryInt(qs, "page")
 	input.PageSize = common.ReadQueryInt(qs, "page_size")
+	input.Cursor = common.ReadQueryStr(qs, "cursor")
+	input.Limit = common.ReadQueryInt(qs, "limit")
 }
 
 func readUserAdminQueryParams(input *requests.UsersAdminFilters, qs url.Values) {
@@ -152,14 +721,9 @@ func readUserAdminQueryParams(input *requests.UsersAdminFilters, qs url.Values)
 	input.CreatedByIDs = common.ReadQueryCSUUIDs(qs, "created_by_ids")
 	input.UpdatedByIDs = common.ReadQueryCSUUIDs(qs, "updated_by_ids")
 	input.Sorts = common.ReadQueryCSStrs(qs, "sorts")
-	input.SortSafeList = []string{
-		"id", "phone", "email", "is_active", "is_banned", "is_trusted", "is_invited",
-		"ref_signups", "prod_ref_bought", "whole_ddp", "ddp", "bonus", "is_staff",
-		"created_at", "updated_at",
-		"-id", "-phone", "-email", "-is_active", "-is_banned", "-is_trusted", "-is_invited",
-		"-ref_signups", "-prod_ref_bought", "-whole_ddp", "-ddp", "-bonus", "-is_staff",
-		"-created_at", "-updated_at",
-	}
+	input.SortRegistry = requests.UserSorts
 	input.Page = common.ReadQueryInt(qs, "page")
 	input.PageSize = common.ReadQueryInt(qs, "page_size")
+	input.Cursor = common.ReadQueryStr(qs, "cursor")
+	input.Limit = common.ReadQueryInt(qs, "limit")
 }
@@ -0,0 +1,163 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers an email.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// render returns msg as an RFC 5322 message. The subject is encoded so that
+// non-ASCII subjects survive every mail server.
+func render(from string, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// SMTPSettings are the connection details of an SMTP relay. Username may be
+// empty for relays that do not require authentication.
+type SMTPSettings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// SMTPMailer sends email through an SMTP relay, upgrading the connection
+// with STARTTLS whenever the server offers it.
+type SMTPMailer struct {
+	From     string
+	Settings SMTPSettings
+}
+
+func NewSMTPMailer(from string, settings SMTPSettings) *SMTPMailer {
+	return &SMTPMailer{From: from, Settings: settings}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.Settings.Host, strconv.Itoa(m.Settings.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.Settings.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: m.Settings.Host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.Settings.Username != "" {
+		auth := smtp.PlainAuth("", m.Settings.Username, m.Settings.Password, m.Settings.Host)
+		err = client.Auth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(m.From)
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(msg.To)
+	if err != nil {
+		return err
+	}
+
+	wc, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = wc.Write(render(m.From, msg))
+	if err != nil {
+		return err
+	}
+	err = wc.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// FileMailer drops every outgoing email into Dir as a separate .eml file so
+// that developers and tests can open it. Intended for local development.
+type FileMailer struct {
+	From string
+	Dir  string
+}
+
+func NewFileMailer(from string, dir string) (*FileMailer, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &FileMailer{From: from, Dir: dir}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	suffix := make([]byte, 4)
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(m.Dir, name), render(m.From, msg), 0o644)
+}
+
+// NewMailer returns the mailer configured by driver ("file" or "smtp").
+func NewMailer(driver string, from string, dropDir string, settings SMTPSettings) (Mailer, error) {
+	switch driver {
+	case "smtp":
+		return NewSMTPMailer(from, settings), nil
+	case "file", "":
+		return NewFileMailer(from, dropDir)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", driver)
+	}
+}
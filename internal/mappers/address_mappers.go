@@ -0,0 +1,106 @@
+package mappers
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func CreateCountryInputToCountryMapper(input *requests.CountryAdminCreate, actorID uuid.UUID) *data.Country {
+	return &data.Country{
+		Code:        strings.ToUpper(input.Code),
+		Name:        input.Name,
+		CreatedByID: actorID,
+		UpdatedByID: actorID,
+	}
+}
+
+func CountryToCountryAdminResponseMapper(input *data.Country) *responses.CountryAdminResponse {
+	return &responses.CountryAdminResponse{
+		ID:          input.ID,
+		Code:        input.Code,
+		Name:        input.Name,
+		CreatedAt:   input.CreatedAt,
+		UpdatedAt:   input.UpdatedAt,
+		CreatedByID: input.CreatedByID,
+		UpdatedByID: input.UpdatedByID,
+		Version:     input.Version,
+	}
+}
+
+func CountryToCountryPublicResponseMapper(input *data.Country) *responses.CountryPublicResponse {
+	return &responses.CountryPublicResponse{
+		Code: input.Code,
+		Name: input.Name,
+	}
+}
+
+func CreateCustomerAddressInputToAddressMapper(
+	input *requests.CustomerAddressCreateReq, customerID, actorID uuid.UUID,
+) *data.CustomerAddress {
+	return &data.CustomerAddress{
+		CustomerID:    customerID,
+		RecipientName: input.RecipientName,
+		CountryCode:   strings.ToUpper(input.CountryCode),
+		Region:        input.Region,
+		City:          input.City,
+		Street:        input.Street,
+		PostalCode:    input.PostalCode,
+		Phone:         input.Phone,
+		IsDefault:     input.IsDefault,
+		CreatedByID:   actorID,
+		UpdatedByID:   actorID,
+	}
+}
+
+func UpdateCustomerAddressInputToAddressMapper(
+	input *requests.CustomerAddressUpdateReq, address *data.CustomerAddress, actorID uuid.UUID,
+) {
+	address.RecipientName = input.RecipientName
+	address.CountryCode = strings.ToUpper(input.CountryCode)
+	address.Region = input.Region
+	address.City = input.City
+	address.Street = input.Street
+	address.PostalCode = input.PostalCode
+	address.Phone = input.Phone
+	address.UpdatedByID = actorID
+}
+
+func CustomerAddressToSelfResponseMapper(input *data.CustomerAddress) *responses.CustomerAddressSelfResponse {
+	return &responses.CustomerAddressSelfResponse{
+		ID:            input.ID,
+		RecipientName: input.RecipientName,
+		CountryCode:   input.CountryCode,
+		CountryName:   input.CountryName,
+		Region:        input.Region,
+		City:          input.City,
+		Street:        input.Street,
+		PostalCode:    input.PostalCode,
+		Phone:         input.Phone,
+		IsDefault:     input.IsDefault,
+		CreatedAt:     input.CreatedAt,
+		UpdatedAt:     input.UpdatedAt,
+		Version:       input.Version,
+	}
+}
+
+func CustomerToProfileSelfResponseMapper(
+	customer *data.Customer, addresses []*data.CustomerAddress,
+) *responses.CustomerProfileSelfResponse {
+	res := &responses.CustomerProfileSelfResponse{
+		ID:        customer.ID,
+		UserID:    customer.UserID,
+		Addresses: make([]*responses.CustomerAddressSelfResponse, 0, len(addresses)),
+	}
+	for _, address := range addresses {
+		addressRes := CustomerAddressToSelfResponseMapper(address)
+		if address.IsDefault {
+			res.DefaultAddress = addressRes
+		}
+		res.Addresses = append(res.Addresses, addressRes)
+	}
+	return res
+}
@@ -1,20 +1,21 @@
 package mappers
 
 import (
+	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 )
 
-func CreateCategoryInputToCategoryMapper(input *requests.CategoryAdminCreate) *data.Category {
+func CreateCategoryInputToCategoryMapper(input *requests.CategoryAdminCreate, createdByID uuid.UUID) *data.Category {
 	return &data.Category{
 		ParentID:    input.ParentID,
 		Name:        input.Name,
 		Slug:        input.Slug,
 		Description: input.Description,
 		ImageUrl:    input.ImageUrl,
-		CreatedByID: input.CreatedByID,
-		UpdatedByID: input.UpdatedByID,
+		CreatedByID: createdByID,
+		UpdatedByID: createdByID,
 	}
 }
 
@@ -43,5 +44,19 @@ func CategoryToCategoryManagerResponseMapper(category *data.Category) *responses
 		UpdatedAt:   category.UpdatedAt,
 		CreatedByID: category.CreatedByID,
 		UpdatedByID: category.UpdatedByID,
+		Version:     category.Version,
+		DeletedAt:   category.DeletedAt,
+	}
+}
+
+// CategoryToCategoryAdminUpdateMapper returns the update request that
+// leaves category as it is, the document PATCH requests are applied to.
+func CategoryToCategoryAdminUpdateMapper(category *data.Category) *requests.CategoryAdminUpdate {
+	return &requests.CategoryAdminUpdate{
+		ParentID:    category.ParentID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ImageUrl:    category.ImageUrl,
 	}
 }
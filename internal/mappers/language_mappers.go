@@ -1,17 +1,18 @@
 package mappers
 
 import (
+	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 )
 
-func CreateLanguageInputToLanguageMapper(input *requests.LanguageAdminCreate) *data.Language {
+func CreateLanguageInputToLanguageMapper(input *requests.LanguageAdminCreate, createdByID uuid.UUID) *data.Language {
 	return &data.Language{
 		Name:        input.Name,
 		Code:        input.Code,
-		CreatedByID: input.CreatedByID,
-		UpdatedByID: input.UpdatedByID,
+		CreatedByID: createdByID,
+		UpdatedByID: createdByID,
 	}
 }
 
@@ -24,6 +25,8 @@ func LanguageToLanguageManagerResponseMapper(input *data.Language) *responses.La
 		UpdatedAt:   input.UpdatedAt,
 		CreatedByID: input.CreatedByID,
 		UpdatedByID: input.UpdatedByID,
+		Version:     input.Version,
+		DeletedAt:   input.DeletedAt,
 	}
 }
 
@@ -34,3 +37,12 @@ func LanguageToLanguagePublicResponseMapper(input *data.Language) *responses.Lan
 		Name: input.Name,
 	}
 }
+
+// LanguageToLanguageAdminUpdateMapper returns the update request that
+// leaves the language as it is, the document PATCH requests are applied to.
+func LanguageToLanguageAdminUpdateMapper(input *data.Language) *requests.LanguageAdminUpdate {
+	return &requests.LanguageAdminUpdate{
+		Code: input.Code,
+		Name: input.Name,
+	}
+}
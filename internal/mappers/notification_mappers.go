@@ -0,0 +1,22 @@
+package mappers
+
+import (
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+// NotificationPreferencesToNotificationPreferencesUpdate returns the update
+// request that leaves prefs as they are, the document PATCH requests are
+// applied to.
+func NotificationPreferencesToNotificationPreferencesUpdate(
+	prefs *data.NotificationPreferences,
+) *requests.NotificationPreferencesUpdate {
+	orderUpdates := prefs.OrderUpdates
+	promotions := prefs.Promotions
+	reviewReplies := prefs.ReviewReplies
+	return &requests.NotificationPreferencesUpdate{
+		OrderUpdates:  &orderUpdates,
+		Promotions:    &promotions,
+		ReviewReplies: &reviewReplies,
+	}
+}
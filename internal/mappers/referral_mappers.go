@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func ProductReferralToSelfResponseMapper(
+	referral *data.ProductReferral, shareURL string,
+) *responses.ProductReferralSelfResponse {
+	return &responses.ProductReferralSelfResponse{
+		ID:        referral.ID,
+		ProductID: referral.ProductID,
+		Code:      referral.Code,
+		ShareURL:  shareURL,
+		Clicks:    referral.Clicks,
+		Signups:   referral.Signups,
+		Purchases: referral.Purchases,
+		CreatedAt: referral.CreatedAt,
+	}
+}
+
+func PaidOrderLinesInputToPaidOrderLinesMapper(input []requests.PaidOrderLineReq) []data.PaidOrderLine {
+	lines := make([]data.PaidOrderLine, 0, len(input))
+	for _, line := range input {
+		lines = append(lines, data.PaidOrderLine{
+			ProductID:    line.ProductID,
+			Quantity:     line.Quantity,
+			ReferralCode: line.ReferralCode,
+		})
+	}
+	return lines
+}
@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/api/responses"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func CreateRoleInputToRoleMapper(input *requests.RoleAdminCreate, createdByID uuid.UUID) *data.Role {
+	return &data.Role{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: input.Permissions,
+		CreatedByID: &createdByID,
+		UpdatedByID: &createdByID,
+	}
+}
+
+func RoleToRoleAdminResponseMapper(role *data.Role) *responses.RoleAdminResponse {
+	return &responses.RoleAdminResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: role.Permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+		CreatedByID: role.CreatedByID,
+		UpdatedByID: role.UpdatedByID,
+		Version:     role.Version,
+	}
+}
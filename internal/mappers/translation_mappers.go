@@ -1,12 +1,13 @@
 package mappers
 
 import (
+	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 )
 
-func CreateTranslationInputToTranslationMapper(input *requests.TranslationAdminCreate) *data.Translation {
+func CreateTranslationInputToTranslationMapper(input *requests.TranslationAdminCreate, createdByID uuid.UUID) *data.Translation {
 	return &data.Translation{
 		LanguageCode:        input.LanguageCode,
 		EntityID:            input.EntityID,
@@ -14,8 +15,8 @@ func CreateTranslationInputToTranslationMapper(input *requests.TranslationAdminC
 		FieldName:           input.FieldName,
 		TranslatedFieldName: input.TranslatedFieldName,
 		TranslatedValue:     input.TranslatedValue,
-		CreatedByID:         input.CreatedByID,
-		UpdatedByID:         input.UpdatedByID,
+		CreatedByID:         createdByID,
+		UpdatedByID:         createdByID,
 	}
 }
 
@@ -35,3 +36,16 @@ func TranslationToTranslationManagerResponseMappper(tr *data.Translation) *respo
 		Version:             tr.Version,
 	}
 }
+
+// TranslationToTranslationAdminUpdateMapper returns the update request
+// that leaves tr as it is, the document PATCH requests are applied to.
+func TranslationToTranslationAdminUpdateMapper(tr *data.Translation) *requests.TranslationAdminUpdate {
+	return &requests.TranslationAdminUpdate{
+		LanguageCode:        tr.LanguageCode,
+		EntityID:            tr.EntityID,
+		TableName:           tr.TableName,
+		FieldName:           tr.FieldName,
+		TranslatedFieldName: tr.TranslatedFieldName,
+		TranslatedValue:     tr.TranslatedValue,
+	}
+}
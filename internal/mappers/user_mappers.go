@@ -1,12 +1,13 @@
 package mappers
 
 import (
+	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/api/responses"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 )
 
-func UserCreateAdminToUser(input *requests.UserAdminCreate) *data.User {
+func UserCreateAdminToUser(input *requests.UserAdminCreate, createdByID uuid.UUID) *data.User {
 	return &data.User{
 		Phone:       input.Phone,
 		Password:    input.Password,
@@ -14,7 +15,8 @@ func UserCreateAdminToUser(input *requests.UserAdminCreate) *data.User {
 		LastName:    input.LastName,
 		Patronomic:  input.Patronomic,
 		Email:       input.Email,
-		CreatedByID: input.CreatedByID,
+		CreatedByID: &createdByID,
+		UpdatedByID: &createdByID,
 	}
 }
 
@@ -27,6 +29,8 @@ func UserToUserAdminResponse(user *data.User) *responses.UserAdminResponse {
 	res.Patronomic = user.Patronomic
 	res.DOB = user.DOB
 	res.Email = user.Email
+	res.EmailVerifiedAt = user.EmailVerifiedAt
+	res.PhoneVerifiedAt = user.PhoneVerifiedAt
 	res.IsActive = user.IsActive
 	res.IsBanned = user.IsBanned
 	res.IsTrusted = user.IsTrusted
@@ -48,6 +52,8 @@ func UserToUserAdminResponse(user *data.User) *responses.UserAdminResponse {
 	res.CreatedByID = user.CreatedByID
 	res.UpdatedByID = user.UpdatedByID
 	res.Version = user.Version
+	res.DeletedAt = user.DeletedAt
+	res.ErasedAt = user.ErasedAt
 
 	return &res
 }
@@ -61,6 +67,8 @@ func UserToUserSelfResponse(user *data.User) *responses.UserSelfResponse {
 	res.Patronomic = user.Patronomic
 	res.DOB = user.DOB
 	res.Email = user.Email
+	res.EmailVerifiedAt = user.EmailVerifiedAt
+	res.PhoneVerifiedAt = user.PhoneVerifiedAt
 	res.IsActive = user.IsActive
 	res.IsBanned = user.IsBanned
 	res.IsTrusted = user.IsTrusted
@@ -97,3 +105,29 @@ func UserToUserPublicResponse(user *data.User) *responses.UserPublicResponse {
 
 	return &res
 }
+
+// UserToUserAdminUpdate returns the update request that leaves user as it
+// is, the document PATCH requests are applied to. The password is left
+// empty, which keeps it unchanged.
+func UserToUserAdminUpdate(user *data.User) *requests.UserAdminUpdate {
+	isActive := user.IsActive
+	return &requests.UserAdminUpdate{
+		Phone:      user.Phone,
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		Patronomic: user.Patronomic,
+		Email:      user.Email,
+		IsActive:   &isActive,
+	}
+}
+
+// UserToUserSelfUpdate returns the update request that leaves user as it
+// is, the document PATCH requests are applied to.
+func UserToUserSelfUpdate(user *data.User) *requests.UserSelfUpdate {
+	return &requests.UserSelfUpdate{
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		Patronomic: user.Patronomic,
+		Email:      user.Email,
+	}
+}
@@ -2,14 +2,17 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/kcharymyrat/e-commerce/internal/app"
 	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/services"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
@@ -19,16 +22,8 @@ func AuthMiddleware(app *app.Application) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
 
-			accessClaims, err := verifyClaimsFromAuthHeader(app, localizer, w, r)
-			if err != nil {
-				app.Logger.Error().Err(err).Msg("Error parsing JWT")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsActive || accessClaims.IsBanned {
-				app.Logger.Error().Err(err).Msg("user is not active or banned")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			accessClaims, ok := authenticate(app, localizer, w, r)
+			if !ok {
 				return
 			}
 
@@ -40,92 +35,36 @@ func AuthMiddleware(app *app.Application) func(next http.Handler) http.Handler {
 	}
 }
 
-func StaffAuthMiddleware(app *app.Application) func(next http.Handler) http.Handler {
+// RequirePermission authenticates the request and lets it through only when
+// the user holds every listed permission through their roles. Superusers
+// bypass the permission check.
+func RequirePermission(app *app.Application, permissions ...string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
 
-			accessClaims, err := verifyClaimsFromAuthHeader(app, localizer, w, r)
-			if err != nil {
-				app.Logger.Error().Err(err).Msg("Error parsing JWT")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsActive || accessClaims.IsBanned {
-				app.Logger.Error().Err(err).Msg("user is not active or banned")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsStaff {
-				app.Logger.Error().Err(err).Msg("user is not staff")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			ctx := context.WithValue(r.Context(), types.UserClaimsKey{}, accessClaims)
-			r = r.WithContext(ctx)
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
-func AdminAuthMiddleware(app *app.Application) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
-
-			accessClaims, err := verifyClaimsFromAuthHeader(app, localizer, w, r)
-			if err != nil {
-				app.Logger.Error().Err(err).Msg("Error parsing JWT")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsActive || accessClaims.IsBanned {
-				app.Logger.Error().Err(err).Msg("user is not active or banned")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsAdmin {
-				app.Logger.Error().Err(err).Msg("user is not admin")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			ctx := context.WithValue(r.Context(), types.UserClaimsKey{}, accessClaims)
-			r = r.WithContext(ctx)
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
-func SuperuserAuthMiddleware(app *app.Application) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
-
-			accessClaims, err := verifyClaimsFromAuthHeader(app, localizer, w, r)
-			if err != nil {
-				app.Logger.Error().Err(err).Msg("Error parsing JWT")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsActive || accessClaims.IsBanned {
-				app.Logger.Error().Err(err).Msg("user is not active or banned")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			accessClaims, ok := authenticate(app, localizer, w, r)
+			if !ok {
 				return
 			}
 
 			if !accessClaims.IsSuperuser {
-				app.Logger.Error().Err(err).Msg("user is not superuser")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
+				granted, err := services.GetUserPermissionsService(app, accessClaims.UserID)
+				if err != nil {
+					common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+					return
+				}
+
+				for _, permission := range permissions {
+					if !slices.Contains(granted, permission) {
+						app.Logger.Warn().
+							Str("user_id", accessClaims.UserID.String()).
+							Str("permission", permission).
+							Msg("permission denied")
+						common.ForbiddenResponse(app.Logger, localizer, w, r)
+						return
+					}
+				}
 			}
 
 			ctx := context.WithValue(r.Context(), types.UserClaimsKey{}, accessClaims)
@@ -141,16 +80,8 @@ func SelfAuthMiddleware(app *app.Application) func(next http.Handler) http.Handl
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
 
-			accessClaims, err := verifyClaimsFromAuthHeader(app, localizer, w, r)
-			if err != nil {
-				app.Logger.Error().Err(err).Msg("Error parsing JWT")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
-				return
-			}
-
-			if !accessClaims.IsActive || accessClaims.IsBanned {
-				app.Logger.Error().Err(err).Msg("user is not active or banned")
-				common.UnauthorizedResponse(app.Logger, localizer, w, r)
+			accessClaims, ok := authenticate(app, localizer, w, r)
+			if !ok {
 				return
 			}
 
@@ -174,13 +105,47 @@ func SelfAuthMiddleware(app *app.Application) func(next http.Handler) http.Handl
 	}
 }
 
-func verifyClaimsFromAuthHeader(
-	app *app.Application, localizer *i18n.Localizer, w http.ResponseWriter, r *http.Request,
-) (*auth.UserClaims, error) {
+// authenticate verifies the access token of r and refreshes its account
+// flags from the database. It sends the error response and returns false
+// when the request must not go on.
+func authenticate(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	w http.ResponseWriter,
+	r *http.Request,
+) (*auth.UserClaims, bool) {
+	accessClaims, err := verifyClaimsFromAuthHeader(app, r)
+	if err != nil {
+		app.Logger.Error().Err(err).Msg("Error parsing JWT")
+		common.UnauthorizedResponse(app.Logger, localizer, w, r)
+		return nil, false
+	}
+
+	err = services.SyncUserClaimsService(app, accessClaims)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			app.Logger.Error().Msg("user no longer exists")
+			common.UnauthorizedResponse(app.Logger, localizer, w, r)
+		default:
+			common.ServerErrorResponse(app.Logger, localizer, w, r, err)
+		}
+		return nil, false
+	}
+
+	if !accessClaims.IsActive || accessClaims.IsBanned {
+		app.Logger.Error().Msg("user is not active or banned")
+		common.UnauthorizedResponse(app.Logger, localizer, w, r)
+		return nil, false
+	}
+
+	return accessClaims, true
+}
+
+func verifyClaimsFromAuthHeader(app *app.Application, r *http.Request) (*auth.UserClaims, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		app.Logger.Warn().Str("url", r.URL.String()).Msg("Missing Authorization header")
-		common.UnauthorizedResponse(app.Logger, localizer, w, r)
 		return nil, fmt.Errorf("missing Authorization header")
 	}
 	access_token := strings.TrimPrefix(authHeader, "Bearer ")
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/services"
+)
+
+// cacheRecorder passes a response through while keeping a copy of it.
+type cacheRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *cacheRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *cacheRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// cachedHeaders are the response headers stored with a cached body.
+var cachedHeaders = []string{"Content-Type", "ETag", "Last-Modified"}
+
+// cacheKey identifies a response by language, path, query and the
+// generations of its tags. The query is re-encoded so that parameter order
+// does not matter.
+func cacheKey(r *http.Request, generations []int64) string {
+	sum := sha256.Sum256([]byte(r.URL.Path + "?" + r.URL.Query().Encode()))
+	key := common.GetAcceptLanguageHeader(r) + ":" + hex.EncodeToString(sum[:])
+	for _, generation := range generations {
+		key += ":" + strconv.FormatInt(generation, 10)
+	}
+	return key
+}
+
+// CacheResponse serves GET requests from the response cache, filling it on
+// a miss with successful responses tagged with tags. The admin services
+// invalidate a tag whenever they change the entities behind it. The tag
+// generations are read before the handler runs, so a response built from
+// data that changed meanwhile is stored under a key that is never read
+// again. Redis errors never fail a request; it is then served uncached.
+func CacheResponse(app *app.Application, tags ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if app.Config.Cache.TTL <= 0 || r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			generations, err := services.GetCacheGenerationsService(app, tags)
+			if err != nil {
+				app.Logger.Error().Err(err).Msg("failed to read response cache")
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := cacheKey(r, generations)
+
+			entry, err := services.GetCachedResponseService(app, key, tags)
+			if err != nil {
+				app.Logger.Error().Err(err).Msg("failed to read response cache")
+			}
+			if entry != nil {
+				for key, values := range entry.Header {
+					w.Header()[key] = values
+				}
+				w.Header().Set("X-Cache", "HIT")
+				w.WriteHeader(entry.Status)
+				w.Write(entry.Body)
+				return
+			}
+
+			w.Header().Set("X-Cache", "MISS")
+			rec := &cacheRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			if rec.status != http.StatusOK {
+				return
+			}
+
+			entry = &data.CacheEntry{
+				Status: rec.status,
+				Header: http.Header{},
+				Body:   rec.body.Bytes(),
+			}
+			for _, key := range cachedHeaders {
+				if value := w.Header().Get(key); value != "" {
+					entry.Header.Set(key, value)
+				}
+			}
+			err = services.CacheResponseService(app, key, entry)
+			if err != nil {
+				app.Logger.Error().Err(err).Msg("failed to write response cache")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/common"
+)
+
+// conditionalRecorder holds a response back until it is known whether the
+// client already has it.
+type conditionalRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *conditionalRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *conditionalRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(b)
+}
+
+// ConditionalGet answers GET requests with 304 Not Modified when the
+// client's If-None-Match or If-Modified-Since shows it has the current
+// representation. Responses without an ETag of their own get a weak one
+// computed from the body. Successful responses are sent with cacheControl
+// as their Cache-Control header; errors never are, so that no cache keeps
+// them.
+func ConditionalGet(cacheControl string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Language")
+
+			rec := &conditionalRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			if rec.status != http.StatusOK {
+				w.WriteHeader(rec.status)
+				w.Write(rec.body.Bytes())
+				return
+			}
+
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				etag = common.WeakETag(rec.body.Bytes())
+				w.Header().Set("ETag", etag)
+			}
+			w.Header().Set("Cache-Control", cacheControl)
+
+			if notModified(r, etag, w.Header().Get("Last-Modified")) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+// notModified evaluates the preconditions of a GET as RFC 9110 orders them:
+// If-Modified-Since only counts when there is no If-None-Match.
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagListMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since.Truncate(time.Second))
+}
+
+// etagListMatches reports whether etag is in a comma separated list of
+// entity tags, comparing weakly as If-None-Match does.
+func etagListMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -67,7 +67,25 @@ func GeneralRateLimiter(app *app.Application) func(http.Handler) http.Handler {
 }
 
 func IPBasedRateLimiter(app *app.Application) func(http.Handler) http.Handler {
+	return ipRateLimiter(app, "rate_limit", redis_rate.PerMinute(100)) // FIXME: store in env
+}
+
+// RouteRateLimiter limits every client IP to perMinute requests to the
+// routes it wraps, on top of the limit IPBasedRateLimiter puts on all
+// routes. It is meant for public routes that write, where the general limit
+// is far too generous. A non-positive perMinute disables the limit.
+func RouteRateLimiter(app *app.Application, name string, perMinute int) func(http.Handler) http.Handler {
+	if perMinute <= 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+	return ipRateLimiter(app, "rate_limit:"+name, redis_rate.PerMinute(perMinute))
+}
 
+// ipRateLimiter limits the requests of every client IP, counted under
+// prefix, to limit.
+func ipRateLimiter(app *app.Application, prefix string, limit redis_rate.Limit) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			localizer := r.Context().Value(constants.LocalizerKey).(*i18n.Localizer)
@@ -81,8 +99,8 @@ func IPBasedRateLimiter(app *app.Application) func(http.Handler) http.Handler {
 				ip = r.RemoteAddr
 			}
 
-			key := fmt.Sprintf("rate_limit:%s", ip)
-			res, err := app.Limiter.Allow(r.Context(), key, redis_rate.PerMinute(100)) // FIXME: store in env
+			key := fmt.Sprintf("%s:%s", prefix, ip)
+			res, err := app.Limiter.Allow(r.Context(), key, limit)
 			if err != nil {
 				app.Logger.Error().
 					Err(err).
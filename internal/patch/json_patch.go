@@ -0,0 +1,268 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies a JSON Patch to doc. The operations are applied in
+// order and the patch fails as a whole if any of them fails.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []operation
+	err = json.Unmarshal(patch, &ops)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+
+	for i, op := range ops {
+		target, err = op.apply(target)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func (op operation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: %q operation without path", ErrMalformed, op.Op)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: %q operation without value", ErrMalformed, op.Op)
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			doc, _, err = remove(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			return add(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, fmt.Errorf("%w: test of %q failed", ErrConflict, *op.Path)
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: %q operation without from", ErrMalformed, op.Op)
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+		if op.Op == "move" {
+			if len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+				return nil, fmt.Errorf("%w: cannot move %q into itself", ErrConflict, *op.From)
+			}
+			doc, value, err = remove(doc, from)
+		} else {
+			value, err = get(doc, from)
+			if err == nil {
+				// Copies must not share maps or slices with the original.
+				var b []byte
+				b, err = json.Marshal(value)
+				if err == nil {
+					value, err = decode(b)
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrMalformed, op.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: invalid path %q", ErrMalformed, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array index token, which must be below max.
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: array index %q out of range", ErrConflict, token)
+	}
+	return i, nil
+}
+
+func notFound(token string) error {
+	return fmt.Errorf("%w: %q does not exist", ErrConflict, token)
+}
+
+func get(doc any, path []string) (any, error) {
+	node := doc
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			v, ok := n[token]
+			if !ok {
+				return nil, notFound(token)
+			}
+			node = v
+		case []any:
+			idx, err := arrayIndex(token, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[idx]
+		default:
+			return nil, notFound(token)
+		}
+	}
+	return node, nil
+}
+
+// add returns node with value added at path. Arrays may grow, so every
+// container on the way is returned and stored back into its parent.
+func add(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token := path[0]
+
+	switch n := node.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, notFound(token)
+		}
+		child, err := add(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+		return n, nil
+
+	case []any:
+		if len(path) == 1 {
+			if token == "-" {
+				return append(n, value), nil
+			}
+			idx, err := arrayIndex(token, len(n)+1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(n, idx, value), nil
+		}
+		idx, err := arrayIndex(token, len(n))
+		if err != nil {
+			return nil, err
+		}
+		child, err := add(n[idx], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = child
+		return n, nil
+
+	default:
+		return nil, notFound(token)
+	}
+}
+
+// remove returns node without the value at path, and that value.
+func remove(node any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, node, nil
+	}
+	token := path[0]
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[token]
+		if !ok {
+			return nil, nil, notFound(token)
+		}
+		if len(path) == 1 {
+			delete(n, token)
+			return n, child, nil
+		}
+		child, removed, err := remove(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[token] = child
+		return n, removed, nil
+
+	case []any:
+		idx, err := arrayIndex(token, len(n))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(path) == 1 {
+			removed := n[idx]
+			return slices.Delete(n, idx, idx+1), removed, nil
+		}
+		child, removed, err := remove(n[idx], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[idx] = child
+		return n, removed, nil
+
+	default:
+		return nil, nil, notFound(token)
+	}
+}
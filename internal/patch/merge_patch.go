@@ -0,0 +1,44 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies a JSON Merge Patch to doc. Members of patch replace
+// those of doc, objects are merged recursively and null removes a member.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch any) any {
+	members, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	result, ok := target.(map[string]any)
+	if !ok {
+		result = map[string]any{}
+	}
+
+	for k, v := range members {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = merge(result[k], v)
+	}
+
+	return result
+}
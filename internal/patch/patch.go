@@ -0,0 +1,99 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch
+// (RFC 6902) documents to JSON documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+var (
+	// ErrMalformed is returned for patches that are not valid documents of
+	// their media type.
+	ErrMalformed = errors.New("malformed patch")
+
+	// ErrConflict is returned for well-formed patches that cannot be
+	// applied to the document, such as a path that does not exist or a
+	// failed test operation.
+	ErrConflict = errors.New("patch cannot be applied")
+)
+
+// Apply applies patch of mediaType to doc and returns the patched document.
+func Apply(mediaType string, doc, patch []byte) ([]byte, error) {
+	switch mediaType {
+	case MergePatchMediaType:
+		return MergePatch(doc, patch)
+	case JSONPatchMediaType:
+		return JSONPatch(doc, patch)
+	default:
+		return nil, fmt.Errorf("%w: unsupported media type %q", ErrMalformed, mediaType)
+	}
+}
+
+// decode parses a single JSON value. Numbers are kept as json.Number so
+// that they survive the round trip exactly.
+func decode(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v any
+	err := dec.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	if dec.Decode(&struct{}{}) != io.EOF {
+		return nil, errors.New("body must only contain a single JSON value")
+	}
+
+	return v, nil
+}
+
+// equal compares decoded JSON values, numbers by value rather than by how
+// they were written.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okA := new(big.Float).SetString(a.String())
+		y, okB := new(big.Float).SetString(b.String())
+		return okA && okB && x.Cmp(y) == 0
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+)
+
+type AuditRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// List returns audit entries matching f, newest first.
+func (r AuditRepository) List(f *requests.AuditLogFilters) ([]*data.AuditEntry, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+	SELECT
+		count(*) OVER(),
+		id, actor_id, action, table_name, entity_id, before, after,
+		reason, request_id, ip, created_at
+	FROM audit_log`)
+
+	filters.Eq(q, "actor_id", f.ActorID)
+	filters.Eq(q, "action", f.Action)
+	filters.Eq(q, "table_name", f.TableName)
+	filters.Eq(q, "entity_id", f.EntityID)
+	filters.Eq(q, "request_id", f.RequestID)
+	filters.Gte(q, "created_at", f.CreatedAtFrom)
+	filters.Lte(q, "created_at", f.CreatedAtUpTo)
+	q.OrderBy("created_at DESC", "id")
+	page, pageSize := f.PaginationFilter.Apply(q)
+
+	query, args := q.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*data.AuditEntry{}
+	for rows.Next() {
+		var entry data.AuditEntry
+		var before, after []byte
+		err := rows.Scan(
+			&totalRecords,
+			&entry.ID,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.TableName,
+			&entry.EntityID,
+			&before,
+			&after,
+			&entry.Reason,
+			&entry.RequestID,
+			&entry.IP,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
+
+	return entries, metadata, nil
+}
+
+// withAudit runs change in a transaction and stores the entry of audit in
+// the same one, so that a change is never committed without its audit
+// entry. A nil audit only runs change.
+func withAudit(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	audit data.AuditFunc,
+	change func(tx pgx.Tx) error,
+) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = change(tx)
+	if err != nil {
+		return err
+	}
+
+	err = recordAuditTx(ctx, tx, audit)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// recordAuditTx stores the entry of audit, if any, inside a transaction
+// owned by the caller.
+func recordAuditTx(ctx context.Context, tx pgx.Tx, audit data.AuditFunc) error {
+	if audit == nil {
+		return nil
+	}
+
+	entry, err := audit()
+	if err != nil {
+		return err
+	}
+
+	return insertAuditEntryTx(ctx, tx, entry)
+}
+
+// insertAuditEntryTx records entry inside a transaction owned by the
+// caller, so that the change and its audit entry commit together.
+func insertAuditEntryTx(ctx context.Context, tx pgx.Tx, entry *data.AuditEntry) error {
+	query := `
+	INSERT INTO audit_log (
+		actor_id, action, table_name, entity_id, before, after, reason, request_id, ip
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id, created_at`
+
+	return tx.QueryRow(
+		ctx,
+		query,
+		entry.ActorID,
+		entry.Action,
+		entry.TableName,
+		entry.EntityID,
+		nullableJSON(entry.Before),
+		nullableJSON(entry.After),
+		entry.Reason,
+		entry.RequestID,
+		entry.IP,
+	).Scan(
+		&entry.ID,
+		&entry.CreatedAt,
+	)
+}
+
+// nullableJSON stores an empty document as NULL rather than as invalid
+// jsonb.
+func nullableJSON(doc []byte) any {
+	if len(doc) == 0 {
+		return nil
+	}
+	return string(doc)
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+type BonusRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// AdjustPoints adds entry.Delta to the user's bonus points and records the
+// entry. A change that would make the balance negative fails with
+// ErrInsufficientBonusPoints.
+func (r BonusRepository) AdjustPoints(entry *data.BonusLedgerEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = adjustPointsTx(ctx, tx, entry)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetDiscount stores percent as the user's dynamic discount and records the
+// change. It reports false and writes nothing when the value is unchanged.
+func (r BonusRepository) SetDiscount(entry *data.BonusLedgerEntry, percent decimal.Decimal) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current decimal.Decimal
+	err = tx.QueryRow(
+		ctx, `SELECT _dynamic_discount_percent FROM users WHERE id = $1 FOR UPDATE`, entry.UserID,
+	).Scan(&current)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return false, common.ErrRecordNotFound
+		default:
+			return false, err
+		}
+	}
+
+	if current.Equal(percent) {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET _dynamic_discount_percent = $1 WHERE id = $2`, percent, entry.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	entry.Kind = constants.BonusKindDiscount
+	entry.Delta = percent.Sub(current)
+	entry.BalanceAfter = percent
+	err = insertLedgerEntryTx(ctx, tx, entry)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func (r BonusRepository) ListByUserID(
+	userID uuid.UUID, f *requests.BonusHistoryFilters,
+) ([]*data.BonusLedgerEntry, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+	SELECT
+		count(*) OVER(),
+		id, user_id, kind, delta, balance_after, reason, note,
+		order_id, referral_id, product_referral_id, actor_id, created_at
+	FROM bonus_ledger_entries`)
+
+	q.Where("user_id = ?", userID)
+	q.OrderBy("created_at DESC", "id")
+	page, pageSize := f.PaginationFilter.Apply(q)
+
+	query, args := q.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*data.BonusLedgerEntry{}
+	for rows.Next() {
+		var entry data.BonusLedgerEntry
+		err := rows.Scan(
+			&totalRecords,
+			&entry.ID,
+			&entry.UserID,
+			&entry.Kind,
+			&entry.Delta,
+			&entry.BalanceAfter,
+			&entry.Reason,
+			&entry.Note,
+			&entry.OrderID,
+			&entry.ReferralID,
+			&entry.ProductReferralID,
+			&entry.ActorID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
+
+	return entries, metadata, nil
+}
+
+// adjustPointsTx is AdjustPoints inside a transaction owned by the caller,
+// so that other repositories can award points atomically with their own
+// writes.
+func adjustPointsTx(ctx context.Context, tx pgx.Tx, entry *data.BonusLedgerEntry) error {
+	var balance decimal.Decimal
+	err := tx.QueryRow(
+		ctx, `SELECT bonus_points FROM users WHERE id = $1 FOR UPDATE`, entry.UserID,
+	).Scan(&balance)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	balance = balance.Add(entry.Delta)
+	if balance.IsNegative() {
+		return common.ErrInsufficientBonusPoints
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET bonus_points = $1 WHERE id = $2`, balance, entry.UserID)
+	if err != nil {
+		return err
+	}
+
+	entry.Kind = constants.BonusKindPoints
+	entry.BalanceAfter = balance
+	return insertLedgerEntryTx(ctx, tx, entry)
+}
+
+func insertLedgerEntryTx(ctx context.Context, tx pgx.Tx, entry *data.BonusLedgerEntry) error {
+	query := `
+	INSERT INTO bonus_ledger_entries (
+		user_id, kind, delta, balance_after, reason, note,
+		order_id, referral_id, product_referral_id, actor_id
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	RETURNING id, created_at`
+
+	return tx.QueryRow(
+		ctx,
+		query,
+		entry.UserID,
+		entry.Kind,
+		entry.Delta,
+		entry.BalanceAfter,
+		entry.Reason,
+		entry.Note,
+		entry.OrderID,
+		entry.ReferralID,
+		entry.ProductReferralID,
+		entry.ActorID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
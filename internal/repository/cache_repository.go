@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheRepository stores cached responses in Redis. Every tag has a
+// generation counter that is part of the keys of the entries depending on
+// it; invalidating a tag bumps its generation, so older entries are never
+// read again and simply expire.
+type CacheRepository struct {
+	RDB *redis.Client
+}
+
+// cacheMetrics counts hits and misses in total and per tag. It is published
+// through expvar as well as CacheRepository.Stats.
+var cacheMetrics = expvar.NewMap("http_cache")
+
+func cacheEntryKey(key string) string {
+	return fmt.Sprintf("http_cache:%s", key)
+}
+
+func cacheGenerationKey(tag string) string {
+	return fmt.Sprintf("http_cache_gen:%s", tag)
+}
+
+func countCache(event string, tags []string) {
+	cacheMetrics.Add(event, 1)
+	for _, tag := range tags {
+		cacheMetrics.Add(tag+"."+event, 1)
+	}
+}
+
+// Get returns the entry stored under key, nil if there is none. tags only
+// attribute the hit or miss in the metrics.
+func (r CacheRepository) Get(key string, tags []string) (*data.CacheEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := r.RDB.Get(ctx, cacheEntryKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			countCache("misses", tags)
+			return nil, nil
+		}
+		countCache("errors", nil)
+		return nil, err
+	}
+
+	var entry data.CacheEntry
+	err = json.Unmarshal(value, &entry)
+	if err != nil {
+		countCache("errors", nil)
+		return nil, err
+	}
+
+	countCache("hits", tags)
+	return &entry, nil
+}
+
+// Generations returns the current generation of every tag, 0 for a tag
+// that was never invalidated.
+func (r CacheRepository) Generations(tags []string) ([]int64, error) {
+	generations := make([]int64, len(tags))
+	if len(tags) == 0 {
+		return generations, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = cacheGenerationKey(tag)
+	}
+
+	values, err := r.RDB.MGet(ctx, keys...).Result()
+	if err != nil {
+		countCache("errors", nil)
+		return nil, err
+	}
+
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		generations[i], err = strconv.ParseInt(value.(string), 10, 64)
+		if err != nil {
+			countCache("errors", nil)
+			return nil, err
+		}
+	}
+
+	return generations, nil
+}
+
+// Set stores entry under key for ttl.
+func (r CacheRepository) Set(key string, entry *data.CacheEntry, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	err = r.RDB.Set(ctx, cacheEntryKey(key), value, ttl).Err()
+	if err != nil {
+		countCache("errors", nil)
+		return err
+	}
+
+	return nil
+}
+
+// Invalidate bumps the generation of every tag. Entries stored under an
+// older generation, including ones written by requests that were already
+// running, are no longer looked up.
+func (r CacheRepository) Invalidate(tags ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipe := r.RDB.TxPipeline()
+	for _, tag := range tags {
+		pipe.Incr(ctx, cacheGenerationKey(tag))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		countCache("errors", nil)
+		return err
+	}
+
+	for _, tag := range tags {
+		countCache("invalidations", []string{tag})
+	}
+
+	return nil
+}
+
+// Stats returns the cache counters of this process.
+func (r CacheRepository) Stats() map[string]int64 {
+	stats := map[string]int64{}
+	cacheMetrics.Do(func(kv expvar.KeyValue) {
+		if v, ok := kv.Value.(*expvar.Int); ok {
+			stats[kv.Key] = v.Value()
+		}
+	})
+	return stats
+}
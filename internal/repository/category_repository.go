@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,7 +19,7 @@ type CategoryRepository struct {
 	DBPOOL *pgxpool.Pool
 }
 
-func (r CategoryRepository) Create(category *data.Category) error {
+func (r CategoryRepository) Create(category *data.Category, audit data.AuditFunc) error {
 	query := `
 	INSERT INTO categories (
 		parent_id, 
@@ -45,20 +44,24 @@ func (r CategoryRepository) Create(category *data.Category) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	return r.DBPOOL.QueryRow(ctx, query, args...).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Slug,
-		&category.CreatedAt,
-		&category.Version,
-	)
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args...).Scan(
+			&category.ID,
+			&category.Name,
+			&category.Slug,
+			&category.CreatedAt,
+			&category.Version,
+		)
+	})
 }
 
 func (r CategoryRepository) GetByID(id uuid.UUID) (*data.Category, error) {
 	query := `
-	SELECT * 
+	SELECT
+		id, parent_id, name, slug, description, image_url, created_at, updated_at,
+		created_by_id, updated_by_id, version
 	FROM categories
-	WHERE id = $1
+	WHERE id = $1 AND deleted_at IS NULL
 `
 	var category data.Category
 
@@ -70,6 +73,7 @@ func (r CategoryRepository) GetByID(id uuid.UUID) (*data.Category, error) {
 		&category.ParentID,
 		&category.Name,
 		&category.Slug,
+		&category.Description,
 		&category.ImageUrl,
 		&category.CreatedAt,
 		&category.UpdatedAt,
@@ -91,9 +95,11 @@ func (r CategoryRepository) GetByID(id uuid.UUID) (*data.Category, error) {
 
 func (r CategoryRepository) GetBySlug(slug string) (*data.Category, error) {
 	query := `
-	SELECT * 
+	SELECT
+		id, parent_id, name, slug, description, image_url, created_at, updated_at,
+		created_by_id, updated_by_id, version
 	FROM categories
-	WHERE slug = $1
+	WHERE slug = $1 AND deleted_at IS NULL
 `
 	var category data.Category
 
@@ -105,6 +111,7 @@ func (r CategoryRepository) GetBySlug(slug string) (*data.Category, error) {
 		&category.ParentID,
 		&category.Name,
 		&category.Slug,
+		&category.Description,
 		&category.ImageUrl,
 		&category.CreatedAt,
 		&category.UpdatedAt,
@@ -125,8 +132,8 @@ func (r CategoryRepository) GetBySlug(slug string) (*data.Category, error) {
 }
 
 func (r CategoryRepository) List(f *requests.CategoriesAdminFilters) ([]*data.Category, types.PaginationMetadata, error) {
-	query := `
-		SELECT (
+	q := filters.NewQuery(`
+		SELECT
 			count(*) OVER(),
 			id, 
 			name, 
@@ -139,42 +146,23 @@ func (r CategoryRepository) List(f *requests.CategoriesAdminFilters) ([]*data.Ca
 			created_by_id, 
 			updated_by_id,
 			version
-		)
-		FROM categories
-		WHERE 1=1
-	`
-
-	args := []interface{}{}
-	argCounter := 1
-
-	if len(f.Names) > 0 {
-		query += fmt.Sprintf(" AND name = ANY($%d)", argCounter)
-		args = append(args, f.Names)
-		argCounter++
-	}
-
-	if len(f.Slugs) > 0 {
-		query += fmt.Sprintf(" AND LOWER(slug) = ANY($%d)", argCounter)
-		args = append(args, f.Slugs)
-		argCounter++
-	}
-
-	if len(f.ParentIDs) > 0 {
-		query += fmt.Sprintf(" AND parent_id = ANY($%d)", argCounter)
-		args = append(args, f.ParentIDs)
-		argCounter++
-	}
+		FROM categories`)
 
+	q.Where("deleted_at IS NULL")
+	filters.AnyOf(q, "name", f.Names)
+	filters.AnyOf(q, "LOWER(slug)", f.Slugs)
+	filters.AnyOf(q, "parent_id", f.ParentIDs)
 	if f.Search != nil {
-		query += fmt.Sprintf(" AND to_tsvector('simple', name) @@ plainto_tsquery('simple', $%d)", argCounter)
-		args = append(args, *f.Search)
-		argCounter++
+		q.Where("search_vector @@ plainto_tsquery('simple', ?)", *f.Search)
 	}
 
-	filters.AddCreatedUpdateAtFilterToSQL(&f.CreatedUpdatedAtFilter, &query, &argCounter, args)
-	filters.AddCreatedUpdateByFilterToSQL(&f.CreatedUpdatedByFilter, &query, &argCounter, args)
-	filters.AddSortListFilterToSQL(&f.SortListFilter, &query)
-	filters.AddPaginationFilterToSQL(&f.PaginationFilter, &query, &argCounter, args)
+	f.CreatedUpdatedAtFilter.Apply(q)
+	f.CreatedUpdatedByFilter.Apply(q)
+	f.FieldFilter.Apply(q)
+	f.SortListFilter.Apply(q)
+	page, pageSize := f.PaginationFilter.Apply(q)
+
+	query, args := q.Build()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -214,24 +202,24 @@ func (r CategoryRepository) List(f *requests.CategoriesAdminFilters) ([]*data.Ca
 		return nil, types.PaginationMetadata{}, err
 	}
 
-	metadata := common.CalculateMetadata(totalRecords, *f.Page, *f.PageSize)
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
 
 	return categories, metadata, nil
 }
 
-func (r CategoryRepository) Update(category *data.Category) error {
+func (r CategoryRepository) Update(category *data.Category, audit data.AuditFunc) error {
 	query := `
 	UPDATE categories
 	SET 
 		parent_id = $1,
 		name = $2,
 		slug = $3,
-		description = $4
+		description = $4,
 		image_url = $5,
 		updated_by_id = $6,
 		version = version + 1
-	WHERE id = $7 AND version = $8
-	RETURNING id, parent_id, name, slug, description, image_url, updated_by_id, version
+	WHERE id = $7 AND version = $8 AND deleted_at IS NULL
+	RETURNING id, parent_id, name, slug, description, image_url, updated_at, updated_by_id, version
 `
 
 	args := []interface{}{
@@ -241,76 +229,214 @@ func (r CategoryRepository) Update(category *data.Category) error {
 		category.Description,
 		category.ImageUrl,
 		category.UpdatedByID,
+		category.ID,
 		category.Version,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	err := r.DBPOOL.QueryRow(ctx, query, args...).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Slug,
-		&category.Description,
-		&category.ImageUrl,
-		&category.UpdatedByID,
-		&category.Version,
-	)
-
-	if err != nil {
-		switch {
-		case errors.Is(err, pgx.ErrNoRows):
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, args...).Scan(
+			&category.ID,
+			&category.ParentID,
+			&category.Name,
+			&category.Slug,
+			&category.Description,
+			&category.ImageUrl,
+			&category.UpdatedAt,
+			&category.UpdatedByID,
+			&category.Version,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
 			return common.ErrEditConflict
-		default:
+		}
+		return err
+	})
+}
+
+// DeleteByID moves the category to the trash. It is removed for good by
+// Purge once the retention period has passed.
+func (r CategoryRepository) DeleteByID(id uuid.UUID, audit data.AuditFunc) error {
+	query := `
+	UPDATE categories
+	SET deleted_at = NOW(), version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, id)
+		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		if result.RowsAffected() < 1 {
+			return common.ErrRecordNotFound
+		}
+
+		return nil
+	})
 }
 
-func (r CategoryRepository) DeleteByID(id uuid.UUID) error {
+func (r CategoryRepository) DeleteBySlug(slug string, audit data.AuditFunc) error {
 	query := `
-	DELETE FROM categories
-	WHERE id = $1
+	UPDATE categories
+	SET deleted_at = NOW(), version = version + 1
+	WHERE slug = $1 AND deleted_at IS NULL
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	result, err := r.DBPOOL.Exec(ctx, query, id)
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, slug)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() < 1 {
+			return common.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// ListDeleted lists the categories in the trash, most recently deleted first.
+func (r CategoryRepository) ListDeleted(f *filters.PaginationFilter) ([]*data.Category, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+	SELECT
+		count(*) OVER(),
+		id, parent_id, name, slug, description, image_url, created_at, updated_at,
+		created_by_id, updated_by_id, version, deleted_at
+	FROM categories`)
+
+	q.Where("deleted_at IS NOT NULL")
+	q.OrderBy("deleted_at DESC", "id")
+	page, pageSize := f.Apply(q)
+
+	query, args := q.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
 	if err != nil {
-		return err
+		return nil, types.PaginationMetadata{}, err
 	}
+	defer rows.Close()
 
-	rowsAffected := result.RowsAffected()
+	totalRecords := 0
+	categories := []*data.Category{}
+
+	for rows.Next() {
+		var category data.Category
+		err := rows.Scan(
+			&totalRecords,
+			&category.ID,
+			&category.ParentID,
+			&category.Name,
+			&category.Slug,
+			&category.Description,
+			&category.ImageUrl,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+			&category.CreatedByID,
+			&category.UpdatedByID,
+			&category.Version,
+			&category.DeletedAt,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		categories = append(categories, &category)
+	}
 
-	if rowsAffected < 1 {
-		return common.ErrRecordNotFound
+	if err = rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
 	}
 
-	return nil
+	return categories, common.CalculateMetadata(totalRecords, page, pageSize), nil
 }
 
-func (r CategoryRepository) DeleteBySlug(slug string) error {
+// Restore takes the category with the slug of category out of the trash,
+// as restored by its UpdatedByID, and loads the restored category into it.
+func (r CategoryRepository) Restore(category *data.Category, audit data.AuditFunc) error {
 	query := `
-	DELETE FROM categories
-	WHERE slug = $1
+	UPDATE categories
+	SET deleted_at = NULL, updated_by_id = $2, version = version + 1
+	WHERE slug = $1 AND deleted_at IS NOT NULL
+	RETURNING
+		id, parent_id, name, slug, description, image_url, created_at, updated_at,
+		created_by_id, updated_by_id, version
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	result, err := r.DBPOOL.Exec(ctx, query, slug)
-	if err != nil {
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, category.Slug, category.UpdatedByID).Scan(
+			&category.ID,
+			&category.ParentID,
+			&category.Name,
+			&category.Slug,
+			&category.Description,
+			&category.ImageUrl,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+			&category.CreatedByID,
+			&category.UpdatedByID,
+			&category.Version,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return common.ErrRecordNotFound
+		}
 		return err
+	})
+}
+
+// Purge removes the categories deleted before the cutoff together with their
+// translations. A category that still has subcategories stays in the trash
+// until its children are gone.
+func (r CategoryRepository) Purge(cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return 0, err
 	}
+	defer tx.Rollback(ctx)
 
-	rowsAffected := result.RowsAffected()
+	rows, err := tx.Query(ctx, `
+	DELETE FROM categories AS c
+	WHERE c.deleted_at < $1
+		AND NOT EXISTS (SELECT 1 FROM categories AS child WHERE child.parent_id = c.id)
+	RETURNING c.id`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return 0, err
+	}
 
-	if rowsAffected < 1 {
-		return common.ErrRecordNotFound
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`DELETE FROM translations WHERE table_name = 'categories' AND entity_id = ANY($1)`,
+		ids,
+	)
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return int64(len(ids)), tx.Commit(ctx)
 }
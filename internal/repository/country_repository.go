@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+type CountryRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+func (r CountryRepository) Create(country *data.Country) error {
+	query := `
+	INSERT INTO countries (code, name, created_by_id, updated_by_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.DBPOOL.QueryRow(
+		ctx, query, country.Code, country.Name, country.CreatedByID, country.UpdatedByID,
+	).Scan(
+		&country.ID,
+		&country.CreatedAt,
+		&country.UpdatedAt,
+		&country.Version,
+	)
+}
+
+func (r CountryRepository) GetByCode(code string) (*data.Country, error) {
+	query := `
+	SELECT id, code, name, created_at, updated_at, created_by_id, updated_by_id, version
+	FROM countries
+	WHERE code = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var country data.Country
+	err := r.DBPOOL.QueryRow(ctx, query, code).Scan(
+		&country.ID,
+		&country.Code,
+		&country.Name,
+		&country.CreatedAt,
+		&country.UpdatedAt,
+		&country.CreatedByID,
+		&country.UpdatedByID,
+		&country.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &country, nil
+}
+
+// List returns every country ordered by name. The table is small enough
+// that it is not paginated.
+func (r CountryRepository) List() ([]*data.Country, error) {
+	query := `
+	SELECT id, code, name, created_at, updated_at, created_by_id, updated_by_id, version
+	FROM countries
+	ORDER BY name, code`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	countries := []*data.Country{}
+	for rows.Next() {
+		var country data.Country
+		err := rows.Scan(
+			&country.ID,
+			&country.Code,
+			&country.Name,
+			&country.CreatedAt,
+			&country.UpdatedAt,
+			&country.CreatedByID,
+			&country.UpdatedByID,
+			&country.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		countries = append(countries, &country)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return countries, nil
+}
+
+// Update renames the country. The code is the key addresses and products
+// point at, so it cannot be changed.
+func (r CountryRepository) Update(country *data.Country) error {
+	query := `
+	UPDATE countries
+	SET name = $1, updated_by_id = $2, version = version + 1
+	WHERE id = $3 AND version = $4
+	RETURNING updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.DBPOOL.QueryRow(
+		ctx, query, country.Name, country.UpdatedByID, country.ID, country.Version,
+	).Scan(
+		&country.UpdatedAt,
+		&country.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r CountryRepository) DeleteByCode(code string) error {
+	query := `DELETE FROM countries WHERE code = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, code)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return common.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,397 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+type CustomerRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+const customerAddressColumns = `
+	a.id, a.customer_id, a.recipient_name, a.country_code, c.name, a.region,
+	a.city, a.street, a.postal_code, a.phone, a.is_default,
+	a.created_at, a.updated_at, a.created_by_id, a.updated_by_id, a.version`
+
+// GetOrCreateByUserID returns the customer profile of the user, creating
+// it on first use.
+func (r CustomerRepository) GetOrCreateByUserID(userID uuid.UUID) (*data.Customer, error) {
+	query := `
+	WITH inserted AS (
+		INSERT INTO customers (user_id, created_by_id, updated_by_id)
+		VALUES ($1, $1, $1)
+		ON CONFLICT (user_id) DO NOTHING
+		RETURNING id, user_id, created_at, updated_at, created_by_id, updated_by_id, version
+	)
+	SELECT id, user_id, created_at, updated_at, created_by_id, updated_by_id, version
+	FROM inserted
+	UNION ALL
+	SELECT id, user_id, created_at, updated_at, created_by_id, updated_by_id, version
+	FROM customers
+	WHERE user_id = $1
+	LIMIT 1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var customer data.Customer
+	err := r.DBPOOL.QueryRow(ctx, query, userID).Scan(
+		&customer.ID,
+		&customer.UserID,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+		&customer.CreatedByID,
+		&customer.UpdatedByID,
+		&customer.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// CreateAddress saves a new address. The first address of a customer
+// always becomes the default one.
+func (r CustomerRepository) CreateAddress(address *data.CustomerAddress) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = lockCustomerTx(ctx, tx, address.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	if !address.IsDefault {
+		err = tx.QueryRow(
+			ctx,
+			`SELECT NOT EXISTS (SELECT 1 FROM customer_addresses WHERE customer_id = $1)`,
+			address.CustomerID,
+		).Scan(&address.IsDefault)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = clearDefaultAddressTx(ctx, tx, address.CustomerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+	INSERT INTO customer_addresses (
+		customer_id, recipient_name, country_code, region, city, street,
+		postal_code, phone, is_default, created_by_id, updated_by_id
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	RETURNING id, created_at, updated_at, version`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		address.CustomerID,
+		address.RecipientName,
+		address.CountryCode,
+		address.Region,
+		address.City,
+		address.Street,
+		address.PostalCode,
+		address.Phone,
+		address.IsDefault,
+		address.CreatedByID,
+		address.UpdatedByID,
+	).Scan(
+		&address.ID,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+		&address.Version,
+	)
+	if err != nil {
+		return addressWriteError(err)
+	}
+
+	err = tx.QueryRow(
+		ctx, `SELECT name FROM countries WHERE code = $1`, address.CountryCode,
+	).Scan(&address.CountryName)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetAddress returns the address only if it belongs to the customer, so
+// one customer cannot read another's address by guessing its id.
+func (r CustomerRepository) GetAddress(customerID, id uuid.UUID) (*data.CustomerAddress, error) {
+	query := `
+	SELECT ` + customerAddressColumns + `
+	FROM customer_addresses a
+	JOIN countries c ON c.code = a.country_code
+	WHERE a.id = $1 AND a.customer_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	address, err := scanCustomerAddress(r.DBPOOL.QueryRow(ctx, query, id, customerID))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return address, nil
+}
+
+// ListAddresses returns the customer's addresses, the default one first.
+func (r CustomerRepository) ListAddresses(customerID uuid.UUID) ([]*data.CustomerAddress, error) {
+	query := `
+	SELECT ` + customerAddressColumns + `
+	FROM customer_addresses a
+	JOIN countries c ON c.code = a.country_code
+	WHERE a.customer_id = $1
+	ORDER BY a.is_default DESC, a.created_at DESC, a.id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []*data.CustomerAddress{}
+	for rows.Next() {
+		address, err := scanCustomerAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// UpdateAddress writes the address fields. Whether the address is the
+// default one is changed with SetDefaultAddress only.
+func (r CustomerRepository) UpdateAddress(address *data.CustomerAddress) error {
+	query := `
+	UPDATE customer_addresses
+	SET recipient_name = $1, country_code = $2, region = $3, city = $4, street = $5,
+		postal_code = $6, phone = $7, updated_by_id = $8, version = version + 1
+	WHERE id = $9 AND customer_id = $10 AND version = $11
+	RETURNING updated_at, version,
+		(SELECT name FROM countries WHERE code = $2)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.DBPOOL.QueryRow(
+		ctx,
+		query,
+		address.RecipientName,
+		address.CountryCode,
+		address.Region,
+		address.City,
+		address.Street,
+		address.PostalCode,
+		address.Phone,
+		address.UpdatedByID,
+		address.ID,
+		address.CustomerID,
+		address.Version,
+	).Scan(
+		&address.UpdatedAt,
+		&address.Version,
+		&address.CountryName,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrEditConflict
+		default:
+			return addressWriteError(err)
+		}
+	}
+
+	return nil
+}
+
+// SetDefaultAddress makes the address the customer's default one.
+func (r CustomerRepository) SetDefaultAddress(customerID, id, updatedByID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = lockCustomerTx(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRow(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM customer_addresses WHERE id = $1 AND customer_id = $2)`,
+		id, customerID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return common.ErrRecordNotFound
+	}
+
+	err = clearDefaultAddressTx(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+	UPDATE customer_addresses
+	SET is_default = TRUE, updated_by_id = $1, version = version + 1
+	WHERE id = $2`, updatedByID, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteAddress removes the address. When it was the default one, the
+// most recently created remaining address becomes the default.
+func (r CustomerRepository) DeleteAddress(customerID, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = lockCustomerTx(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+
+	var wasDefault bool
+	err = tx.QueryRow(
+		ctx,
+		`DELETE FROM customer_addresses WHERE id = $1 AND customer_id = $2 RETURNING is_default`,
+		id, customerID,
+	).Scan(&wasDefault)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	if wasDefault {
+		_, err = tx.Exec(ctx, `
+		UPDATE customer_addresses
+		SET is_default = TRUE, version = version + 1
+		WHERE id = (
+			SELECT id FROM customer_addresses
+			WHERE customer_id = $1
+			ORDER BY created_at DESC, id
+			LIMIT 1
+		)`, customerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockCustomerTx serializes default-address changes of one customer.
+func lockCustomerTx(ctx context.Context, tx pgx.Tx, customerID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM customers WHERE id = $1 FOR UPDATE`, customerID).Scan(&id)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func clearDefaultAddressTx(ctx context.Context, tx pgx.Tx, customerID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+	UPDATE customer_addresses
+	SET is_default = FALSE, version = version + 1
+	WHERE customer_id = $1 AND is_default`, customerID)
+	return err
+}
+
+func scanCustomerAddress(row pgx.Row) (*data.CustomerAddress, error) {
+	var address data.CustomerAddress
+	err := row.Scan(
+		&address.ID,
+		&address.CustomerID,
+		&address.RecipientName,
+		&address.CountryCode,
+		&address.CountryName,
+		&address.Region,
+		&address.City,
+		&address.Street,
+		&address.PostalCode,
+		&address.Phone,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+		&address.CreatedByID,
+		&address.UpdatedByID,
+		&address.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// addressWriteError reports an unknown country code as ErrUnknownCountry
+// instead of a bare foreign key violation.
+func addressWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) &&
+		pgErr.Code == constants.ForeignKeyViolation &&
+		pgErr.ConstraintName == "customer_addresses_country_code_fk" {
+		return common.ErrUnknownCountry
+	}
+	return err
+}
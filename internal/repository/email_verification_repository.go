@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/redis/go-redis/v9"
+)
+
+type EmailVerificationRepository struct {
+	RDB *redis.Client
+}
+
+func emailVerificationKey(nonce string) string {
+	return fmt.Sprintf("email_verification:%s", nonce)
+}
+
+func emailVerificationCooldownKey(userID uuid.UUID) string {
+	return fmt.Sprintf("email_verification_cooldown:%s", userID)
+}
+
+// Save stores the nonce of a freshly signed verification token and starts
+// the resend cooldown of the user. It returns
+// common.ErrEmailVerificationCooldown when the previous email was sent less
+// than cooldown ago.
+func (r EmailVerificationRepository) Save(
+	nonce string, userID uuid.UUID, ttl time.Duration, cooldown time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := r.RDB.SetNX(ctx, emailVerificationCooldownKey(userID), 1, cooldown).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return common.ErrEmailVerificationCooldown
+	}
+
+	return r.RDB.Set(ctx, emailVerificationKey(nonce), userID.String(), ttl).Err()
+}
+
+// Consume returns the user the nonce was issued for and deletes it in the
+// same round trip, so a token can be used only once.
+func (r EmailVerificationRepository) Consume(nonce string) (uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := r.RDB.GetDel(ctx, emailVerificationKey(nonce)).Result()
+	if err != nil {
+		switch {
+		case errors.Is(err, redis.Nil):
+			return uuid.Nil, common.ErrRecordNotFound
+		default:
+			return uuid.Nil, err
+		}
+	}
+
+	return uuid.Parse(value)
+}
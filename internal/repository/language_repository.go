@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +11,7 @@ import (
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/internal/common"
 	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
 
@@ -19,40 +19,44 @@ type LanguageRepository struct {
 	DBPOOL *pgxpool.Pool
 }
 
-func (r LanguageRepository) Create(language *data.Language) error {
+func (r LanguageRepository) Create(language *data.Language, audit data.AuditFunc) error {
 	query := `
 		INSERT INTO languages (
 			code,
 			name,
 			created_by_id,
-			updated_by_id,
-		) VALUES $1, $2, $3, $4
-		RETURNING id, code, name, version
+			updated_by_id
+		) VALUES ($1, $2, $3, $4)
+		RETURNING id, code, name, created_at, updated_at, version
 	`
 
 	args := []interface{}{
 		language.Code,
 		language.Name,
 		language.CreatedByID,
-		language.CreatedByID,
+		language.UpdatedByID,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	return r.DBPOOL.QueryRow(ctx, query, args).Scan(
-		&language.ID,
-		&language.Code,
-		&language.Name,
-		&language.Version,
-	)
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args...).Scan(
+			&language.ID,
+			&language.Code,
+			&language.Name,
+			&language.CreatedAt,
+			&language.UpdatedAt,
+			&language.Version,
+		)
+	})
 }
 
 func (r LanguageRepository) GetByID(id uuid.UUID) (*data.Language, error) {
 	query := `
-		SELECT * 
+		SELECT id, code, name, created_at, updated_at, created_by_id, updated_by_id, version
 		FROM languages
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -85,9 +89,9 @@ func (r LanguageRepository) GetByID(id uuid.UUID) (*data.Language, error) {
 
 func (r LanguageRepository) GetByCode(code string) (*data.Language, error) {
 	query := `
-		SELECT * 
+		SELECT id, code, name, created_at, updated_at, created_by_id, updated_by_id, version
 		FROM languages
-		WHERE code = $1
+		WHERE code = $1 AND deleted_at IS NULL
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
@@ -119,35 +123,17 @@ func (r LanguageRepository) GetByCode(code string) (*data.Language, error) {
 }
 
 func (r LanguageRepository) List(f *requests.LanguagesAdminFilters) ([]*data.Language, types.PaginationMetadata, error) {
-	query := `
-		SELECT *
-		FROM languages
-	`
+	q := filters.NewQuery(`
+		SELECT
+			count(*) OVER(),
+			id, code, name, created_at, updated_at, created_by_id, updated_by_id, version
+		FROM languages`)
 
-	argCounter := 1
-	args := []interface{}{}
-
-	fallbackPageSize := 20 // FIXME: make a constant number
-	if f.PageSize != nil {
-		query += fmt.Sprintf(" LIMIT $%d", argCounter)
-		args = append(args, *f.PageSize)
-		argCounter++
-		fallbackPageSize = *f.PageSize
-	} else {
-		f.PageSize = &fallbackPageSize
-		query += fmt.Sprintf(" LIMIT %d", fallbackPageSize)
-	}
+	q.Where("deleted_at IS NULL")
+	q.OrderBy("code")
+	page, pageSize := f.PaginationFilter.Apply(q)
 
-	defaultPage := 1 // FIXME: make a constant number
-	if f.Page != nil {
-		offset := fallbackPageSize * (*f.Page - 1)
-		query += fmt.Sprintf(" OFFSET $%d", argCounter)
-		args = append(args, offset)
-		argCounter++
-	} else {
-		f.Page = &defaultPage
-		query += fmt.Sprintf(" OFFSET %d", defaultPage)
-	}
+	query, args := q.Build()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
@@ -164,6 +150,7 @@ func (r LanguageRepository) List(f *requests.LanguagesAdminFilters) ([]*data.Lan
 	for rows.Next() {
 		var language data.Language
 		err := rows.Scan(
+			&totalRecords,
 			&language.ID,
 			&language.Code,
 			&language.Name,
@@ -177,19 +164,18 @@ func (r LanguageRepository) List(f *requests.LanguagesAdminFilters) ([]*data.Lan
 			return nil, types.PaginationMetadata{}, err
 		}
 		languages = append(languages, &language)
-		totalRecords++
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, types.PaginationMetadata{}, err
 	}
 
-	metadata := common.CalculateMetadata(totalRecords, *f.Page, *f.PageSize)
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
 
 	return languages, metadata, nil
 }
 
-func (r LanguageRepository) Update(language *data.Language) error {
+func (r LanguageRepository) Update(language *data.Language, audit data.AuditFunc) error {
 	query := `
 		UPDATE languages
 		SET
@@ -197,8 +183,8 @@ func (r LanguageRepository) Update(language *data.Language) error {
 			name = $2,
 			updated_by_id = $3,
 			version = version + 1
-		WHERE id = $4 AND version = $5
-		RETURNING id, code, name, version	
+		WHERE id = $4 AND version = $5 AND deleted_at IS NULL
+		RETURNING id, code, name, updated_at, version
 	`
 
 	args := []interface{}{
@@ -212,43 +198,157 @@ func (r LanguageRepository) Update(language *data.Language) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	err := r.DBPOOL.QueryRow(ctx, query, args).Scan(
-		&language.ID,
-		&language.Code,
-		&language.Name,
-		&language.Version,
-	)
-
-	if err != nil {
-		switch {
-		case errors.Is(err, pgx.ErrNoRows):
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, args...).Scan(
+			&language.ID,
+			&language.Code,
+			&language.Name,
+			&language.UpdatedAt,
+			&language.Version,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
 			return common.ErrEditConflict
-		default:
+		}
+		return err
+	})
+}
+
+// Delete moves the language to the trash. It is removed for good by Purge
+// once the retention period has passed.
+func (r LanguageRepository) Delete(id uuid.UUID, audit data.AuditFunc) error {
+	query := `
+		UPDATE languages
+		SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, id)
+		if err != nil {
 			return err
 		}
+
+		if result.RowsAffected() < 1 {
+			return common.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// ListDeleted lists the languages in the trash, most recently deleted first.
+func (r LanguageRepository) ListDeleted(f *filters.PaginationFilter) ([]*data.Language, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+		SELECT
+			count(*) OVER(),
+			id, code, name, created_at, updated_at, created_by_id, updated_by_id, version,
+			deleted_at
+		FROM languages`)
+
+	q.Where("deleted_at IS NOT NULL")
+	q.OrderBy("deleted_at DESC", "id")
+	page, pageSize := f.Apply(q)
+
+	query, args := q.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	languages := []*data.Language{}
+
+	for rows.Next() {
+		var language data.Language
+		err := rows.Scan(
+			&totalRecords,
+			&language.ID,
+			&language.Code,
+			&language.Name,
+			&language.CreatedAt,
+			&language.UpdatedAt,
+			&language.CreatedByID,
+			&language.UpdatedByID,
+			&language.Version,
+			&language.DeletedAt,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		languages = append(languages, &language)
 	}
 
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+
+	return languages, common.CalculateMetadata(totalRecords, page, pageSize), nil
 }
 
-func (r LanguageRepository) Delete(id uuid.UUID) error {
+// Restore takes the language with the ID of language out of the trash, as
+// restored by its UpdatedByID, and loads the restored language into it.
+func (r LanguageRepository) Restore(language *data.Language, audit data.AuditFunc) error {
 	query := `
-		DELETE FROM languages
-		WHERE id = $1
+		UPDATE languages
+		SET deleted_at = NULL, updated_by_id = $2, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, code, name, created_at, updated_at, created_by_id, updated_by_id, version
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	result, err := r.DBPOOL.Exec(ctx, query, id)
-	if err != nil {
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, language.ID, language.UpdatedByID).Scan(
+			&language.ID,
+			&language.Code,
+			&language.Name,
+			&language.CreatedAt,
+			&language.UpdatedAt,
+			&language.CreatedByID,
+			&language.UpdatedByID,
+			&language.Version,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return common.ErrRecordNotFound
+		}
 		return err
+	})
+}
+
+// Purge removes the languages deleted before the cutoff. Their translations
+// go first because translations reference the language code.
+func (r LanguageRepository) Purge(cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM translations
+		WHERE language_code IN (SELECT code FROM languages WHERE deleted_at < $1)`,
+		cutoff,
+	)
+	if err != nil {
+		return 0, err
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected < 1 {
-		return common.ErrRecordNotFound
+	result, err := tx.Exec(ctx, `DELETE FROM languages WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return result.RowsAffected(), tx.Commit(ctx)
 }
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/redis/go-redis/v9"
+)
+
+type LoginAttemptRepository struct {
+	RDB *redis.Client
+}
+
+func loginFailuresKey(kind, key string) string {
+	return fmt.Sprintf("login_failures:%s:%s", kind, key)
+}
+
+func loginLockoutKey(kind, key string) string {
+	return fmt.Sprintf("login_lockout:%s:%s", kind, key)
+}
+
+// GetLockout returns how long the key is still locked out, zero if it is not.
+func (r LoginAttemptRepository) GetLockout(kind, key string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ttl, err := r.RDB.PTTL(ctx, loginLockoutKey(kind, key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+// RegisterFailure increments the failed attempts counter and returns the new
+// value. The counter expires window after the last failure.
+func (r LoginAttemptRepository) RegisterFailure(kind, key string, window time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	k := loginFailuresKey(kind, key)
+
+	pipe := r.RDB.TxPipeline()
+	incr := pipe.Incr(ctx, k)
+	pipe.Expire(ctx, k, window)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(incr.Val()), nil
+}
+
+func (r LoginAttemptRepository) Lock(kind, key string, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.RDB.Set(ctx, loginLockoutKey(kind, key), 1, d).Err()
+}
+
+// Reset removes both the failed attempts counter and an active lockout.
+func (r LoginAttemptRepository) Reset(kind, key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.RDB.Del(ctx, loginFailuresKey(kind, key), loginLockoutKey(kind, key)).Err()
+}
+
+// List returns every key of kind that has failed attempts or is locked out.
+func (r LoginAttemptRepository) List(kind string) ([]*data.LoginLockout, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lockouts := map[string]*data.LoginLockout{}
+	keys := []string{}
+
+	get := func(key string) *data.LoginLockout {
+		l, ok := lockouts[key]
+		if !ok {
+			l = &data.LoginLockout{Kind: kind, Key: key}
+			lockouts[key] = l
+			keys = append(keys, key)
+		}
+		return l
+	}
+
+	failuresPrefix := loginFailuresKey(kind, "")
+	iter := r.RDB.Scan(ctx, 0, failuresPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		value, err := r.RDB.Get(ctx, iter.Val()).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, err
+		}
+		attempts, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		get(strings.TrimPrefix(iter.Val(), failuresPrefix)).FailedAttempts = attempts
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	lockoutPrefix := loginLockoutKey(kind, "")
+	iter = r.RDB.Scan(ctx, 0, lockoutPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		ttl, err := r.RDB.PTTL(ctx, iter.Val()).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ttl < 0 {
+			continue
+		}
+		lockedUntil := time.Now().Add(ttl)
+		get(strings.TrimPrefix(iter.Val(), lockoutPrefix)).LockedUntil = &lockedUntil
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*data.LoginLockout, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, lockouts[key])
+	}
+
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/redis/go-redis/v9"
+)
+
+type MFAChallengeRepository struct {
+	RDB *redis.Client
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("mfa_challenge:%s", tokenHash)
+}
+
+func (r MFAChallengeRepository) Save(tokenHash string, challenge *data.MFAChallenge, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := mfaChallengeKey(tokenHash)
+
+	pipe := r.RDB.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", challenge.UserID.String(), "enroll", challenge.Enroll, "attempts", 0)
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r MFAChallengeRepository) Get(tokenHash string) (*data.MFAChallenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	values, err := r.RDB.HGetAll(ctx, mfaChallengeKey(tokenHash)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, common.ErrRecordNotFound
+	}
+
+	userID, err := uuid.Parse(values["user_id"])
+	if err != nil {
+		return nil, err
+	}
+
+	enroll, err := strconv.ParseBool(values["enroll"])
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := strconv.Atoi(values["attempts"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.MFAChallenge{
+		UserID:   userID,
+		Enroll:   enroll,
+		Attempts: attempts,
+	}, nil
+}
+
+// IncrementAttempts bumps the attempts counter of an existing challenge and
+// returns the new value.
+func (r MFAChallengeRepository) IncrementAttempts(tokenHash string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := mfaChallengeKey(tokenHash)
+
+	exists, err := r.RDB.Exists(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		return 0, common.ErrRecordNotFound
+	}
+
+	attempts, err := r.RDB.HIncrBy(ctx, key, "attempts", 1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(attempts), nil
+}
+
+func (r MFAChallengeRepository) Delete(tokenHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.RDB.Del(ctx, mfaChallengeKey(tokenHash)).Err()
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+type NotificationPreferenceRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// GetByUserID returns the preferences of the user, or the defaults when
+// the user never changed them.
+func (r NotificationPreferenceRepository) GetByUserID(userID uuid.UUID) (*data.NotificationPreferences, error) {
+	query := `
+	SELECT user_id, order_updates, promotions, review_replies, created_at, updated_at, version
+	FROM user_notification_preferences
+	WHERE user_id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var prefs data.NotificationPreferences
+	err := r.DBPOOL.QueryRow(ctx, query, userID).Scan(
+		&prefs.UserID,
+		&prefs.OrderUpdates,
+		&prefs.Promotions,
+		&prefs.ReviewReplies,
+		&prefs.CreatedAt,
+		&prefs.UpdatedAt,
+		&prefs.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return data.DefaultNotificationPreferences(userID), nil
+		default:
+			return nil, err
+		}
+	}
+
+	return &prefs, nil
+}
+
+func (r NotificationPreferenceRepository) Upsert(prefs *data.NotificationPreferences) error {
+	query := `
+	INSERT INTO user_notification_preferences (user_id, order_updates, promotions, review_replies)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (user_id) DO UPDATE
+	SET
+		order_updates = EXCLUDED.order_updates,
+		promotions = EXCLUDED.promotions,
+		review_replies = EXCLUDED.review_replies,
+		updated_at = NOW(),
+		version = user_notification_preferences.version + 1
+	RETURNING created_at, updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.DBPOOL.QueryRow(
+		ctx,
+		query,
+		prefs.UserID,
+		prefs.OrderUpdates,
+		prefs.Promotions,
+		prefs.ReviewReplies,
+	).Scan(
+		&prefs.CreatedAt,
+		&prefs.UpdatedAt,
+		&prefs.Version,
+	)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/redis/go-redis/v9"
+)
+
+type OTPRepository struct {
+	RDB *redis.Client
+}
+
+func otpKey(purpose, phone string) string {
+	return fmt.Sprintf("otp:%s:%s", purpose, phone)
+}
+
+func otpCooldownKey(purpose, phone string) string {
+	return fmt.Sprintf("otp_cooldown:%s:%s", purpose, phone)
+}
+
+// Save stores the otp hash with a fresh attempts counter and starts the
+// resend cooldown. It returns common.ErrOTPResendCooldown when the
+// previous code was sent less than cooldown ago.
+func (r OTPRepository) Save(otp *data.OTP, ttl time.Duration, cooldown time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := r.RDB.SetNX(ctx, otpCooldownKey(otp.Purpose, otp.Phone), 1, cooldown).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return common.ErrOTPResendCooldown
+	}
+
+	key := otpKey(otp.Purpose, otp.Phone)
+
+	pipe := r.RDB.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "code_hash", otp.CodeHash, "attempts", 0)
+	pipe.Expire(ctx, key, ttl)
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	otp.Attempts = 0
+	otp.ExpiresAt = time.Now().Add(ttl)
+
+	return nil
+}
+
+func (r OTPRepository) Get(purpose, phone string) (*data.OTP, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := otpKey(purpose, phone)
+
+	values, err := r.RDB.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, common.ErrRecordNotFound
+	}
+
+	attempts, err := strconv.Atoi(values["attempts"])
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := r.RDB.TTL(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.OTP{
+		Phone:     phone,
+		Purpose:   purpose,
+		CodeHash:  values["code_hash"],
+		Attempts:  attempts,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// incrementAttemptsScript bumps the attempts counter only while the otp
+// exists, so a code expiring between the check and the increment can not
+// leave a counter behind without a TTL. It returns -1 for a missing key.
+var incrementAttemptsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+`)
+
+// IncrementAttempts atomically bumps the attempts counter and returns the new
+// value. A missing key means the otp has already expired or been consumed.
+func (r OTPRepository) IncrementAttempts(purpose, phone string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	attempts, err := incrementAttemptsScript.Run(ctx, r.RDB, []string{otpKey(purpose, phone)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if attempts < 0 {
+		return 0, common.ErrRecordNotFound
+	}
+
+	return attempts, nil
+}
+
+func (r OTPRepository) Delete(purpose, phone string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.RDB.Del(ctx, otpKey(purpose, phone)).Err()
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/redis/go-redis/v9"
+)
+
+type PasswordResetRepository struct {
+	RDB *redis.Client
+}
+
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password_reset:%s", tokenHash)
+}
+
+func (r PasswordResetRepository) Save(tokenHash string, userID uuid.UUID, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.RDB.Set(ctx, passwordResetKey(tokenHash), userID.String(), ttl).Err()
+}
+
+// Consume returns the user the token was issued for and deletes the token in
+// the same round trip, so a token can be used only once.
+func (r PasswordResetRepository) Consume(tokenHash string) (uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	value, err := r.RDB.GetDel(ctx, passwordResetKey(tokenHash)).Result()
+	if err != nil {
+		switch {
+		case errors.Is(err, redis.Nil):
+			return uuid.Nil, common.ErrRecordNotFound
+		default:
+			return uuid.Nil, err
+		}
+	}
+
+	return uuid.Parse(value)
+}
@@ -0,0 +1,566 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+type PrivacyRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// erasedAuditFields are the fields of user snapshots in the audit log that
+// identify the person. Erase replaces their values with erasedAuditValue.
+var erasedAuditFields = []string{
+	"phone", "phone_verified_at", "first_name", "last_name", "patronomic", "dob",
+	"email", "email_verified_at",
+}
+
+const erasedAuditValue = "[erased]"
+
+const erasureRequestColumnsSQL = `
+	id, user_id, requested_at, execute_after, cancelled_at, executed_at
+`
+
+func scanErasureRequest(row pgx.Row) (*data.ErasureRequest, error) {
+	var request data.ErasureRequest
+	err := row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.RequestedAt,
+		&request.ExecuteAfter,
+		&request.CancelledAt,
+		&request.ExecutedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &request, nil
+}
+
+// Export collects the data kept about user. Everything is read in one
+// repeatable read transaction so the parts of the export agree with each
+// other.
+func (r PrivacyRepository) Export(user *data.User) (*data.UserDataExport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	export := &data.UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    user,
+	}
+
+	export.Addresses, err = collectExportRows(ctx, tx, `
+	SELECT `+customerAddressColumns+`
+	FROM customer_addresses a
+	JOIN countries c ON c.code = a.country_code
+	JOIN customers cu ON cu.id = a.customer_id
+	WHERE cu.user_id = $1
+	ORDER BY a.created_at, a.id`, user.ID, scanCustomerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	export.Sessions, err = collectExportRows(ctx, tx, `
+	SELECT id, is_revoked, created_at, expires_at
+	FROM sessions
+	WHERE user_phone = $1
+	ORDER BY created_at, id`, user.Phone, func(row pgx.Row) (*data.SessionRecord, error) {
+		var session data.SessionRecord
+		err := row.Scan(&session.ID, &session.IsRevoked, &session.CreatedAt, &session.ExpiresAt)
+		return &session, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	export.Purchases, err = collectExportRows(ctx, tx, `
+	SELECT order_id, product_id, quantity, referral_id, created_at
+	FROM product_purchases
+	WHERE buyer_id = $1
+	ORDER BY created_at, order_id, product_id`, user.ID, func(row pgx.Row) (*data.PurchaseRecord, error) {
+		var purchase data.PurchaseRecord
+		err := row.Scan(
+			&purchase.OrderID,
+			&purchase.ProductID,
+			&purchase.Quantity,
+			&purchase.ReferralID,
+			&purchase.CreatedAt,
+		)
+		return &purchase, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	export.Reviews, err = collectExportRows(ctx, tx, `
+	SELECT
+		id, product_id, user_id, rating, review_text, image_url, video_url,
+		is_approved, created_at, updated_at, version
+	FROM product_reviews
+	WHERE user_id = $1
+	ORDER BY created_at, id`, user.ID, func(row pgx.Row) (*data.ProductReview, error) {
+		var review data.ProductReview
+		err := row.Scan(
+			&review.ID,
+			&review.ProductID,
+			&review.UserID,
+			&review.Rating,
+			&review.ReviewText,
+			&review.ImageUrl,
+			&review.VideoUrl,
+			&review.IsApproved,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&review.Version,
+		)
+		return &review, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	referrals, err := collectExportRows(ctx, tx, `
+	SELECT id, user_id, code, created_at, updated_at, version
+	FROM user_referrals
+	WHERE user_id = $1`, user.ID, func(row pgx.Row) (*data.UserReferral, error) {
+		var referral data.UserReferral
+		err := row.Scan(
+			&referral.ID,
+			&referral.UserID,
+			&referral.Code,
+			&referral.CreatedAt,
+			&referral.UpdatedAt,
+			&referral.Version,
+		)
+		return &referral, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(referrals) > 0 {
+		export.Referral = referrals[0]
+	}
+
+	export.ProductReferrals, err = collectExportRows(ctx, tx, `
+	SELECT `+productReferralColumnsSQL+`
+	FROM user_product_referrals
+	WHERE user_id = $1
+	ORDER BY created_at, id`, user.ID, scanProductReferral)
+	if err != nil {
+		return nil, err
+	}
+
+	export.BonusHistory, err = collectExportRows(ctx, tx, `
+	SELECT
+		id, user_id, kind, delta, balance_after, reason, note,
+		order_id, referral_id, product_referral_id, actor_id, created_at
+	FROM bonus_ledger_entries
+	WHERE user_id = $1
+	ORDER BY created_at, id`, user.ID, func(row pgx.Row) (*data.BonusLedgerEntry, error) {
+		var entry data.BonusLedgerEntry
+		err := row.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Kind,
+			&entry.Delta,
+			&entry.BalanceAfter,
+			&entry.Reason,
+			&entry.Note,
+			&entry.OrderID,
+			&entry.ReferralID,
+			&entry.ProductReferralID,
+			&entry.ActorID,
+			&entry.CreatedAt,
+		)
+		return &entry, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	export.ErasureRequests, err = collectExportRows(ctx, tx, `
+	SELECT `+erasureRequestColumnsSQL+`
+	FROM erasure_requests
+	WHERE user_id = $1
+	ORDER BY requested_at, id`, user.ID, scanErasureRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	export.NotificationPreferences = data.DefaultNotificationPreferences(user.ID)
+	err = tx.QueryRow(ctx, `
+	SELECT order_updates, promotions, review_replies, created_at, updated_at, version
+	FROM user_notification_preferences
+	WHERE user_id = $1`, user.ID).Scan(
+		&export.NotificationPreferences.OrderUpdates,
+		&export.NotificationPreferences.Promotions,
+		&export.NotificationPreferences.ReviewReplies,
+		&export.NotificationPreferences.CreatedAt,
+		&export.NotificationPreferences.UpdatedAt,
+		&export.NotificationPreferences.Version,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	return export, tx.Commit(ctx)
+}
+
+func collectExportRows[T any](
+	ctx context.Context, tx pgx.Tx, query string, arg any, scan func(pgx.Row) (*T, error),
+) ([]*T, error) {
+	rows, err := tx.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// CreateErasureRequest schedules the erasure of the user. A user has at most
+// one pending request; a second one fails with ErrErasurePending.
+func (r PrivacyRepository) CreateErasureRequest(request *data.ErasureRequest) error {
+	query := `
+	INSERT INTO erasure_requests (user_id, execute_after)
+	VALUES ($1, $2)
+	RETURNING ` + erasureRequestColumnsSQL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created, err := scanErasureRequest(
+		r.DBPOOL.QueryRow(ctx, query, request.UserID, request.ExecuteAfter),
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) &&
+			pgErr.Code == constants.UniqueViolation &&
+			pgErr.ConstraintName == "idx_erasure_requests_pending" {
+			return common.ErrErasurePending
+		}
+		return err
+	}
+
+	*request = *created
+	return nil
+}
+
+func (r PrivacyRepository) GetPendingErasureRequest(userID uuid.UUID) (*data.ErasureRequest, error) {
+	query := `
+	SELECT ` + erasureRequestColumnsSQL + `
+	FROM erasure_requests
+	WHERE user_id = $1 AND cancelled_at IS NULL AND executed_at IS NULL`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanErasureRequest(r.DBPOOL.QueryRow(ctx, query, userID))
+}
+
+func (r PrivacyRepository) CancelErasureRequest(userID uuid.UUID) (*data.ErasureRequest, error) {
+	query := `
+	UPDATE erasure_requests
+	SET cancelled_at = NOW()
+	WHERE user_id = $1 AND cancelled_at IS NULL AND executed_at IS NULL
+	RETURNING ` + erasureRequestColumnsSQL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanErasureRequest(r.DBPOOL.QueryRow(ctx, query, userID))
+}
+
+// ListDueErasureRequests returns at most limit pending requests whose
+// cooling-off period has passed, oldest first.
+func (r PrivacyRepository) ListDueErasureRequests(limit int) ([]*data.ErasureRequest, error) {
+	query := `
+	SELECT ` + erasureRequestColumnsSQL + `
+	FROM erasure_requests
+	WHERE cancelled_at IS NULL AND executed_at IS NULL AND execute_after <= NOW()
+	ORDER BY execute_after, id
+	LIMIT $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*data.ErasureRequest{}
+	for rows.Next() {
+		request, err := scanErasureRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+// Erase carries out a due erasure request with eraseUserTx. It returns
+// ErrRecordNotFound if the request was cancelled or executed in the
+// meantime.
+func (r PrivacyRepository) Erase(requestID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID uuid.UUID
+	err = tx.QueryRow(
+		ctx,
+		`SELECT user_id FROM erasure_requests
+		WHERE id = $1 AND cancelled_at IS NULL AND executed_at IS NULL
+		AND execute_after <= NOW()
+		FOR UPDATE`,
+		requestID,
+	).Scan(&userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	err = eraseUserTx(ctx, tx, userID, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListExpiredDeletedUsers returns the ids of at most limit users that were
+// moved to the trash before cutoff and are not erased yet, oldest first.
+func (r PrivacyRepository) ListExpiredDeletedUsers(cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+	SELECT id
+	FROM users
+	WHERE deleted_at < $1 AND erased_at IS NULL
+	ORDER BY deleted_at, id
+	LIMIT $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// EraseDeletedUser erases a user that was moved to the trash before cutoff
+// with eraseUserTx, recording reason in the audit log. It returns
+// ErrRecordNotFound if the user was restored or erased in the meantime.
+func (r PrivacyRepository) EraseDeletedUser(userID uuid.UUID, cutoff time.Time, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
+		ctx,
+		`SELECT id FROM users
+		WHERE id = $1 AND deleted_at < $2 AND erased_at IS NULL
+		FOR UPDATE`,
+		userID,
+		cutoff,
+	).Scan(&userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	err = eraseUserTx(ctx, tx, userID, &reason)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// eraseUserTx anonymizes the user inside a transaction owned by the caller.
+// The user row stays because orders, reviews and the bonus ledger point at
+// it, but everything that identifies the person is removed from it: the
+// phone is replaced with a unique placeholder, names, email and date of
+// birth are cleared and the password no longer matches anything. Sessions,
+// saved addresses and second factor secrets are deleted. The same fields are
+// redacted in the user's audit log snapshots, together with the addresses
+// of the requests the user made. A pending erasure request of the user is
+// marked as executed.
+func eraseUserTx(ctx context.Context, tx pgx.Tx, userID uuid.UUID, reason *string) error {
+	var phone string
+	err := tx.QueryRow(ctx, `SELECT phone FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&phone)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM sessions WHERE user_phone = $1`, phone)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE users
+		SET phone = '+999' || lpad(nextval('erased_user_phone_seq')::text, 11, '0'),
+			password_hash = ''::bytea,
+			first_name = NULL,
+			last_name = NULL,
+			patronymic = NULL,
+			dob = NULL,
+			email = NULL,
+			email_verified_at = NULL,
+			phone_verified_at = NULL,
+			is_active = FALSE,
+			deleted_at = COALESCE(deleted_at, NOW()),
+			erased_at = NOW(),
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		`DELETE FROM customer_addresses
+		WHERE customer_id IN (SELECT id FROM customers WHERE user_id = $1)`,
+		`DELETE FROM user_totp WHERE user_id = $1`,
+		`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+	}
+	for _, statement := range statements {
+		_, err = tx.Exec(ctx, statement, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = redactAuditLogTx(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE erasure_requests SET executed_at = NOW()
+		WHERE user_id = $1 AND cancelled_at IS NULL AND executed_at IS NULL`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return insertAuditEntryTx(ctx, tx, &data.AuditEntry{
+		Action:    constants.AuditActionUserErase,
+		TableName: "users",
+		EntityID:  &userID,
+		Reason:    reason,
+	})
+}
+
+// redactAuditLogTx removes the personal data of the user from the audit
+// log. The names of the changed fields stay, so the log still shows what was
+// changed and by whom. The audit_log trigger only allows this while
+// app.audit_redaction is on, which set_config limits to the transaction.
+func redactAuditLogTx(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `SELECT set_config('app.audit_redaction', 'on', true)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE audit_log
+		SET before = (
+				SELECT jsonb_object_agg(key, CASE WHEN key = ANY($2) THEN to_jsonb($3::text) ELSE value END)
+				FROM jsonb_each(before)
+			),
+			after = (
+				SELECT jsonb_object_agg(key, CASE WHEN key = ANY($2) THEN to_jsonb($3::text) ELSE value END)
+				FROM jsonb_each(after)
+			)
+		WHERE table_name = 'users' AND entity_id = $1
+		AND (before ?| $2 OR after ?| $2)`,
+		userID,
+		erasedAuditFields,
+		erasedAuditValue,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE audit_log SET ip = NULL WHERE actor_id = $1 AND ip IS NOT NULL`, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `SELECT set_config('app.audit_redaction', 'off', true)`)
+	return err
+}
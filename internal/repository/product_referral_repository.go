@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/shopspring/decimal"
+)
+
+type ProductReferralRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+const productReferralColumnsSQL = `
+	id, user_id, product_id, code, clicks, signups, purchases,
+	created_at, updated_at, version
+`
+
+func scanProductReferral(row pgx.Row) (*data.ProductReferral, error) {
+	var referral data.ProductReferral
+	err := row.Scan(
+		&referral.ID,
+		&referral.UserID,
+		&referral.ProductID,
+		&referral.Code,
+		&referral.Clicks,
+		&referral.Signups,
+		&referral.Purchases,
+		&referral.CreatedAt,
+		&referral.UpdatedAt,
+		&referral.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &referral, nil
+}
+
+func (r ProductReferralRepository) Create(referral *data.ProductReferral) error {
+	query := `
+	INSERT INTO user_product_referrals (user_id, product_id, code)
+	VALUES ($1, $2, $3)
+	RETURNING ` + productReferralColumnsSQL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created, err := scanProductReferral(
+		r.DBPOOL.QueryRow(ctx, query, referral.UserID, referral.ProductID, referral.Code),
+	)
+	if err != nil {
+		return err
+	}
+
+	*referral = *created
+	return nil
+}
+
+func (r ProductReferralRepository) GetByUserAndProduct(userID, productID uuid.UUID) (*data.ProductReferral, error) {
+	query := `SELECT ` + productReferralColumnsSQL + `
+	FROM user_product_referrals
+	WHERE user_id = $1 AND product_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanProductReferral(r.DBPOOL.QueryRow(ctx, query, userID, productID))
+}
+
+func (r ProductReferralRepository) GetByCode(code string) (*data.ProductReferral, error) {
+	query := `SELECT ` + productReferralColumnsSQL + `
+	FROM user_product_referrals
+	WHERE code = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanProductReferral(r.DBPOOL.QueryRow(ctx, query, strings.ToUpper(code)))
+}
+
+func (r ProductReferralRepository) ListByUserID(userID uuid.UUID) ([]*data.ProductReferral, error) {
+	query := `SELECT ` + productReferralColumnsSQL + `
+	FROM user_product_referrals
+	WHERE user_id = $1
+	ORDER BY created_at DESC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	referrals := []*data.ProductReferral{}
+	for rows.Next() {
+		referral, err := scanProductReferral(rows)
+		if err != nil {
+			return nil, err
+		}
+		referrals = append(referrals, referral)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return referrals, nil
+}
+
+// RegisterClick counts one visit through the share link and returns the
+// referral so the caller can send the visitor on to the product.
+func (r ProductReferralRepository) RegisterClick(code string) (*data.ProductReferral, error) {
+	query := `
+	UPDATE user_product_referrals
+	SET clicks = clicks + 1
+	WHERE code = $1
+	RETURNING ` + productReferralColumnsSQL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanProductReferral(r.DBPOOL.QueryRow(ctx, query, strings.ToUpper(code)))
+}
+
+// RecordPaidOrder stores the lines of a paid order, adds them to the
+// buyer's bought products and credits the product referrer of every line
+// that came through a share link with a purchase and the given bonus points.
+// Lines already recorded for the order are skipped, so reporting the same
+// order twice changes nothing. It returns the ids of the credited
+// referrers, one per credited line.
+func (r ProductReferralRepository) RecordPaidOrder(
+	orderID, buyerID uuid.UUID, lines []data.PaidOrderLine, points decimal.Decimal,
+) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The explicit code wins; otherwise fall back to the product link the
+	// buyer signed up with, if it was for this product. Nobody is credited
+	// for their own purchases.
+	referralQuery := `
+	SELECT upr.id
+	FROM user_product_referrals upr
+	WHERE upr.product_id = $1
+		AND upr.user_id <> $2
+		AND (
+			upr.code = $3
+			OR ($3 IS NULL AND upr.id = (SELECT inv_prod_ref_id FROM users WHERE id = $2))
+		)`
+
+	insertQuery := `
+	INSERT INTO product_purchases (order_id, product_id, buyer_id, quantity, referral_id)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (order_id, product_id) DO NOTHING`
+
+	boughtQuery := `
+	INSERT INTO user_bought_products (user_id, product_id, quantity)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, product_id)
+	DO UPDATE SET quantity = user_bought_products.quantity + EXCLUDED.quantity,
+		version = user_bought_products.version + 1`
+
+	creditQuery := `
+	WITH referral AS (
+		UPDATE user_product_referrals
+		SET purchases = purchases + 1
+		WHERE id = $1
+		RETURNING user_id
+	)
+	UPDATE users
+	SET prod_ref_bought = prod_ref_bought + 1
+	FROM referral
+	WHERE users.id = referral.user_id
+	RETURNING users.id`
+
+	credited := []uuid.UUID{}
+	for _, line := range lines {
+		var code *string
+		if line.ReferralCode != nil {
+			upper := strings.ToUpper(*line.ReferralCode)
+			code = &upper
+		}
+
+		var referralID *uuid.UUID
+		var id uuid.UUID
+		err = tx.QueryRow(ctx, referralQuery, line.ProductID, buyerID, code).Scan(&id)
+		switch {
+		case err == nil:
+			referralID = &id
+		case errors.Is(err, pgx.ErrNoRows):
+		default:
+			return nil, err
+		}
+
+		result, err := tx.Exec(ctx, insertQuery, orderID, line.ProductID, buyerID, line.Quantity, referralID)
+		if err != nil {
+			return nil, err
+		}
+		if result.RowsAffected() == 0 {
+			continue
+		}
+
+		_, err = tx.Exec(ctx, boughtQuery, buyerID, line.ProductID, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		if referralID == nil {
+			continue
+		}
+
+		var referrerID uuid.UUID
+		err = tx.QueryRow(ctx, creditQuery, *referralID).Scan(&referrerID)
+		if err != nil {
+			return nil, err
+		}
+		credited = append(credited, referrerID)
+
+		if points.IsPositive() {
+			err = adjustPointsTx(ctx, tx, &data.BonusLedgerEntry{
+				UserID:            referrerID,
+				Delta:             points,
+				Reason:            constants.BonusReasonProductReferralPurchase,
+				OrderID:           &orderID,
+				ProductReferralID: referralID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return credited, tx.Commit(ctx)
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+type ReferralRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+func (r ReferralRepository) Create(referral *data.UserReferral) error {
+	query := `
+	INSERT INTO user_referrals (user_id, code)
+	VALUES ($1, $2)
+	RETURNING id, created_at, updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.DBPOOL.QueryRow(ctx, query, referral.UserID, referral.Code).Scan(
+		&referral.ID,
+		&referral.CreatedAt,
+		&referral.UpdatedAt,
+		&referral.Version,
+	)
+}
+
+func (r ReferralRepository) GetByUserID(userID uuid.UUID) (*data.UserReferral, error) {
+	query := `
+	SELECT id, user_id, code, created_at, updated_at, version
+	FROM user_referrals
+	WHERE user_id = $1`
+
+	return r.get(query, userID)
+}
+
+// GetByCode looks the code up case-insensitively, codes are generated in
+// upper case but are often typed by hand.
+func (r ReferralRepository) GetByCode(code string) (*data.UserReferral, error) {
+	query := `
+	SELECT id, user_id, code, created_at, updated_at, version
+	FROM user_referrals
+	WHERE code = $1`
+
+	return r.get(query, strings.ToUpper(code))
+}
+
+func (r ReferralRepository) get(query string, arg interface{}) (*data.UserReferral, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var referral data.UserReferral
+	err := r.DBPOOL.QueryRow(ctx, query, arg).Scan(
+		&referral.ID,
+		&referral.UserID,
+		&referral.Code,
+		&referral.CreatedAt,
+		&referral.UpdatedAt,
+		&referral.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &referral, nil
+}
+
+// CountSignup credits the user that invited userID with one more signup
+// and, when the user came through a product share link, the owner of that
+// link as well. The inviter also receives the given bonus points. The
+// counters are incremented in place so concurrent signups never lose an
+// update. It returns the ids of every credited referrer.
+func (r ReferralRepository) CountSignup(userID uuid.UUID, points decimal.Decimal) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	credited := []uuid.UUID{}
+
+	query := `
+	UPDATE users AS referrer
+	SET
+		ref_signups = referrer.ref_signups + (invited.inv_ref_id IS NOT NULL)::int,
+		total_referrals = referrer.total_referrals + 1
+	FROM users AS invited
+	WHERE invited.id = $1 AND referrer.id = invited.invited_by_id
+	RETURNING referrer.id, invited.inv_ref_id, invited.inv_prod_ref_id`
+
+	var referrerID uuid.UUID
+	var referralID, productReferralID *uuid.UUID
+	err = tx.QueryRow(ctx, query, userID).Scan(&referrerID, &referralID, &productReferralID)
+	switch {
+	case err == nil:
+		credited = append(credited, referrerID)
+		if points.IsPositive() {
+			err = adjustPointsTx(ctx, tx, &data.BonusLedgerEntry{
+				UserID:            referrerID,
+				Delta:             points,
+				Reason:            constants.BonusReasonReferralSignup,
+				ReferralID:        referralID,
+				ProductReferralID: productReferralID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, pgx.ErrNoRows):
+	default:
+		return nil, err
+	}
+
+	query = `
+	WITH referral AS (
+		UPDATE user_product_referrals AS upr
+		SET signups = upr.signups + 1
+		FROM users AS invited
+		WHERE invited.id = $1 AND upr.id = invited.inv_prod_ref_id
+		RETURNING upr.user_id
+	)
+	UPDATE users
+	SET prod_ref_signups = prod_ref_signups + 1
+	FROM referral
+	WHERE users.id = referral.user_id
+	RETURNING users.id`
+
+	var productReferrerID uuid.UUID
+	err = tx.QueryRow(ctx, query, userID).Scan(&productReferrerID)
+	switch {
+	case err == nil:
+		credited = append(credited, productReferrerID)
+	case errors.Is(err, pgx.ErrNoRows):
+	default:
+		return nil, err
+	}
+
+	return credited, tx.Commit(ctx)
+}
+
+func (r ReferralRepository) Leaderboard(
+	f *requests.ReferralLeaderboardFilters,
+) ([]*data.ReferralLeaderboardEntry, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+	SELECT
+		count(*) OVER(),
+		id, phone, first_name, last_name,
+		ref_signups, prod_ref_signups, prod_ref_bought, total_referrals
+	FROM users`)
+
+	q.Where("total_referrals > 0")
+	q.OrderBy("total_referrals DESC", "ref_signups DESC", "id")
+	page, pageSize := f.PaginationFilter.Apply(q)
+
+	query, args := q.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	entries := []*data.ReferralLeaderboardEntry{}
+	for rows.Next() {
+		var entry data.ReferralLeaderboardEntry
+		err := rows.Scan(
+			&totalRecords,
+			&entry.UserID,
+			&entry.Phone,
+			&entry.FirstName,
+			&entry.LastName,
+			&entry.RefSignups,
+			&entry.ProdRefSignups,
+			&entry.ProdRefBought,
+			&entry.TotalReferrals,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
+
+	return entries, metadata, nil
+}
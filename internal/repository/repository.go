@@ -2,22 +2,59 @@ package repository
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 type Repositories struct {
-	Categories   CategoryRepository
-	Languages    LanguageRepository
-	Translations TranslationRepository
-	Users        UserRepository
-	Sessions     SessionRepository
+	Categories         CategoryRepository
+	Languages          LanguageRepository
+	Translations       TranslationRepository
+	Users              UserRepository
+	Sessions           SessionRepository
+	OTPs               OTPRepository
+	PasswordResets     PasswordResetRepository
+	LoginAttempts      LoginAttemptRepository
+	TOTPs              TOTPRepository
+	MFAChallenges      MFAChallengeRepository
+	Roles              RoleRepository
+	Referrals          ReferralRepository
+	ProductReferrals   ProductReferralRepository
+	Bonus              BonusRepository
+	Countries          CountryRepository
+	Customers          CustomerRepository
+	Audit              AuditRepository
+	Privacy            PrivacyRepository
+	EmailVerifications EmailVerificationRepository
+	NotificationPrefs  NotificationPreferenceRepository
+	Search             SearchRepository
+	Suggestions        SuggestionRepository
+	Cache              CacheRepository
 }
 
-func NewRepositories(dbpool *pgxpool.Pool) Repositories {
+func NewRepositories(dbpool *pgxpool.Pool, rdb *redis.Client) Repositories {
 	return Repositories{
-		Categories:   CategoryRepository{DBPOOL: dbpool},
-		Languages:    LanguageRepository{DBPOOL: dbpool},
-		Translations: TranslationRepository{DBPOOL: dbpool},
-		Users:        UserRepository{DBPOOL: dbpool},
-		Sessions:     SessionRepository{DBPOOL: dbpool},
+		Categories:         CategoryRepository{DBPOOL: dbpool},
+		Languages:          LanguageRepository{DBPOOL: dbpool},
+		Translations:       TranslationRepository{DBPOOL: dbpool},
+		Users:              UserRepository{DBPOOL: dbpool},
+		Sessions:           SessionRepository{DBPOOL: dbpool},
+		OTPs:               OTPRepository{RDB: rdb},
+		PasswordResets:     PasswordResetRepository{RDB: rdb},
+		LoginAttempts:      LoginAttemptRepository{RDB: rdb},
+		TOTPs:              TOTPRepository{DBPOOL: dbpool},
+		MFAChallenges:      MFAChallengeRepository{RDB: rdb},
+		Roles:              RoleRepository{DBPOOL: dbpool},
+		Referrals:          ReferralRepository{DBPOOL: dbpool},
+		ProductReferrals:   ProductReferralRepository{DBPOOL: dbpool},
+		Bonus:              BonusRepository{DBPOOL: dbpool},
+		Countries:          CountryRepository{DBPOOL: dbpool},
+		Customers:          CustomerRepository{DBPOOL: dbpool},
+		Audit:              AuditRepository{DBPOOL: dbpool},
+		Privacy:            PrivacyRepository{DBPOOL: dbpool},
+		EmailVerifications: EmailVerificationRepository{RDB: rdb},
+		NotificationPrefs:  NotificationPreferenceRepository{DBPOOL: dbpool},
+		Search:             SearchRepository{DBPOOL: dbpool},
+		Suggestions:        SuggestionRepository{RDB: rdb},
+		Cache:              CacheRepository{RDB: rdb},
 	}
 }
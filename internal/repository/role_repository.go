@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+type RoleRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// userRoleIDsSQL selects the ids of every role held by the user $1: roles
+// assigned in user_roles plus the roles implied by the legacy is_admin flag
+// and by a catalog_managers row.
+const userRoleIDsSQL = `
+	SELECT role_id FROM user_roles WHERE user_id = $1
+	UNION
+	SELECT id FROM roles
+	WHERE name = 'admin' AND EXISTS (SELECT 1 FROM users WHERE id = $1 AND is_admin)
+	UNION
+	SELECT id FROM roles
+	WHERE name = 'catalog_manager' AND EXISTS (SELECT 1 FROM catalog_managers WHERE user_id = $1)
+`
+
+const roleColumnsSQL = `
+	r.id, r.name, r.description, r.is_system,
+	COALESCE(
+		(SELECT array_agg(p.code ORDER BY p.code)
+		FROM role_permissions rp
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE rp.role_id = r.id),
+		'{}'
+	),
+	r.created_at, r.updated_at, r.created_by_id, r.updated_by_id, r.version
+`
+
+func scanRole(row pgx.Row) (*data.Role, error) {
+	var role data.Role
+	err := row.Scan(
+		&role.ID,
+		&role.Name,
+		&role.Description,
+		&role.IsSystem,
+		&role.Permissions,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+		&role.CreatedByID,
+		&role.UpdatedByID,
+		&role.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r RoleRepository) Create(role *data.Role) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	INSERT INTO roles (name, description, created_by_id, updated_by_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at, version`
+
+	err = tx.QueryRow(ctx, query, role.Name, role.Description, role.CreatedByID, role.UpdatedByID).Scan(
+		&role.ID,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+		&role.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = setRolePermissions(ctx, tx, role.ID, role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r RoleRepository) GetByID(id uuid.UUID) (*data.Role, error) {
+	query := `SELECT ` + roleColumnsSQL + ` FROM roles r WHERE r.id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	role, err := scanRole(r.DBPOOL.QueryRow(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return role, nil
+}
+
+func (r RoleRepository) List() ([]*data.Role, error) {
+	query := `SELECT ` + roleColumnsSQL + ` FROM roles r ORDER BY r.name`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*data.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// Update changes name, description and the full permission set of the role.
+func (r RoleRepository) Update(role *data.Role) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	UPDATE roles
+	SET name = $1, description = $2, updated_by_id = $3, version = version + 1
+	WHERE id = $4 AND version = $5
+	RETURNING updated_at, version`
+
+	err = tx.QueryRow(ctx, query, role.Name, role.Description, role.UpdatedByID, role.ID, role.Version).Scan(
+		&role.UpdatedAt,
+		&role.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM role_permissions WHERE role_id = $1`, role.ID)
+	if err != nil {
+		return err
+	}
+
+	err = setRolePermissions(ctx, tx, role.ID, role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete removes a role that is not a system role.
+func (r RoleRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM roles WHERE id = $1 AND is_system = FALSE`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r RoleRepository) ListPermissions() ([]*data.Permission, error) {
+	query := `SELECT id, code, description, created_at FROM permissions ORDER BY code`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []*data.Permission{}
+	for rows.Next() {
+		var p data.Permission
+		err := rows.Scan(&p.ID, &p.Code, &p.Description, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, &p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// GetUserPermissions returns the codes of every permission granted to the
+// user through any of their roles.
+func (r RoleRepository) GetUserPermissions(userID uuid.UUID) ([]string, error) {
+	query := `
+	SELECT DISTINCT p.code
+	FROM role_permissions rp
+	JOIN permissions p ON p.id = rp.permission_id
+	WHERE rp.role_id IN (` + userRoleIDsSQL + `)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := []string{}
+	for rows.Next() {
+		var code string
+		err := rows.Scan(&code)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (r RoleRepository) ListUserRoles(userID uuid.UUID) ([]*data.Role, error) {
+	query := `SELECT ` + roleColumnsSQL + `
+	FROM roles r
+	WHERE r.id IN (` + userRoleIDsSQL + `)
+	ORDER BY r.name`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*data.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r RoleRepository) AssignToUser(userID, roleID uuid.UUID, createdByID *uuid.UUID) error {
+	query := `
+	INSERT INTO user_roles (user_id, role_id, created_by_id)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, role_id) DO NOTHING`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.DBPOOL.Exec(ctx, query, userID, roleID, createdByID)
+	return err
+}
+
+func (r RoleRepository) RemoveFromUser(userID, roleID uuid.UUID) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, userID, roleID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func setRolePermissions(ctx context.Context, tx pgx.Tx, roleID uuid.UUID, codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO role_permissions (role_id, permission_id)
+	SELECT $1, id FROM permissions WHERE code = ANY($2)`
+
+	result, err := tx.Exec(ctx, query, roleID, codes)
+	if err != nil {
+		return err
+	}
+
+	if int(result.RowsAffected()) != len(codes) {
+		return common.ErrUnknownPermission
+	}
+
+	return nil
+}
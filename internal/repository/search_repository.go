@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+)
+
+type SearchRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// searchSource is a table the catalog search covers.
+type searchSource struct {
+	kind        string
+	table       string
+	description string
+	visible     string
+}
+
+var searchSources = []searchSource{
+	{requests.SearchKindProduct, "products", "e.description", "e.is_active"},
+	{requests.SearchKindCategory, "categories", "e.description", "e.deleted_at IS NULL"},
+	{requests.SearchKindBrand, "brands", "NULL::text", "TRUE"},
+}
+
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MinWords=10, MaxWords=25, MaxFragments=2"
+
+// searchSelect matches the rows of one source against the search term $1.
+// Base columns are matched with the 'simple' configuration, translations in
+// language $2 with the configuration of that language, and names also by
+// trigram similarity so that typos still find them.
+func searchSelect(s searchSource) string {
+	return fmt.Sprintf(`
+	SELECT
+		'%[1]s' AS kind,
+		e.id,
+		coalesce(tn.translated_value, e.name) AS name,
+		e.slug,
+		CASE
+			WHEN tn.id IS NULL AND td.id IS NULL THEN
+				ts_headline('simple', coalesce(%[3]s, e.name), sq.simple_query, '%[5]s')
+			ELSE
+				ts_headline(search_config($2), coalesce(td.translated_value, tn.translated_value), sq.language_query, '%[5]s')
+		END AS snippet,
+		greatest(
+			ts_rank(e.search_vector, sq.simple_query),
+			ts_rank(coalesce(tn.search_vector, '') || coalesce(td.search_vector, ''), sq.language_query)
+		) + greatest(
+			similarity(e.name, $1),
+			similarity(coalesce(tn.translated_value, ''), $1)
+		) AS rank
+	FROM %[2]s e
+	CROSS JOIN search_queries sq
+	LEFT JOIN translations tn
+		ON tn.table_name = '%[2]s' AND tn.entity_id = e.id
+		AND tn.language_code = $2 AND tn.field_name = 'name'
+	LEFT JOIN translations td
+		ON td.table_name = '%[2]s' AND td.entity_id = e.id
+		AND td.language_code = $2 AND td.field_name = 'description'
+	WHERE %[4]s AND (
+		e.search_vector @@ sq.simple_query OR
+		e.name %% $1 OR
+		e.id IN (
+			SELECT t.entity_id FROM translations t
+			WHERE t.table_name = '%[2]s' AND t.language_code = $2 AND (
+				t.search_vector @@ sq.language_query OR
+				(t.field_name = 'name' AND t.translated_value %% $1)
+			)
+		)
+	)`, s.kind, s.table, s.description, s.visible, searchHeadlineOptions)
+}
+
+// Catalog searches products, categories and brands, best matches first.
+func (r SearchRepository) Catalog(
+	f *requests.CatalogSearchFilters,
+	langCode string,
+) ([]*data.SearchHit, types.PaginationMetadata, error) {
+	selects := []string{}
+	for _, source := range searchSources {
+		if len(f.Kinds) == 0 || slices.Contains(f.Kinds, source.kind) {
+			selects = append(selects, searchSelect(source))
+		}
+	}
+
+	q := filters.NewQuery(`
+	WITH search_queries AS (
+		SELECT
+			websearch_to_tsquery('simple', $1) AS simple_query,
+			websearch_to_tsquery(search_config($2), $1) AS language_query
+	)
+	SELECT count(*) OVER(), kind, id, name, slug, snippet, rank
+	FROM (` + strings.Join(selects, "\n\tUNION ALL") + `
+	) hits`)
+	q.Arg(f.Query)
+	q.Arg(langCode)
+
+	q.OrderBy("rank DESC", "kind", "id")
+	page, pageSize := f.PaginationFilter.Apply(q)
+
+	query, args := q.Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	hits := []*data.SearchHit{}
+	for rows.Next() {
+		var hit data.SearchHit
+		err := rows.Scan(
+			&totalRecords,
+			&hit.Kind,
+			&hit.ID,
+			&hit.Name,
+			&hit.Slug,
+			&hit.Snippet,
+			&hit.Rank,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		hits = append(hits, &hit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
+
+	return hits, metadata, nil
+}
+
+// Exists reports whether ref is a catalog entity the search can show.
+func (r SearchRepository) Exists(ref data.CatalogRef) (bool, error) {
+	i := slices.IndexFunc(searchSources, func(s searchSource) bool {
+		return s.kind == ref.Kind
+	})
+	if i < 0 {
+		return false, nil
+	}
+	source := searchSources[i]
+
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s e WHERE e.id = $1 AND %s)`, source.table, source.visible)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	err := r.DBPOOL.QueryRow(ctx, query, ref.ID).Scan(&exists)
+	return exists, err
+}
+
+// Suggestions returns every searchable catalog entity named in every
+// language, keyed by language code. Entities without a translation in a
+// language keep their own name.
+func (r SearchRepository) Suggestions() (map[string][]*data.Suggestion, error) {
+	query := `
+	WITH entities AS (
+		SELECT 'product' AS kind, 'products' AS table_name, id, slug, name
+		FROM products WHERE is_active
+		UNION ALL
+		SELECT 'category', 'categories', id, slug, name
+		FROM categories WHERE deleted_at IS NULL
+		UNION ALL
+		SELECT 'brand', 'brands', id, slug, name
+		FROM brands
+	)
+	SELECT l.code, e.kind, e.id, e.slug, coalesce(t.translated_value, e.name)
+	FROM entities e
+	CROSS JOIN languages l
+	LEFT JOIN translations t
+		ON t.table_name = e.table_name AND t.entity_id = e.id
+		AND t.language_code = l.code AND t.field_name = 'name'
+	WHERE l.deleted_at IS NULL`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows, err := r.DBPOOL.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := map[string][]*data.Suggestion{}
+	for rows.Next() {
+		var langCode string
+		var s data.Suggestion
+		err := rows.Scan(&langCode, &s.Kind, &s.ID, &s.Slug, &s.Name)
+		if err != nil {
+			return nil, err
+		}
+		suggestions[langCode] = append(suggestions[langCode], &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
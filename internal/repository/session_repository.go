@@ -135,6 +135,20 @@ func (r SessionRepository) RevokeSessionByID(id uuid.UUID) error {
 	return nil
 }
 
+// RevokeAllByUserPhone revokes every active session of the user, e.g. after
+// a password change.
+func (r SessionRepository) RevokeAllByUserPhone(phone string) error {
+	query := `UPDATE sessions
+	SET is_revoked = true
+	WHERE user_phone = $1 AND is_revoked = false`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.DBPOOL.Exec(ctx, query, phone)
+	return err
+}
+
 func (r SessionRepository) DeleteByID(id uuid.UUID) error {
 	query := `
 	DELETE FROM categories
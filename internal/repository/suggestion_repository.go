@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/redis/go-redis/v9"
+)
+
+// SuggestionRepository keeps the type-ahead index in Redis. Every language
+// has a sorted set whose members all score 0, so that Redis orders them
+// lexicographically and a prefix is a ZRANGEBYLEX range. A member starts
+// with the normalized text it matches, followed by the suggestion itself.
+type SuggestionRepository struct {
+	RDB *redis.Client
+}
+
+const (
+	suggestionPopularityKey = "search_popularity"
+	suggestionSeparator     = "\x00"
+)
+
+func suggestionIndexKey(langCode string) string {
+	return fmt.Sprintf("search_suggest:%s", langCode)
+}
+
+func suggestionPopularityMember(ref data.CatalogRef) string {
+	return ref.Kind + ":" + ref.ID.String()
+}
+
+// normalizeSuggestion lowercases s and collapses its whitespace, the form
+// both indexed names and typed prefixes are compared in.
+func normalizeSuggestion(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// suggestionMembers returns the index members of s: one for the whole name
+// and one for every later word, so that "pro" finds "iPhone 15 Pro".
+func suggestionMembers(s *data.Suggestion) []string {
+	suffix := strings.Join([]string{s.Kind, s.ID.String(), s.Slug, s.Name}, suggestionSeparator)
+
+	words := strings.Fields(normalizeSuggestion(s.Name))
+	members := make([]string, len(words))
+	for i := range words {
+		members[i] = strings.Join(words[i:], " ") + suggestionSeparator + suffix
+	}
+	return members
+}
+
+func parseSuggestionMember(member string) (*data.Suggestion, error) {
+	parts := strings.Split(member, suggestionSeparator)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("malformed suggestion %q", member)
+	}
+
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.Suggestion{
+		CatalogRef: data.CatalogRef{Kind: parts[1], ID: id},
+		Slug:       parts[3],
+		Name:       parts[4],
+	}, nil
+}
+
+// Replace swaps the index of a language for one built from suggestions.
+// The new index is written under a temporary key and renamed over the old
+// one, so readers never see a half built index.
+func (r SuggestionRepository) Replace(langCode string, suggestions []*data.Suggestion) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	key := suggestionIndexKey(langCode)
+	if len(suggestions) == 0 {
+		return r.RDB.Del(ctx, key).Err()
+	}
+
+	tmpKey := key + ":building:" + uuid.NewString()
+
+	members := []redis.Z{}
+	for _, s := range suggestions {
+		for _, member := range suggestionMembers(s) {
+			members = append(members, redis.Z{Member: member})
+		}
+	}
+
+	pipe := r.RDB.Pipeline()
+	for start := 0; start < len(members); start += 1000 {
+		end := min(start+1000, len(members))
+		pipe.ZAdd(ctx, tmpKey, members[start:end]...)
+	}
+	pipe.Rename(ctx, tmpKey, key)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		r.RDB.Del(ctx, tmpKey)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveExcept deletes the indexes of every language not in langCodes,
+// such as languages that were deleted or no longer have anything to
+// suggest. Indexes that are still being built are left alone.
+func (r SuggestionRepository) RemoveExcept(langCodes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	keep := map[string]bool{}
+	for _, langCode := range langCodes {
+		keep[suggestionIndexKey(langCode)] = true
+	}
+
+	stale := []string{}
+	iter := r.RDB.Scan(ctx, 0, suggestionIndexKey("*"), 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if keep[key] || strings.Contains(key, ":building:") {
+			continue
+		}
+		stale = append(stale, key)
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+	return r.RDB.Del(ctx, stale...).Err()
+}
+
+// Prefix returns up to limit suggestions of a language whose name, or a
+// later word of it, starts with prefix, most popular first.
+func (r SuggestionRepository) Prefix(langCode, prefix string, limit int) ([]*data.Suggestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prefix = normalizeSuggestion(prefix)
+	if prefix == "" {
+		return []*data.Suggestion{}, nil
+	}
+
+	// Popularity is only known after the lexicographic lookup, so more
+	// candidates than asked for are fetched and ranked here.
+	members, err := r.RDB.ZRangeByLex(ctx, suggestionIndexKey(langCode), &redis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: int64(limit * 20),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	suggestions := []*data.Suggestion{}
+	popularityMembers := []string{}
+	for _, member := range members {
+		s, err := parseSuggestionMember(member)
+		if err != nil {
+			return nil, err
+		}
+		popularityMember := suggestionPopularityMember(s.CatalogRef)
+		if seen[popularityMember] {
+			continue
+		}
+		seen[popularityMember] = true
+		suggestions = append(suggestions, s)
+		popularityMembers = append(popularityMembers, popularityMember)
+	}
+
+	if len(suggestions) == 0 {
+		return suggestions, nil
+	}
+
+	scores, err := r.RDB.ZMScore(ctx, suggestionPopularityKey, popularityMembers...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range suggestions {
+		s.Popularity = scores[i]
+	}
+
+	return rankSuggestions(suggestions, limit), nil
+}
+
+// rankSuggestions orders suggestions by popularity, then shorter names
+// first, as the closest to what was typed, and cuts them to limit.
+func rankSuggestions(suggestions []*data.Suggestion, limit int) []*data.Suggestion {
+	slices.SortStableFunc(suggestions, func(a, b *data.Suggestion) int {
+		if a.Popularity != b.Popularity {
+			return cmp.Compare(b.Popularity, a.Popularity)
+		}
+		if len(a.Name) != len(b.Name) {
+			return cmp.Compare(len(a.Name), len(b.Name))
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// AddPopularity increases the popularity of catalog entities by weight.
+// Popularity is shared by all languages and survives index rebuilds.
+func (r SuggestionRepository) AddPopularity(refs []data.CatalogRef, weight float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if len(refs) == 0 {
+		return nil
+	}
+
+	pipe := r.RDB.Pipeline()
+	for _, ref := range refs {
+		pipe.ZIncrBy(ctx, suggestionPopularityKey, weight, suggestionPopularityMember(ref))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// PrunePopularity drops the popularity of every entity not in refs, the
+// entities the catalog can currently suggest.
+func (r SuggestionRepository) PrunePopularity(refs []data.CatalogRef) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	keep := map[string]bool{}
+	for _, ref := range refs {
+		keep[suggestionPopularityMember(ref)] = true
+	}
+
+	members, err := r.RDB.ZRange(ctx, suggestionPopularityKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	stale := []interface{}{}
+	for _, member := range members {
+		if !keep[member] {
+			stale = append(stale, member)
+		}
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+	return r.RDB.ZRem(ctx, suggestionPopularityKey, stale...).Err()
+}
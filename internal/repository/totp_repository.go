@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+type TOTPRepository struct {
+	DBPOOL *pgxpool.Pool
+}
+
+// SavePending stores a new, not yet enabled secret for the user. An enabled
+// secret is never overwritten; common.ErrTOTPAlreadyEnabled is returned
+// instead.
+func (r TOTPRepository) SavePending(userID uuid.UUID, secretEncrypted []byte) error {
+	query := `
+	INSERT INTO user_totp (user_id, secret_encrypted)
+	VALUES ($1, $2)
+	ON CONFLICT (user_id) DO UPDATE
+	SET secret_encrypted = EXCLUDED.secret_encrypted, last_used_step = 0
+	WHERE user_totp.is_enabled = FALSE
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, userID, secretEncrypted)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrTOTPAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r TOTPRepository) GetByUserID(userID uuid.UUID) (*data.UserTOTP, error) {
+	query := `
+	SELECT user_id, secret_encrypted, is_enabled, last_used_step, created_at, updated_at, enabled_at
+	FROM user_totp
+	WHERE user_id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var totp data.UserTOTP
+	err := r.DBPOOL.QueryRow(ctx, query, userID).Scan(
+		&totp.UserID,
+		&totp.SecretEncrypted,
+		&totp.IsEnabled,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+		&totp.UpdatedAt,
+		&totp.EnabledAt,
+	)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, common.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &totp, nil
+}
+
+// UseStep records step as the last used one. It fails with
+// common.ErrTOTPInvalid when step is not newer than the stored one, which
+// stops a code from being replayed within its validity window.
+func (r TOTPRepository) UseStep(userID uuid.UUID, step int64) error {
+	query := `
+	UPDATE user_totp
+	SET last_used_step = $2
+	WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrTOTPInvalid
+	}
+
+	return nil
+}
+
+func (r TOTPRepository) Enable(userID uuid.UUID) error {
+	query := `
+	UPDATE user_totp
+	SET is_enabled = TRUE, enabled_at = NOW()
+	WHERE user_id = $1 AND is_enabled = FALSE
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the secret together with all recovery codes of the user.
+func (r TOTPRepository) Delete(userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrRecordNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReplaceRecoveryCodes drops every existing recovery code of the user and
+// stores the given hashes instead.
+func (r TOTPRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err = tx.Exec(
+			ctx,
+			`INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`,
+			userID,
+			codeHash,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+func (r TOTPRepository) UseRecoveryCode(userID uuid.UUID, codeHash string) error {
+	query := `
+	UPDATE user_recovery_codes
+	SET used_at = NOW()
+	WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrTOTPInvalid
+	}
+
+	return nil
+}
@@ -20,7 +20,7 @@ type TranslationRepository struct {
 	DBPOOL *pgxpool.Pool
 }
 
-func (r TranslationRepository) Create(translation *data.Translation) error {
+func (r TranslationRepository) Create(translation *data.Translation, audit data.AuditFunc) error {
 	query := `
 		INSERT INTO translations (
 			language_code,
@@ -49,15 +49,17 @@ func (r TranslationRepository) Create(translation *data.Translation) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	return r.DBPOOL.QueryRow(ctx, query, args).Scan(
-		&translation.ID,
-		&translation.LanguageCode,
-		&translation.TableName,
-		&translation.FieldName,
-		&translation.TranslatedFieldName,
-		&translation.TranslatedValue,
-		&translation.Version,
-	)
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args).Scan(
+			&translation.ID,
+			&translation.LanguageCode,
+			&translation.TableName,
+			&translation.FieldName,
+			&translation.TranslatedFieldName,
+			&translation.TranslatedValue,
+			&translation.Version,
+		)
+	})
 }
 
 func (r TranslationRepository) GetByID(id uuid.UUID) (*data.Translation, error) {
@@ -98,58 +100,52 @@ func (r TranslationRepository) GetByID(id uuid.UUID) (*data.Translation, error)
 	return &translation, nil
 }
 
-func (r TranslationRepository) List(f *requests.TranslationsAdminFilters) ([]*data.Translation, types.PaginationMetadata, error) {
-
-	query := `
-		SELECT COUNT(*) OVER(), * 
-		FROM translations
-		WHERE 1=1
-	`
-
-	args := []interface{}{}
-	argCounter := 1
-
-	if len(f.LanguageCodes) > 0 {
-		query += fmt.Sprintf(" AND language_code = ANY($%d)", argCounter)
-		args = append(args, f.LanguageCodes)
-		argCounter++
-	}
-
-	if len(f.TableNames) > 0 {
-		query += fmt.Sprintf(" AND table_name = ANY($%d)", argCounter)
-		args = append(args, f.TableNames)
-		argCounter++
-	}
+func (r TranslationRepository) List(f *requests.TranslationsAdminFilters, keyset *filters.Keyset) ([]*data.Translation, types.PaginationMetadata, error) {
 
-	if len(f.FieldNames) > 0 {
-		query += fmt.Sprintf(" AND field_name = ANY($%d)", argCounter)
-		args = append(args, f.FieldNames)
-		argCounter++
-	}
+	q := filters.NewQuery(`
+		SELECT
+			` + keyset.Columns() + `,
+			id,
+			language_code,
+			entity_id,
+			table_name,
+			field_name,
+			translated_field_name,
+			translated_value,
+			created_at,
+			updated_at,
+			created_by_id,
+			updated_by_id,
+			version
+		FROM translations`)
 
-	if len(f.EntityIDs) > 0 {
-		query += fmt.Sprintf(" AND entity_id = ANY($%d)", argCounter)
-		args = append(args, f.EntityIDs)
-		argCounter++
+	filters.AnyOf(q, "language_code", f.LanguageCodes)
+	filters.AnyOf(q, "table_name", f.TableNames)
+	filters.AnyOf(q, "field_name", f.FieldNames)
+	filters.AnyOf(q, "entity_id", f.EntityIDs)
+	if f.Search != nil {
+		p := q.Arg(*f.Search)
+		q.Where(fmt.Sprintf(`(
+			id::text = %[1]s OR
+			to_tsvector('simple', table_name) @@ plainto_tsquery('simple', %[1]s) OR
+			to_tsvector('simple', field_name) @@ plainto_tsquery('simple', %[1]s) OR
+			to_tsvector('simple', translated_field_name) @@ plainto_tsquery('simple', %[1]s) OR
+			to_tsvector('simple', translated_value) @@ plainto_tsquery('simple', %[1]s)
+		)`, p))
 	}
 
-	if f.Search != nil {
-		query += fmt.Sprintf(` 
-		AND (
-			to_tsvector('simple', id) @@ plainto_tsquery('simple', $%d) OR 
-			to_tsvector('simple', table_name) @@ plainto_tsquery('simple', $%d) OR 
-			to_tsvector('simple', field_name) @@ plainto_tsquery('simple', $%d) OR  
-			to_tsvector('simple', translated_field_name) @@ plainto_tsquery('simple', $%d) OR 
-			to_tsvector('simple', translated_value) @@ plainto_tsquery('simple', $%d)
-		)`, argCounter, argCounter, argCounter, argCounter, argCounter)
-		args = append(args, *f.Search)
-		argCounter++
+	f.CreatedUpdatedAtFilter.Apply(q)
+	f.CreatedUpdatedByFilter.Apply(q)
+	f.FieldFilter.Apply(q)
+	var page, pageSize int
+	if keyset != nil {
+		keyset.Apply(q)
+	} else {
+		f.SortListFilter.Apply(q)
+		page, pageSize = f.PaginationFilter.Apply(q)
 	}
 
-	filters.AddCreatedUpdateAtFilterToSQL(&f.CreatedUpdatedAtFilter, &query, &argCounter, args)
-	filters.AddCreatedUpdateByFilterToSQL(&f.CreatedUpdatedByFilter, &query, &argCounter, args)
-	filters.AddSortListFilterToSQL(&f.SortListFilter, &query)
-	filters.AddPaginationFilterToSQL(&f.PaginationFilter, &query, &argCounter, args)
+	query, args := q.Build()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -161,12 +157,15 @@ func (r TranslationRepository) List(f *requests.TranslationsAdminFilters) ([]*da
 	defer rows.Close()
 
 	totalRecords := 0
+	cursorValues := [][]*string{}
 	trs := []*data.Translation{}
 
 	for rows.Next() {
 		var tr data.Translation
+		var values []*string
 		err := rows.Scan(
 			&totalRecords,
+			&values,
 			&tr.ID,
 			&tr.LanguageCode,
 			&tr.EntityID,
@@ -184,19 +183,25 @@ func (r TranslationRepository) List(f *requests.TranslationsAdminFilters) ([]*da
 			return nil, types.PaginationMetadata{}, err
 		}
 		trs = append(trs, &tr)
+		cursorValues = append(cursorValues, values)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, types.PaginationMetadata{}, err
 	}
 
-	metadata := common.CalculateMetadata(totalRecords, *f.Page, *f.PageSize)
+	if keyset != nil {
+		trs, metadata := filters.KeysetPage(keyset, trs, cursorValues)
+		return trs, metadata, nil
+	}
+
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
 
 	return trs, metadata, nil
 
 }
 
-func (r TranslationRepository) Update(tr *data.Translation) error {
+func (r TranslationRepository) Update(tr *data.Translation, audit data.AuditFunc) error {
 	query := `
 		UPDATE translations
 		SET
@@ -227,30 +232,25 @@ func (r TranslationRepository) Update(tr *data.Translation) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := r.DBPOOL.QueryRow(ctx, query, args).Scan(
-		&tr.LanguageCode,
-		&tr.EntityID,
-		&tr.TableName,
-		&tr.FieldName,
-		&tr.TranslatedFieldName,
-		&tr.TranslatedValue,
-		&tr.UpdatedByID,
-		&tr.Version,
-	)
-
-	if err != nil {
-		switch {
-		case errors.Is(err, pgx.ErrNoRows):
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, args).Scan(
+			&tr.LanguageCode,
+			&tr.EntityID,
+			&tr.TableName,
+			&tr.FieldName,
+			&tr.TranslatedFieldName,
+			&tr.TranslatedValue,
+			&tr.UpdatedByID,
+			&tr.Version,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
 			return common.ErrRecordNotFound
-		default:
-			return err
 		}
-	}
-
-	return nil
+		return err
+	})
 }
 
-func (r TranslationRepository) Delete(id uuid.UUID) error {
+func (r TranslationRepository) Delete(id uuid.UUID, audit data.AuditFunc) error {
 	query := `
 		DELETE FROM translations
 		WHERE id = $1
@@ -259,17 +259,18 @@ func (r TranslationRepository) Delete(id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	result, err := r.DBPOOL.Exec(ctx, query, id)
-	if err != nil {
-		return err
-	}
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, id)
+		if err != nil {
+			return err
+		}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected < 1 {
-		return common.ErrRecordNotFound
-	}
+		if result.RowsAffected() < 1 {
+			return common.ErrRecordNotFound
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func (r TranslationRepository) GetByEntityIDLangCodeFieldName(entityID uuid.UUID, languageCode, fieldName string) (*data.Translation, error) {
@@ -2,9 +2,9 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,76 +22,74 @@ type UserRepository struct {
 	DBPOOL *pgxpool.Pool
 }
 
-func (r UserRepository) Create(user *data.User) error {
+func (r UserRepository) Create(user *data.User, audit data.AuditFunc) error {
 	passwordHashBytes, err := auth.GeneratePasswordHash(user.Password)
 	if err != nil {
 		return err
 	}
 
-	args := []interface{}{
-		user.Phone,
-		passwordHashBytes,
-		user.FirstName,
-		user.LastName,
-		user.Patronomic,
-		user.Email,
-		user.IsActive,
-	}
-
 	query := `
 		INSERT INTO users (
-			phone, 
-			password_hash, 
+			phone,
+			password_hash,
 			first_name,
 			last_name,
-			patronmic,
+			patronymic,
 			email,
-			is_active 
+			is_active,
+			invited_by_id,
+			inv_ref_id,
+			inv_prod_ref_id,
+			created_by_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, phone, first_name, last_name, patronomic, email, is_active, created_by_id
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at, version
 	`
 
-	if user.CreatedByID != nil {
-		query = `
-		INSERT INTO users (
-			phone, 
-			password_hash, 
-			first_name,
-			last_name,
-			patronmic,
-			email,
-			is_active, 
-			created_by_id
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, phone, first_name, last_name, patronomic, email, is_active, created_by_id
-		`
-		args = append(args, *user.CreatedByID)
+	args := []interface{}{
+		user.Phone,
+		passwordHashBytes,
+		user.FirstName,
+		user.LastName,
+		user.Patronomic,
+		user.Email,
+		user.IsActive,
+		user.InvitedByID,
+		user.InvRefID,
+		user.InvProdRefID,
+		user.CreatedByID,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = r.DBPOOL.QueryRow(ctx, query, args...).Scan(
-		&user.ID,
-		&user.Phone,
-		&user.FirstName,
-		&user.LastName,
-		&user.Patronomic,
-		&user.Email,
-		&user.IsActive,
-		&user.CreatedByID,
-	)
+	err = withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args...).Scan(
+			&user.ID,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.Version,
+		)
+	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	user.PasswordHash = passwordHashBytes
+	return nil
 }
 
 func (r UserRepository) GetByID(id uuid.UUID) (*data.User, error) {
 	query := `
-	SELECT * 
+	SELECT
+		id, phone, password_hash, first_name, last_name, patronymic, dob, email, email_verified_at,
+		phone_verified_at, is_active, is_banned, is_trusted, invited_by_id, inv_ref_id, inv_prod_ref_id,
+		ref_signups, prod_ref_signups, prod_ref_bought, total_referrals,
+		_dynamic_discount_percent, dyn_disc_percent, bonus_points,
+		is_staff, is_admin, is_superuser, created_at, updated_at,
+		created_by_id, updated_by_id, version
 	FROM users
-	WHERE id = $1
+	WHERE id = $1 AND deleted_at IS NULL
 	`
 	var user data.User
 
@@ -101,11 +99,14 @@ func (r UserRepository) GetByID(id uuid.UUID) (*data.User, error) {
 	err := r.DBPOOL.QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Phone,
+		&user.PasswordHash,
 		&user.FirstName,
 		&user.LastName,
 		&user.Patronomic,
 		&user.DOB,
 		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
 		&user.IsActive,
 		&user.IsBanned,
 		&user.IsTrusted,
@@ -143,9 +144,15 @@ func (r UserRepository) GetByID(id uuid.UUID) (*data.User, error) {
 
 func (r UserRepository) GetByPhone(phone string) (*data.User, error) {
 	query := `
-	SELECT * 
+	SELECT
+		id, phone, password_hash, first_name, last_name, patronymic, dob, email, email_verified_at,
+		phone_verified_at, is_active, is_banned, is_trusted, invited_by_id, inv_ref_id, inv_prod_ref_id,
+		ref_signups, prod_ref_signups, prod_ref_bought, total_referrals,
+		_dynamic_discount_percent, dyn_disc_percent, bonus_points,
+		is_staff, is_admin, is_superuser, created_at, updated_at,
+		created_by_id, updated_by_id, version
 	FROM users
-	WHERE phone = $1
+	WHERE phone = $1 AND deleted_at IS NULL
 	`
 	var user data.User
 
@@ -155,12 +162,14 @@ func (r UserRepository) GetByPhone(phone string) (*data.User, error) {
 	err := r.DBPOOL.QueryRow(ctx, query, phone).Scan(
 		&user.ID,
 		&user.Phone,
+		&user.PasswordHash,
 		&user.FirstName,
 		&user.LastName,
 		&user.Patronomic,
-		&user.PasswordHash,
 		&user.DOB,
 		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
 		&user.IsActive,
 		&user.IsBanned,
 		&user.IsTrusted,
@@ -196,35 +205,43 @@ func (r UserRepository) GetByPhone(phone string) (*data.User, error) {
 	return &user, nil
 }
 
-func (r UserRepository) List(f *requests.UsersAdminFilters) ([]*data.User, types.PaginationMetadata, error) {
-	query := `SELECT *
-	FROM users
-	WHERE 1=1`
-
-	args := []interface{}{}
-	argCounter := 1
-
-	addUserSpecificFiltersToSQL(f, &query, &argCounter, args)
+func (r UserRepository) List(f *requests.UsersAdminFilters, keyset *filters.Keyset) ([]*data.User, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+	SELECT
+		` + keyset.Columns() + `,
+		id, phone, first_name, last_name, patronymic, dob, email,
+		is_active, is_banned, is_trusted, invited_by_id, inv_ref_id, inv_prod_ref_id,
+		ref_signups, prod_ref_signups, prod_ref_bought, total_referrals,
+		_dynamic_discount_percent, dyn_disc_percent, bonus_points,
+		is_staff, is_admin, is_superuser, created_at, updated_at,
+		created_by_id, updated_by_id, version
+	FROM users`)
+
+	q.Where("deleted_at IS NULL")
+	applyUserFilters(q, f)
 
 	if f.Search != nil {
-		query += fmt.Sprintf(
-			` AND (
-				id = $%d OR 
-				to_tsvector('simple', phone) @@ plainto_tsquery('phone', $%d) OR 
-				to_tsvector('simple', email) @@ plainto_tsquery('email', $%d) OR 
-				to_tsvector('simple', first_name) @@ plainto_tsquery('first_name', $%d) OR 
-				to_tsvector('simple', last_name) @@ plainto_tsquery('last_name', $%d) OR 
-				to_tsvector('simple', first_name || ' ' || last_name) @@ plainto_tsquery('simple', $%d) OR
-			)`, argCounter, argCounter, argCounter, argCounter, argCounter, argCounter,
-		)
-		args = append(args, *f.Search)
-		argCounter++
+		p := q.Arg(*f.Search)
+		q.Where(fmt.Sprintf(`(
+			id::text = %[1]s OR
+			phone = %[1]s OR
+			email = %[1]s OR
+			to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, '')) @@ plainto_tsquery('simple', %[1]s)
+		)`, p))
+	}
+
+	f.CreatedUpdatedAtFilter.Apply(q)
+	f.CreatedUpdatedByFilter.Apply(q)
+	f.FieldFilter.Apply(q)
+	var page, pageSize int
+	if keyset != nil {
+		keyset.Apply(q)
+	} else {
+		f.SortListFilter.Apply(q)
+		page, pageSize = f.PaginationFilter.Apply(q)
 	}
 
-	filters.AddCreatedUpdateAtFilterToSQL(&f.CreatedUpdatedAtFilter, &query, &argCounter, args)
-	filters.AddCreatedUpdateByFilterToSQL(&f.CreatedUpdatedByFilter, &query, &argCounter, args)
-	filters.AddSortListFilterToSQL(&f.SortListFilter, &query)
-	filters.AddPaginationFilterToSQL(&f.PaginationFilter, &query, &argCounter, args)
+	query, args := q.Build()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -233,12 +250,18 @@ func (r UserRepository) List(f *requests.UsersAdminFilters) ([]*data.User, types
 	if err != nil {
 		return nil, types.PaginationMetadata{}, err
 	}
+	defer rows.Close()
 
+	totalRecords := 0
+	cursorValues := [][]*string{}
 	users := []*data.User{}
 
 	for rows.Next() {
 		var user data.User
+		var values []*string
 		err := rows.Scan(
+			&totalRecords,
+			&values,
 			&user.ID,
 			&user.Phone,
 			&user.FirstName,
@@ -272,29 +295,49 @@ func (r UserRepository) List(f *requests.UsersAdminFilters) ([]*data.User, types
 			return nil, types.PaginationMetadata{}, err
 		}
 		users = append(users, &user)
+		cursorValues = append(cursorValues, values)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, types.PaginationMetadata{}, err
 	}
 
-	metadata := common.CalculateMetadata(len(users), *f.Page, *f.PageSize)
+	if keyset != nil {
+		users, metadata := filters.KeysetPage(keyset, users, cursorValues)
+		return users, metadata, nil
+	}
+
+	metadata := common.CalculateMetadata(totalRecords, page, pageSize)
 
 	return users, metadata, nil
 }
 
-func (r UserRepository) Update(user *data.User) error {
+func (r UserRepository) Update(user *data.User, audit data.AuditFunc) error {
+	// Passwords are changed only through UpdatePassword, so that every
+	// password change also goes through session revocation.
 	query := `UPDATE users
 		SET 
 			phone = $1,
 			first_name = $2,
 			last_name = $3,
-			patronomic = $4,
-			email = $5
+			patronymic = $4,
+			email = $5,
+			email_verified_at = CASE
+				WHEN email IS DISTINCT FROM $5 THEN NULL
+				ELSE email_verified_at
+			END,
 			is_active = $6,
 			updated_by_id = $7,
+			updated_at = NOW(),
 			version = version + 1
-		WHERE id = $8 AND version = $9
+		WHERE id = $8 AND version = $9 AND deleted_at IS NULL
+		RETURNING
+			id, phone, first_name, last_name, patronymic, dob, email, email_verified_at,
+			phone_verified_at, is_active, is_banned, is_trusted, invited_by_id, inv_ref_id, inv_prod_ref_id,
+			ref_signups, prod_ref_signups, prod_ref_bought, total_referrals,
+			_dynamic_discount_percent, dyn_disc_percent, bonus_points,
+			is_staff, is_admin, is_superuser, created_at, updated_at,
+			created_by_id, updated_by_id, version
 		`
 
 	args := []interface{}{
@@ -309,88 +352,155 @@ func (r UserRepository) Update(user *data.User) error {
 		user.Version,
 	}
 
-	if user.Password != "" {
-		user.Password = strings.TrimSpace(user.Password)
-		passwordHashBytes, err := auth.GeneratePasswordHash(user.Password)
-		if err != nil {
-			return err
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return withAudit(ctx, r.DBPOOL, audit, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, args...).Scan(
+			&user.ID,
+			&user.Phone,
+			&user.FirstName,
+			&user.LastName,
+			&user.Patronomic,
+			&user.DOB,
+			&user.Email,
+			&user.EmailVerifiedAt,
+			&user.PhoneVerifiedAt,
+			&user.IsActive,
+			&user.IsBanned,
+			&user.IsTrusted,
+			&user.InvitedByID,
+			&user.InvRefID,
+			&user.InvProdRefID,
+			&user.RefSignups,
+			&user.ProdRefSignups,
+			&user.ProdRefBought,
+			&user.TotalRefferals,
+			&user.WholeDynDiscPercent,
+			&user.DynDiscPercent,
+			&user.BonusPoints,
+			&user.IsStaff,
+			&user.IsAdmin,
+			&user.IsSuperuser,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.CreatedByID,
+			&user.UpdatedByID,
+			&user.Version,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return common.ErrEditConflict
 		}
+		return err
+	})
+}
 
-		query = `UPDATE users
-		SET 
-			phone = $1,
-			password = $2,
-			first_name = $3,
-			last_name = $4,
-			patronomic = $5,
-			email = $6
-			is_active = $7,
-			updated_by_id = $8,
-			version = version + 1,
-		WHERE id = $9 AND version = $10
-		`
+func (r UserRepository) UpdatePassword(id uuid.UUID, password string) error {
+	passwordHashBytes, err := auth.GeneratePasswordHash(password)
+	if err != nil {
+		return err
+	}
 
-		args = []interface{}{
-			user.Phone,
-			passwordHashBytes,
-			user.FirstName,
-			user.LastName,
-			user.Patronomic,
-			user.Email,
-			user.IsActive,
-			user.UpdatedByID,
-			user.ID,
-			user.Version,
-		}
+	query := `UPDATE users
+		SET password_hash = $1, updated_at = NOW(), version = version + 1
+		WHERE id = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.DBPOOL.Exec(ctx, query, passwordHashBytes, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrRecordNotFound
 	}
 
+	return nil
+}
+
+// MarkEmailVerified marks email as verified for the user. It returns
+// ErrRecordNotFound when the user no longer has that email, so a link sent
+// to an old address cannot verify a new one.
+func (r UserRepository) MarkEmailVerified(id uuid.UUID, email string) error {
+	query := `UPDATE users
+		SET email_verified_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND email = $2 AND deleted_at IS NULL`
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := r.DBPOOL.QueryRow(ctx, query, args...).Scan(
-		&user.ID,
-		&user.Phone,
-		&user.FirstName,
-		&user.LastName,
-		&user.Patronomic,
-		&user.DOB,
-		&user.Email,
-		&user.IsActive,
-		&user.IsBanned,
-		&user.IsTrusted,
-		&user.InvitedByID,
-		&user.InvRefID,
-		&user.InvProdRefID,
-		&user.RefSignups,
-		&user.ProdRefSignups,
-		&user.ProdRefBought,
-		&user.TotalRefferals,
-		&user.WholeDynDiscPercent,
-		&user.DynDiscPercent,
-		&user.BonusPoints,
-		&user.IsStaff,
-		&user.IsAdmin,
-		&user.IsSuperuser,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&user.CreatedByID,
-		&user.UpdatedByID,
-		&user.Version,
-	)
+	result, err := r.DBPOOL.Exec(ctx, query, id, email)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() < 1 {
+		return common.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UpdatePhone moves the user to a phone they have just confirmed and
+// revokes every session opened with the old one, in one transaction.
+// Sessions are keyed by phone, so they would otherwise outlive the change.
+func (r UserRepository) UpdatePhone(id uuid.UUID, phone string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
+	var oldPhone string
+	err = tx.QueryRow(
+		ctx,
+		`SELECT phone FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		id,
+	).Scan(&oldPhone)
 	if err != nil {
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
-			return common.ErrEditConflict
+			return common.ErrRecordNotFound
 		default:
 			return err
 		}
 	}
-	return nil
+
+	query := `UPDATE users
+		SET phone = $1, phone_verified_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $2`
+
+	_, err = tx.Exec(ctx, query, phone, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE sessions SET is_revoked = true WHERE user_phone = $1 AND is_revoked = false`,
+		oldPhone,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r UserRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
+// Activate marks the phone of a new user as verified and the user as
+// active. It only succeeds while the phone is unverified, so first-time
+// side effects run exactly once; otherwise it returns ErrRecordNotFound.
+// Deactivated accounts stay deactivated because their phone is verified.
+func (r UserRepository) Activate(id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET is_active = TRUE, phone_verified_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND phone_verified_at IS NULL AND is_banned = FALSE AND deleted_at IS NULL`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -407,154 +517,321 @@ func (r UserRepository) Delete(id uuid.UUID) error {
 	return nil
 }
 
-func addUserSpecificFiltersToSQL(
-	f *requests.UsersAdminFilters, query *string, argCounter *int, args []interface{},
-) {
-	if f.ID != nil {
-		*query += fmt.Sprintf(" AND id = $%d", *argCounter)
-		args = append(args, *f.ID)
-		*argCounter++
-	}
+// UpdateModerationState writes the ban, trust and access level flags of
+// the user and records entry in the audit log in the same transaction.
+// With revokeSessions every session of the user is revoked as well, so
+// refresh tokens issued with the old flags stop working.
+func (r UserRepository) UpdateModerationState(
+	user *data.User, entry *data.AuditEntry, revokeSessions bool,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	if f.Phone != nil {
-		*query += fmt.Sprintf(" AND phone = $%d", *argCounter)
-		args = append(args, *f.Phone)
-		*argCounter++
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if f.Email != nil {
-		*query += fmt.Sprintf(" AND email = $%d", *argCounter)
-		args = append(args, *f.Email)
-		*argCounter++
+	query := `
+	UPDATE users
+	SET is_banned = $1, is_trusted = $2, is_staff = $3, is_admin = $4, is_superuser = $5,
+		updated_by_id = $6, version = version + 1
+	WHERE id = $7 AND version = $8
+	RETURNING is_active, is_banned, is_trusted, is_staff, is_admin, is_superuser, updated_at, version`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		user.IsBanned,
+		user.IsTrusted,
+		user.IsStaff,
+		user.IsAdmin,
+		user.IsSuperuser,
+		user.UpdatedByID,
+		user.ID,
+		user.Version,
+	).Scan(
+		&user.IsActive,
+		&user.IsBanned,
+		&user.IsTrusted,
+		&user.IsStaff,
+		&user.IsAdmin,
+		&user.IsSuperuser,
+		&user.UpdatedAt,
+		&user.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrEditConflict
+		default:
+			return err
+		}
 	}
 
-	if f.IsActice != nil {
-		*query += fmt.Sprintf(" AND is_active = $%d", *argCounter)
-		args = append(args, *f.IsActice)
-		*argCounter++
+	entry.After, err = json.Marshal(user.ModerationState())
+	if err != nil {
+		return err
 	}
 
-	if f.IsBanned != nil {
-		*query += fmt.Sprintf(" AND is_banned = $%d", *argCounter)
-		args = append(args, *f.IsBanned)
-		*argCounter++
+	err = insertAuditEntryTx(ctx, tx, entry)
+	if err != nil {
+		return err
 	}
 
-	if f.IsBanned != nil {
-		*query += fmt.Sprintf(" AND is_banned = $%d", *argCounter)
-		args = append(args, *f.IsBanned)
-		*argCounter++
+	if revokeSessions {
+		_, err = tx.Exec(
+			ctx,
+			`UPDATE sessions SET is_revoked = true WHERE user_phone = $1 AND is_revoked = false`,
+			user.Phone,
+		)
+		if err != nil {
+			return err
+		}
 	}
 
-	if f.IsTrusted != nil {
-		*query += fmt.Sprintf(" AND is_trusted = $%d", *argCounter)
-		args = append(args, *f.IsTrusted)
-		*argCounter++
-	}
+	return tx.Commit(ctx)
+}
 
-	if f.IsTrusted != nil {
-		*query += fmt.Sprintf(" AND is_trusted = $%d", *argCounter)
-		args = append(args, *f.IsTrusted)
-		*argCounter++
+// Delete moves the user to the trash and revokes their sessions. Users are
+// never purged because orders, sessions and the audit log keep pointing at
+// them; once the retention period has passed they are erased instead.
+func (r UserRepository) Delete(id uuid.UUID, audit data.AuditFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if f.IsInvited != nil {
-		if *f.IsInvited {
-			*query += " AND (invited_by_id IS NOT NULL OR inv_ref_id is NOT NULL OR inv_prod_ref_id IS NOT NULL)"
-		} else {
-			*query += " AND invited_by_id IS NULL AND inv_ref_id IS NULL AND inv_prod_ref_id IS NULL"
+	var phone string
+	err = tx.QueryRow(
+		ctx,
+		`UPDATE users SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING phone`,
+		id,
+	).Scan(&phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
 		}
 	}
 
-	if f.RefSignupsFrom != nil {
-		*query += fmt.Sprintf(" AND ref_signups >= $%d", *argCounter)
-		args = append(args, *f.RefSignupsFrom)
-		*argCounter++
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE sessions SET is_revoked = true WHERE user_phone = $1 AND is_revoked = false`,
+		phone,
+	)
+	if err != nil {
+		return err
 	}
 
-	if f.RefSignupsTo != nil {
-		*query += fmt.Sprintf(" AND ref_signups <= $%d", *argCounter)
-		args = append(args, *f.RefSignupsTo)
-		*argCounter++
+	err = recordAuditTx(ctx, tx, audit)
+	if err != nil {
+		return err
 	}
 
-	if f.ProdRefSignupsFrom != nil {
-		*query += fmt.Sprintf(" AND prod_ref_signups >= $%d", *argCounter)
-		args = append(args, *f.ProdRefSignupsFrom)
-		*argCounter++
-	}
+	return tx.Commit(ctx)
+}
 
-	if f.ProdRefSignupsTo != nil {
-		*query += fmt.Sprintf(" AND prod_ref_signups <= $%d", *argCounter)
-		args = append(args, *f.ProdRefSignupsTo)
-		*argCounter++
-	}
+// ListDeleted lists the users in the trash, most recently deleted first.
+func (r UserRepository) ListDeleted(f *filters.PaginationFilter) ([]*data.User, types.PaginationMetadata, error) {
+	q := filters.NewQuery(`
+	SELECT
+		count(*) OVER(),
+		id, phone, first_name, last_name, patronymic, dob, email,
+		is_active, is_banned, is_trusted, invited_by_id, inv_ref_id, inv_prod_ref_id,
+		ref_signups, prod_ref_signups, prod_ref_bought, total_referrals,
+		_dynamic_discount_percent, dyn_disc_percent, bonus_points,
+		is_staff, is_admin, is_superuser, created_at, updated_at,
+		created_by_id, updated_by_id, version, deleted_at, erased_at
+	FROM users`)
+
+	q.Where("deleted_at IS NOT NULL")
+	q.OrderBy("deleted_at DESC", "id")
+	page, pageSize := f.Apply(q)
+
+	query, args := q.Build()
 
-	if f.ProdRefBoughtFrom != nil {
-		*query += fmt.Sprintf(" AND prod_ref_bought >= $%d", *argCounter)
-		args = append(args, *f.ProdRefBoughtFrom)
-		*argCounter++
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	if f.ProdRefBoughtTo != nil {
-		*query += fmt.Sprintf(" AND prod_ref_bought <= $%d", *argCounter)
-		args = append(args, *f.ProdRefBoughtTo)
-		*argCounter++
+	rows, err := r.DBPOOL.Query(ctx, query, args...)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
 	}
+	defer rows.Close()
 
-	if f.WholeDynDiscPercentFrom != nil {
-		*query += fmt.Sprintf(" AND _dynamic_discount_percent >= $%d", *argCounter)
-		args = append(args, *f.WholeDynDiscPercentFrom)
-		*argCounter++
-	}
+	totalRecords := 0
+	users := []*data.User{}
 
-	if f.WholeDynDiscPercentTo != nil {
-		*query += fmt.Sprintf(" AND _dynamic_discount_percent <= $%d", *argCounter)
-		args = append(args, *f.WholeDynDiscPercentTo)
-		*argCounter++
+	for rows.Next() {
+		var user data.User
+		err := rows.Scan(
+			&totalRecords,
+			&user.ID,
+			&user.Phone,
+			&user.FirstName,
+			&user.LastName,
+			&user.Patronomic,
+			&user.DOB,
+			&user.Email,
+			&user.IsActive,
+			&user.IsBanned,
+			&user.IsTrusted,
+			&user.InvitedByID,
+			&user.InvRefID,
+			&user.InvProdRefID,
+			&user.RefSignups,
+			&user.ProdRefSignups,
+			&user.ProdRefBought,
+			&user.TotalRefferals,
+			&user.WholeDynDiscPercent,
+			&user.DynDiscPercent,
+			&user.BonusPoints,
+			&user.IsStaff,
+			&user.IsAdmin,
+			&user.IsSuperuser,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.CreatedByID,
+			&user.UpdatedByID,
+			&user.Version,
+			&user.DeletedAt,
+			&user.ErasedAt,
+		)
+		if err != nil {
+			return nil, types.PaginationMetadata{}, err
+		}
+		users = append(users, &user)
 	}
 
-	if f.DynDiscPercentFrom != nil {
-		*query += fmt.Sprintf(" AND dyn_disc_percent >= $%d", *argCounter)
-		args = append(args, *f.DynDiscPercentFrom)
-		*argCounter++
+	if err := rows.Err(); err != nil {
+		return nil, types.PaginationMetadata{}, err
 	}
 
-	if f.DynDiscPercentTo != nil {
-		*query += fmt.Sprintf(" AND dyn_disc_percent <= $%d", *argCounter)
-		args = append(args, *f.DynDiscPercentTo)
-		*argCounter++
-	}
+	return users, common.CalculateMetadata(totalRecords, page, pageSize), nil
+}
 
-	if f.BonusPointsFrom != nil {
-		*query += fmt.Sprintf(" AND bonus_points >= $%d", *argCounter)
-		args = append(args, *f.BonusPointsFrom)
-		*argCounter++
-	}
+// Restore takes the user with the ID of user out of the trash, as restored
+// by its UpdatedByID, and loads the restored user into it. Admins and
+// superusers are only restored with allowPrivileged. Sessions revoked on
+// delete stay revoked, so the user has to log in again. Erased users cannot
+// be restored.
+func (r UserRepository) Restore(user *data.User, allowPrivileged bool, audit data.AuditFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	if f.BonusPointsTo != nil {
-		*query += fmt.Sprintf(" AND bonus_points <= $%d", *argCounter)
-		args = append(args, *f.BonusPointsTo)
-		*argCounter++
+	tx, err := r.DBPOOL.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
+		ctx,
+		`UPDATE users
+		SET deleted_at = NULL, updated_by_id = $2, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL AND erased_at IS NULL
+		RETURNING
+			id, phone, first_name, last_name, patronymic, dob, email, email_verified_at,
+			phone_verified_at, is_active, is_banned, is_trusted, invited_by_id, inv_ref_id, inv_prod_ref_id,
+			ref_signups, prod_ref_signups, prod_ref_bought, total_referrals,
+			_dynamic_discount_percent, dyn_disc_percent, bonus_points,
+			is_staff, is_admin, is_superuser, created_at, updated_at,
+			created_by_id, updated_by_id, version`,
+		user.ID,
+		user.UpdatedByID,
+	).Scan(
+		&user.ID,
+		&user.Phone,
+		&user.FirstName,
+		&user.LastName,
+		&user.Patronomic,
+		&user.DOB,
+		&user.Email,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
+		&user.IsActive,
+		&user.IsBanned,
+		&user.IsTrusted,
+		&user.InvitedByID,
+		&user.InvRefID,
+		&user.InvProdRefID,
+		&user.RefSignups,
+		&user.ProdRefSignups,
+		&user.ProdRefBought,
+		&user.TotalRefferals,
+		&user.WholeDynDiscPercent,
+		&user.DynDiscPercent,
+		&user.BonusPoints,
+		&user.IsStaff,
+		&user.IsAdmin,
+		&user.IsSuperuser,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.CreatedByID,
+		&user.UpdatedByID,
+		&user.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return common.ErrRecordNotFound
+		default:
+			return err
+		}
 	}
 
-	if f.IsStaff != nil {
-		*query += fmt.Sprintf(" AND is_staff = $%d", *argCounter)
-		args = append(args, *f.IsStaff)
-		*argCounter++
+	if (user.IsAdmin || user.IsSuperuser) && !allowPrivileged {
+		return common.ErrPrivilegeEscalation
 	}
 
-	if f.IsAdmin != nil {
-		*query += fmt.Sprintf(" AND is_admin = $%d", *argCounter)
-		args = append(args, *f.IsAdmin)
-		*argCounter++
+	err = recordAuditTx(ctx, tx, audit)
+	if err != nil {
+		return err
 	}
 
-	if f.IsSuperuser != nil {
-		*query += fmt.Sprintf(" AND is_superuser = $%d", *argCounter)
-		args = append(args, *f.IsSuperuser)
-		*argCounter++
+	return tx.Commit(ctx)
+}
+
+func applyUserFilters(q *filters.Query, f *requests.UsersAdminFilters) {
+	filters.Eq(q, "id", f.ID)
+	filters.Eq(q, "phone", f.Phone)
+	filters.Eq(q, "email", f.Email)
+	filters.Eq(q, "is_active", f.IsActice)
+	filters.Eq(q, "is_banned", f.IsBanned)
+	filters.Eq(q, "is_trusted", f.IsTrusted)
+
+	if f.IsInvited != nil {
+		if *f.IsInvited {
+			q.Where("(invited_by_id IS NOT NULL OR inv_ref_id IS NOT NULL OR inv_prod_ref_id IS NOT NULL)")
+		} else {
+			q.Where("invited_by_id IS NULL AND inv_ref_id IS NULL AND inv_prod_ref_id IS NULL")
+		}
 	}
 
-	fmt.Println(args)
+	filters.Gte(q, "ref_signups", f.RefSignupsFrom)
+	filters.Lte(q, "ref_signups", f.RefSignupsTo)
+	filters.Gte(q, "prod_ref_signups", f.ProdRefSignupsFrom)
+	filters.Lte(q, "prod_ref_signups", f.ProdRefSignupsTo)
+	filters.Gte(q, "prod_ref_bought", f.ProdRefBoughtFrom)
+	filters.Lte(q, "prod_ref_bought", f.ProdRefBoughtTo)
+	filters.Gte(q, "_dynamic_discount_percent", f.WholeDynDiscPercentFrom)
+	filters.Lte(q, "_dynamic_discount_percent", f.WholeDynDiscPercentTo)
+	filters.Gte(q, "dyn_disc_percent", f.DynDiscPercentFrom)
+	filters.Lte(q, "dyn_disc_percent", f.DynDiscPercentTo)
+	filters.Gte(q, "bonus_points", f.BonusPointsFrom)
+	filters.Lte(q, "bonus_points", f.BonusPointsTo)
+
+	filters.Eq(q, "is_staff", f.IsStaff)
+	filters.Eq(q, "is_admin", f.IsAdmin)
+	filters.Eq(q, "is_superuser", f.IsSuperuser)
 }
@@ -4,6 +4,7 @@ import (
 	chiMiddleware "github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/handlers"
 	"github.com/kcharymyrat/e-commerce/internal/middleware"
 
@@ -33,73 +34,271 @@ func Routes(app *app.Application) *chi.Mux {
 		r.Get("/healthcheck", handlers.HealthcheckHandler(app))
 
 		r.Route("/categories", func(r chi.Router) {
+			r.Use(middleware.ConditionalGet(constants.CacheControlCatalog))
+			r.Use(middleware.CacheResponse(app, constants.CacheTagCategories, constants.CacheTagTranslations))
 			r.Get("/", handlers.ListCategoriesPublicHandler(app))
 			r.Get("/{slug}", handlers.GetCategoryPublicHandler(app))
 		})
 
+		r.Route("/search", func(r chi.Router) {
+			r.Use(middleware.ConditionalGet(constants.CacheControlSearch))
+			r.Get("/", handlers.SearchCatalogPublicHandler(app))
+			r.Get("/suggest", handlers.SuggestPublicHandler(app))
+			r.With(
+				middleware.RouteRateLimiter(app, "search_clicks", app.Config.Search.ClicksPerMinute),
+			).Post("/clicks", handlers.RegisterSearchClickPublicHandler(app))
+		})
+
 		r.Route("/languages", func(r chi.Router) {
+			r.Use(middleware.ConditionalGet(constants.CacheControlCatalog))
+			r.Use(middleware.CacheResponse(app, constants.CacheTagLanguages, constants.CacheTagTranslations))
 			r.Get("/", handlers.ListLanguagesPublicHandler(app))
 			r.Get("/{id}", handlers.GetLanguagePublicHandler(app))
 		})
 
 		r.Route("/users", func(r chi.Router) {
+			r.Use(middleware.ConditionalGet(constants.CacheControlProfile))
 			r.Get("/{id}", handlers.GetUserPublicHandler(app))
 		})
 
+		r.With(
+			middleware.ConditionalGet(constants.CacheControlReference),
+			middleware.CacheResponse(app, constants.CacheTagCountries),
+		).Get("/countries", handlers.ListCountriesPublicHandler(app))
+
+		r.With(
+			middleware.RouteRateLimiter(app, "product_referral_clicks", app.Config.Referral.ClicksPerMinute),
+		).Post("/product-referrals/{code}/clicks", handlers.RegisterProductReferralClickPublicHandler(app))
+
+		r.Route("/register", func(r chi.Router) {
+			r.Post("/", handlers.RegisterUserWithPasswordPublicHandler(app))
+			r.Post("/verify", handlers.VerifyRegistrationPublicHandler(app))
+			r.Post("/resend", handlers.ResendRegistrationOTPPublicHandler(app))
+		})
+
+		r.Route("/login", func(r chi.Router) {
+			r.Post("/", handlers.LoginWithPasswordPublicHandler(app))
+			r.Post("/otp", handlers.RequestLoginOTPPublicHandler(app))
+			r.Post("/otp/verify", handlers.LoginWithOTPPublicHandler(app))
+			// MFA challenges are the same whether the admin or the public
+			// login issued them, so both complete them with these handlers.
+			r.Post("/mfa", handlers.VerifyMFAAdminHandler(app))
+			r.Post("/mfa/enroll", handlers.EnrollMFAAdminHandler(app))
+		})
+
+		r.Route("/password", func(r chi.Router) {
+			r.Post("/forgot", handlers.ForgotPasswordPublicHandler(app))
+			r.Post("/reset/verify", handlers.VerifyPasswordResetOTPPublicHandler(app))
+			r.Post("/reset", handlers.ResetPasswordPublicHandler(app))
+		})
+
+		r.Post("/email/verify", handlers.VerifyEmailPublicHandler(app))
+
 		r.Route("/admin", func(r chi.Router) {
-			// r.Use(middleware.AdminAuthMiddleware(app))
 			r.Route("/categories", func(r chi.Router) {
-				r.Get("/", handlers.ListCategoriesManagerHandler(app))
-				r.Post("/", handlers.CreateCategoryManagerHandler(app))
-				r.Get("/{slug}", handlers.GetCategoryManagerHandler(app))
-				r.Put("/{slug}", handlers.UpdateCategoryManagerHandler(app))
-				r.Patch("/{slug}", handlers.PartialUpdateCategoryManagerHandler(app))
-				r.Delete("/{slug}", handlers.DeleteCategoryManagerHandler(app))
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermCategoryRead))
+					r.Get("/", handlers.ListCategoriesManagerHandler(app))
+					r.Get("/{slug}", handlers.GetCategoryManagerHandler(app))
+					r.Get("/trash", handlers.ListDeletedCategoriesManagerHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermCategoryWrite))
+					r.Post("/", handlers.CreateCategoryManagerHandler(app))
+					r.Put("/{slug}", handlers.UpdateCategoryManagerHandler(app))
+					r.Patch("/{slug}", handlers.PartialUpdateCategoryManagerHandler(app))
+					r.Delete("/{slug}", handlers.DeleteCategoryManagerHandler(app))
+					r.Post("/{slug}/restore", handlers.RestoreCategoryManagerHandler(app))
+				})
 			})
 
 			r.Route("/languages", func(r chi.Router) {
-				r.Get("/", handlers.ListLanguagesManagerHandler(app))
-				r.Post("/", handlers.CreateLanguageManagerHandler(app))
-				r.Get("/{id}", handlers.GetLanguageManagerHandler(app))
-				r.Put("/{id}", handlers.UpdateLanguageManagerHandler(app))
-				r.Patch("/{id}", handlers.PartialUpdateLanguageManagerHandler(app))
-				r.Delete("/{id}", handlers.DeleteLanguageManagerHandler(app))
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermLanguageRead))
+					r.Get("/", handlers.ListLanguagesManagerHandler(app))
+					r.Get("/{id}", handlers.GetLanguageManagerHandler(app))
+					r.Get("/trash", handlers.ListDeletedLanguagesManagerHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermLanguageWrite))
+					r.Post("/", handlers.CreateLanguageManagerHandler(app))
+					r.Put("/{id}", handlers.UpdateLanguageManagerHandler(app))
+					r.Patch("/{id}", handlers.PartialUpdateLanguageManagerHandler(app))
+					r.Delete("/{id}", handlers.DeleteLanguageManagerHandler(app))
+					r.Post("/{id}/restore", handlers.RestoreLanguageManagerHandler(app))
+				})
 			})
 
 			r.Route("/translations", func(r chi.Router) {
-				r.Get("/", handlers.ListTranslationsHandler(app))
-				r.Post("/", handlers.CreateTranslationMangerHandler(app))
-				r.Get("/{id}", handlers.GetTranslationHandler(app))
-				r.Put("/{id}", handlers.UpdateTranslationHandler(app))
-				r.Patch("/{id}", handlers.PartialUpdateTranslationHandler(app))
-				r.Delete("/{id}", handlers.DeleteTranslationHandler(app))
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermTranslationRead))
+					r.Get("/", handlers.ListTranslationsHandler(app))
+					r.Get("/{id}", handlers.GetTranslationHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermTranslationWrite))
+					r.Post("/", handlers.CreateTranslationMangerHandler(app))
+					r.Put("/{id}", handlers.UpdateTranslationHandler(app))
+					r.Patch("/{id}", handlers.PartialUpdateTranslationHandler(app))
+					r.Delete("/{id}", handlers.DeleteTranslationHandler(app))
+				})
 			})
 
 			r.Route("/users", func(r chi.Router) {
-				r.Get("/", handlers.ListUsersAdminHandler(app))
-				r.Post("/", handlers.CreateUserAdminHandler(app))
-				r.Get("/{id}", handlers.GetUsersAdminHandler(app))
-				r.Put("/{id}", handlers.UpdateUserAdminHandler(app))
-				r.Patch("/{id}", handlers.PartialUpdateUserAdminHandler(app))
-				r.Delete("/{id}", handlers.DeleteUserAdminHandler(app))
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermUserRead))
+					r.Get("/", handlers.ListUsersAdminHandler(app))
+					r.Get("/{id}", handlers.GetUsersAdminHandler(app))
+					r.Get("/trash", handlers.ListDeletedUsersAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermUserWrite))
+					r.Post("/", handlers.CreateUserAdminHandler(app))
+					r.Put("/{id}", handlers.UpdateUserAdminHandler(app))
+					r.Patch("/{id}", handlers.PartialUpdateUserAdminHandler(app))
+					r.Delete("/{id}", handlers.DeleteUserAdminHandler(app))
+					r.Post("/{id}/restore", handlers.RestoreUserAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermUserBan))
+					r.Post("/{id}/ban", handlers.BanUserAdminHandler(app))
+					r.Post("/{id}/unban", handlers.UnbanUserAdminHandler(app))
+					r.Post("/{id}/trust", handlers.TrustUserAdminHandler(app))
+					r.Post("/{id}/untrust", handlers.UntrustUserAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermUserWrite))
+					r.Post("/{id}/promote", handlers.PromoteUserAdminHandler(app))
+					r.Post("/{id}/demote", handlers.DemoteUserAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermRoleRead))
+					r.Get("/{id}/roles", handlers.ListUserRolesAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermBonusRead))
+					r.Get("/{id}/bonus", handlers.GetUserBonusAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermBonusWrite))
+					r.Post("/{id}/bonus", handlers.AdjustUserBonusAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermRoleWrite))
+					r.Post("/{id}/roles", handlers.AssignUserRoleAdminHandler(app))
+					r.Delete("/{id}/roles/{role_id}", handlers.RemoveUserRoleAdminHandler(app))
+				})
+			})
+
+			r.Route("/countries", func(r chi.Router) {
+				r.Use(middleware.RequirePermission(app, constants.PermCountryWrite))
+				r.Post("/", handlers.CreateCountryAdminHandler(app))
+				r.Put("/{code}", handlers.UpdateCountryAdminHandler(app))
+				r.Delete("/{code}", handlers.DeleteCountryAdminHandler(app))
+			})
+
+			r.Route("/roles", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermRoleRead))
+					r.Get("/", handlers.ListRolesAdminHandler(app))
+					r.Get("/{id}", handlers.GetRoleAdminHandler(app))
+				})
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequirePermission(app, constants.PermRoleWrite))
+					r.Post("/", handlers.CreateRoleAdminHandler(app))
+					r.Put("/{id}", handlers.UpdateRoleAdminHandler(app))
+					r.Delete("/{id}", handlers.DeleteRoleAdminHandler(app))
+				})
+			})
+
+			r.With(middleware.RequirePermission(app, constants.PermRoleRead)).
+				Get("/permissions", handlers.ListPermissionsAdminHandler(app))
+
+			r.Route("/purchases", func(r chi.Router) {
+				r.Use(middleware.RequirePermission(app, constants.PermPurchaseWrite))
+				r.Post("/", handlers.RecordPaidOrderAdminHandler(app))
+				r.Post("/redemptions", handlers.RedeemBonusAdminHandler(app))
+			})
+
+			r.Route("/referrals", func(r chi.Router) {
+				r.Use(middleware.RequirePermission(app, constants.PermReferralRead))
+				r.Get("/leaderboard", handlers.ListReferralLeaderboardAdminHandler(app))
+			})
+
+			r.Route("/audit", func(r chi.Router) {
+				r.Use(middleware.RequirePermission(app, constants.PermAuditRead))
+				r.Get("/", handlers.ListAuditLogAdminHandler(app))
+			})
+
+			r.With(middleware.RequirePermission(app, constants.PermMetricsRead)).
+				Get("/cache/stats", handlers.GetCacheStatsAdminHandler(app))
+
+			r.Route("/lockouts", func(r chi.Router) {
+				r.Use(middleware.RequirePermission(app, constants.PermLockoutManage))
+				r.Get("/", handlers.ListLoginLockoutsAdminHandler(app))
+				r.Delete("/", handlers.ClearLoginLockoutAdminHandler(app))
 			})
 
 			r.Post("/login", handlers.LoginAdminHandler(app))
-			r.Post("/logout", handlers.LogoutAdminHandler(app))
+			r.Post("/login/mfa", handlers.VerifyMFAAdminHandler(app))
+			r.Post("/login/mfa/enroll", handlers.EnrollMFAAdminHandler(app))
 
-			r.Route("/tokens", func(r chi.Router) {
-				r.Post("/renew", handlers.RenewAccessTokenReqHandler(app))
-				r.Post("/revoke", handlers.RevokeSessionByIDHandler(app))
+			// Renewing is what clients do once the access token expired, so
+			// it is authenticated by the refresh token and its session only.
+			r.Post("/tokens/renew", handlers.RenewAccessTokenReqHandler(app))
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AuthMiddleware(app))
+				r.Post("/logout", handlers.LogoutAdminHandler(app))
+				r.Post("/tokens/revoke", handlers.RevokeSessionByIDHandler(app))
 			})
 		})
 
 		r.Route("/me", func(r chi.Router) {
-			// r.Use(middleware.SelfAuthMiddleware(app))
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AuthMiddleware(app))
+				r.Post("/password", handlers.ChangePasswordSelfHandler(app))
+				r.Post("/phone", handlers.RequestPhoneChangeSelfHandler(app))
+				r.Post("/phone/verify", handlers.VerifyPhoneChangeSelfHandler(app))
+				r.Get("/referral", handlers.GetReferralSelfHandler(app))
+				r.Get("/product-referrals", handlers.ListProductReferralsSelfHandler(app))
+				r.Post("/product-referrals", handlers.CreateProductReferralSelfHandler(app))
+				r.Get("/bonus", handlers.GetBonusSelfHandler(app))
+				r.Get("/customer", handlers.GetCustomerProfileSelfHandler(app))
+				r.Route("/addresses", func(r chi.Router) {
+					r.Get("/", handlers.ListAddressesSelfHandler(app))
+					r.Post("/", handlers.CreateAddressSelfHandler(app))
+					r.Get("/{id}", handlers.GetAddressSelfHandler(app))
+					r.Put("/{id}", handlers.UpdateAddressSelfHandler(app))
+					r.Delete("/{id}", handlers.DeleteAddressSelfHandler(app))
+					r.Post("/{id}/default", handlers.SetDefaultAddressSelfHandler(app))
+				})
+				r.Route("/2fa", func(r chi.Router) {
+					r.Post("/totp", handlers.StartTOTPEnrollmentSelfHandler(app))
+					r.Post("/totp/confirm", handlers.ConfirmTOTPEnrollmentSelfHandler(app))
+					r.Delete("/totp", handlers.DisableTOTPSelfHandler(app))
+					r.Post("/recovery-codes", handlers.RegenerateRecoveryCodesSelfHandler(app))
+				})
+				r.Post("/email/verification", handlers.RequestEmailVerificationSelfHandler(app))
+				r.Get("/notifications", handlers.GetNotificationPreferencesSelfHandler(app))
+				r.Patch("/notifications", handlers.UpdateNotificationPreferencesSelfHandler(app))
+				r.Get("/export", handlers.ExportUserDataSelfHandler(app))
+				r.Route("/erasure", func(r chi.Router) {
+					r.Get("/", handlers.GetErasureSelfHandler(app))
+					r.Post("/", handlers.RequestErasureSelfHandler(app))
+					r.Delete("/", handlers.CancelErasureSelfHandler(app))
+				})
+			})
+
 			r.Route("/users", func(r chi.Router) {
-				r.Get("/{id}", handlers.GetUserSelfHandler(app))
-				r.Put("/{id}", handlers.UpdateUserSelfHandler(app))
-				r.Patch("/{id}", handlers.PartialUpdateUserSelfHandler(app))
-				r.Delete("/{id}", handlers.DeleteUserSelfHandler(app))
+				// Grouped so the middleware runs after routing and can read {id}.
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.SelfAuthMiddleware(app))
+					r.Get("/{id}", handlers.GetUserSelfHandler(app))
+					r.Put("/{id}", handlers.UpdateUserSelfHandler(app))
+					r.Patch("/{id}", handlers.PartialUpdateUserSelfHandler(app))
+					r.Delete("/{id}", handlers.DeleteUserSelfHandler(app))
+				})
 			})
 		})
 
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+)
+
+// auditIgnoredFields change on every write and would only add noise to
+// the before/after diff.
+var auditIgnoredFields = []string{"updated_at", "version"}
+
+// auditChange completes entry for a change of the entity of table with the
+// given id, which repositories store together with the change. before is
+// nil for creates and after is nil for deletes. For updates only the fields
+// whose value changed are kept on either side. A nil entry records nothing.
+func auditChange(entry *data.AuditEntry, table string, id *uuid.UUID, before, after any) data.AuditFunc {
+	if entry == nil {
+		return nil
+	}
+
+	return func() (*data.AuditEntry, error) {
+		var err error
+		entry.TableName = table
+		entry.EntityID = id
+		entry.Before, entry.After, err = auditDiff(before, after)
+		if err != nil {
+			return nil, err
+		}
+		return entry, nil
+	}
+}
+
+func ListAuditLogService(
+	app *app.Application, f *requests.AuditLogFilters,
+) ([]*data.AuditEntry, types.PaginationMetadata, error) {
+	return app.Repositories.Audit.List(f)
+}
+
+func auditDiff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if bytes.Equal(value, afterFields[key]) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditFields(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := marshalAuditFields(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+// auditFields flattens v into its top level JSON fields.
+func auditFields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(doc, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range auditIgnoredFields {
+		delete(fields, key)
+	}
+
+	return fields, nil
+}
+
+func marshalAuditFields(fields map[string]json.RawMessage) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
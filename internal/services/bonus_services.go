@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+var maxStoredDiscountPercent = decimal.NewFromInt(100)
+
+// DynamicDiscountFor computes the discount a user has earned from their
+// referral counters. The database caps the effective discount
+// (dyn_disc_percent) at 10%, the stored value only at 100%.
+func DynamicDiscountFor(app *app.Application, user *data.User) decimal.Decimal {
+	rules := app.Config.Bonus
+
+	percent := rules.DiscountPerReferralSignup.Mul(decimal.NewFromInt(int64(user.RefSignups))).
+		Add(rules.DiscountPerProductSignup.Mul(decimal.NewFromInt(int64(user.ProdRefSignups)))).
+		Add(rules.DiscountPerProductPurchase.Mul(decimal.NewFromInt(int64(user.ProdRefBought))))
+
+	return decimal.Min(percent, maxStoredDiscountPercent).Round(2)
+}
+
+// ApplyDiscountRulesService recomputes the user's dynamic discount and
+// records the change in the ledger when it moved.
+func ApplyDiscountRulesService(app *app.Application, userID uuid.UUID) error {
+	user, err := app.Repositories.Users.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	entry := &data.BonusLedgerEntry{
+		UserID: userID,
+		Reason: constants.BonusReasonDiscountRule,
+	}
+	_, err = app.Repositories.Bonus.SetDiscount(entry, DynamicDiscountFor(app, user))
+	return err
+}
+
+func applyDiscountRules(app *app.Application, userIDs []uuid.UUID) error {
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		err := ApplyDiscountRulesService(app, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RedeemBonusPointsService spends points of the buyer on the order and
+// returns the amount they are worth. Each order can redeem points only once.
+func RedeemBonusPointsService(
+	app *app.Application, buyerID, actorID, orderID uuid.UUID, points decimal.Decimal,
+) (decimal.Decimal, error) {
+	entry := &data.BonusLedgerEntry{
+		UserID:  buyerID,
+		Delta:   points.Neg(),
+		Reason:  constants.BonusReasonRedemption,
+		OrderID: &orderID,
+		ActorID: &actorID,
+	}
+
+	err := app.Repositories.Bonus.AdjustPoints(entry)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constants.UniqueViolation {
+			return decimal.Zero, common.ErrOrderAlreadyRedeemed
+		}
+		return decimal.Zero, err
+	}
+
+	return points.Mul(app.Config.Bonus.PointValue).Round(2), nil
+}
+
+// AdjustBonusAdminService applies a manual correction made by support. A
+// manual discount holds until the next referral event recomputes it from
+// the rules.
+func AdjustBonusAdminService(
+	app *app.Application, userID, actorID uuid.UUID, input *requests.BonusAdjustReq,
+) error {
+	if input.PointsDelta != nil {
+		err := app.Repositories.Bonus.AdjustPoints(&data.BonusLedgerEntry{
+			UserID:  userID,
+			Delta:   *input.PointsDelta,
+			Reason:  constants.BonusReasonManualAdjustment,
+			Note:    &input.Note,
+			ActorID: &actorID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if input.DiscountPercent != nil {
+		_, err := app.Repositories.Bonus.SetDiscount(&data.BonusLedgerEntry{
+			UserID:  userID,
+			Reason:  constants.BonusReasonManualAdjustment,
+			Note:    &input.Note,
+			ActorID: &actorID,
+		}, input.DiscountPercent.Round(2))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ListBonusHistoryService(
+	app *app.Application, userID uuid.UUID, f *requests.BonusHistoryFilters,
+) ([]*data.BonusLedgerEntry, types.PaginationMetadata, error) {
+	return app.Repositories.Bonus.ListByUserID(userID, f)
+}
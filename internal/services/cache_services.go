@@ -0,0 +1,32 @@
+package services
+
+import (
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func GetCachedResponseService(app *app.Application, key string, tags []string) (*data.CacheEntry, error) {
+	return app.Repositories.Cache.Get(key, tags)
+}
+
+func CacheResponseService(app *app.Application, key string, entry *data.CacheEntry) error {
+	return app.Repositories.Cache.Set(key, entry, app.Config.Cache.TTL)
+}
+
+func GetCacheGenerationsService(app *app.Application, tags []string) ([]int64, error) {
+	return app.Repositories.Cache.Generations(tags)
+}
+
+// invalidateCache retires the cached responses tagged with any of tags. It is
+// called after the change is committed, so a failure is only logged: the
+// entries expire on their own.
+func invalidateCache(app *app.Application, tags ...string) {
+	err := app.Repositories.Cache.Invalidate(tags...)
+	if err != nil {
+		app.Logger.Error().Err(err).Strs("tags", tags).Msg("failed to invalidate cache")
+	}
+}
+
+func CacheStatsService(app *app.Application) map[string]int64 {
+	return app.Repositories.Cache.Stats()
+}
@@ -4,12 +4,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
 
-func CreateCategoryService(app *app.Application, category *data.Category) error {
-	return app.Repositories.Categories.Create(category)
+func CreateCategoryService(app *app.Application, category *data.Category, entry *data.AuditEntry) error {
+	err := app.Repositories.Categories.Create(category, auditChange(entry, "categories", &category.ID, nil, category))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCategories)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func GetCategoryByIDService(app *app.Application, id uuid.UUID) (*data.Category, error) {
@@ -31,22 +38,36 @@ func UpdateCategoryService(
 	app *app.Application,
 	input *requests.CategoryAdminUpdate,
 	category *data.Category,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *category
+
 	category.ParentID = input.ParentID
 	category.Name = input.Name
 	category.Slug = input.Slug
 	category.Description = input.Description
 	category.ImageUrl = input.ImageUrl
-	category.UpdatedByID = input.UpdatedByID
+	category.UpdatedByID = updatedByID
 
-	return app.Repositories.Categories.Update(category)
+	err := app.Repositories.Categories.Update(category, auditChange(entry, "categories", &category.ID, &before, category))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCategories)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func PartialUpdateCategoryService(
 	app *app.Application,
 	input *requests.CategoryAdminPartialUpdate,
 	category *data.Category,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *category
+
 	if input.Name != nil {
 		category.Name = *input.Name
 	}
@@ -63,15 +84,56 @@ func PartialUpdateCategoryService(
 		category.ImageUrl = *input.ImageUrl
 	}
 
-	category.UpdatedByID = input.UpdatedByID
+	category.UpdatedByID = updatedByID
 
-	return app.Repositories.Categories.Update(category)
+	err := app.Repositories.Categories.Update(category, auditChange(entry, "categories", &category.ID, &before, category))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCategories)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
-func DeleteCategoryServiceById(app *app.Application, id uuid.UUID) error {
-	return app.Repositories.Categories.DeleteByID(id)
+func DeleteCategoryServiceById(app *app.Application, category *data.Category, entry *data.AuditEntry) error {
+	err := app.Repositories.Categories.DeleteByID(category.ID, auditChange(entry, "categories", &category.ID, category, nil))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCategories)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
-func DeleteCategoryServiceBySlug(app *app.Application, slug string) error {
-	return app.Repositories.Categories.DeleteBySlug(slug)
+func DeleteCategoryServiceBySlug(app *app.Application, category *data.Category, entry *data.AuditEntry) error {
+	err := app.Repositories.Categories.DeleteBySlug(category.Slug, auditChange(entry, "categories", &category.ID, category, nil))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCategories)
+	RebuildSuggestionsInBackground(app)
+	return nil
+}
+
+func ListDeletedCategoriesService(
+	app *app.Application,
+	f *requests.TrashAdminFilters,
+) ([]*data.Category, types.PaginationMetadata, error) {
+	return app.Repositories.Categories.ListDeleted(&f.PaginationFilter)
+}
+
+func RestoreCategoryService(
+	app *app.Application,
+	slug string,
+	restoredByID uuid.UUID,
+	entry *data.AuditEntry,
+) (*data.Category, error) {
+	category := &data.Category{Slug: slug, UpdatedByID: restoredByID}
+	err := app.Repositories.Categories.Restore(category, auditChange(entry, "categories", &category.ID, nil, category))
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(app, constants.CacheTagCategories)
+	RebuildSuggestionsInBackground(app)
+	return category, nil
 }
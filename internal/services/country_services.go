@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func CreateCountryService(app *app.Application, country *data.Country) error {
+	err := app.Repositories.Countries.Create(country)
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCountries)
+	return nil
+}
+
+func GetCountryByCodeService(app *app.Application, code string) (*data.Country, error) {
+	return app.Repositories.Countries.GetByCode(code)
+}
+
+func ListCountriesService(app *app.Application) ([]*data.Country, error) {
+	return app.Repositories.Countries.List()
+}
+
+func UpdateCountryService(app *app.Application, country *data.Country) error {
+	err := app.Repositories.Countries.Update(country)
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCountries)
+	return nil
+}
+
+func DeleteCountryService(app *app.Application, code string) error {
+	err := app.Repositories.Countries.DeleteByCode(code)
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagCountries)
+	return nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func GetOrCreateCustomerService(app *app.Application, userID uuid.UUID) (*data.Customer, error) {
+	return app.Repositories.Customers.GetOrCreateByUserID(userID)
+}
+
+func CreateCustomerAddressService(app *app.Application, address *data.CustomerAddress) error {
+	return app.Repositories.Customers.CreateAddress(address)
+}
+
+func GetCustomerAddressService(app *app.Application, customerID, id uuid.UUID) (*data.CustomerAddress, error) {
+	return app.Repositories.Customers.GetAddress(customerID, id)
+}
+
+func ListCustomerAddressesService(app *app.Application, customerID uuid.UUID) ([]*data.CustomerAddress, error) {
+	return app.Repositories.Customers.ListAddresses(customerID)
+}
+
+func UpdateCustomerAddressService(app *app.Application, address *data.CustomerAddress) error {
+	return app.Repositories.Customers.UpdateAddress(address)
+}
+
+func SetDefaultCustomerAddressService(app *app.Application, customerID, id, actorID uuid.UUID) error {
+	return app.Repositories.Customers.SetDefaultAddress(customerID, id, actorID)
+}
+
+func DeleteCustomerAddressService(app *app.Application, customerID, id uuid.UUID) error {
+	return app.Repositories.Customers.DeleteAddress(customerID, id)
+}
+
+// SnapshotDeliveryAddressService copies the user's saved address for an
+// order. A nil addressID selects the default address.
+func SnapshotDeliveryAddressService(
+	app *app.Application, userID uuid.UUID, addressID *uuid.UUID,
+) (*data.DeliveryAddress, error) {
+	customer, err := GetOrCreateCustomerService(app, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var address *data.CustomerAddress
+	if addressID != nil {
+		address, err = GetCustomerAddressService(app, customer.ID, *addressID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		addresses, err := ListCustomerAddressesService(app, customer.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) == 0 || !addresses[0].IsDefault {
+			return nil, common.ErrRecordNotFound
+		}
+		address = addresses[0]
+	}
+
+	snapshot := address.Snapshot()
+	return &snapshot, nil
+}
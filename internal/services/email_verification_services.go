@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// RequestEmailVerificationService emails the user a link with a signed,
+// single-use token for their current email address.
+func RequestEmailVerificationService(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	user *data.User,
+) error {
+	if user.Email == nil {
+		return common.ErrEmailMissing
+	}
+	if user.EmailVerifiedAt != nil {
+		return common.ErrEmailAlreadyVerified
+	}
+
+	nonce, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	ttl := app.Config.EmailVerification.TokenTTL
+	token, err := auth.SignEmailToken(&auth.EmailTokenClaims{
+		UserID:    user.ID,
+		Email:     *user.Email,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}, app.Config.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	err = app.Repositories.EmailVerifications.Save(
+		nonce, user.ID, ttl, app.Config.EmailVerification.ResendCooldown,
+	)
+	if err != nil {
+		return err
+	}
+
+	link := app.Config.EmailVerification.LinkBaseURL + "?token=" + url.QueryEscape(token)
+	return sendLocalizedEmail(app, localizer, *user.Email, "email_verification", map[string]interface{}{
+		"link":  link,
+		"hours": int(ttl / time.Hour),
+	})
+}
+
+// VerifyEmailService redeems a verification token. A token is rejected
+// once used, once expired and once the user changed their email.
+func VerifyEmailService(app *app.Application, token string) error {
+	claims, err := auth.ParseEmailToken(token, app.Config.SecretKey)
+	if err != nil {
+		return common.ErrEmailVerificationInvalid
+	}
+
+	userID, err := app.Repositories.EmailVerifications.Consume(claims.Nonce)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrEmailVerificationInvalid
+		default:
+			return err
+		}
+	}
+	if userID != claims.UserID {
+		return common.ErrEmailVerificationInvalid
+	}
+
+	err = app.Repositories.Users.MarkEmailVerified(claims.UserID, claims.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrEmailVerificationInvalid
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,12 +4,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
 
-func CreateLanguageService(app *app.Application, language *data.Language) error {
-	return app.Repositories.Languages.Create(language)
+func CreateLanguageService(app *app.Application, language *data.Language, entry *data.AuditEntry) error {
+	err := app.Repositories.Languages.Create(language, auditChange(entry, "languages", &language.ID, nil, language))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagLanguages)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func GetLanguageService(app *app.Application, id uuid.UUID) (*data.Language, error) {
@@ -27,30 +34,78 @@ func UpdateLanguageService(
 	app *app.Application,
 	input *requests.LanguageAdminUpdate,
 	language *data.Language,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *language
+
 	language.Name = input.Name
 	language.Code = input.Code
-	language.UpdatedByID = input.UpdatedByID
+	language.UpdatedByID = updatedByID
 
-	return app.Repositories.Languages.Update(language)
+	err := app.Repositories.Languages.Update(language, auditChange(entry, "languages", &language.ID, &before, language))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagLanguages)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func PartialUpdateLanguageService(
 	app *app.Application,
 	input *requests.LanguageAdminPartialUpdate,
 	language *data.Language,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *language
+
 	if input.Name != nil {
 		language.Name = *input.Name
 	}
 	if input.Code != nil {
 		language.Code = *input.Code
 	}
-	language.UpdatedByID = input.UpdatedByID
+	language.UpdatedByID = updatedByID
+
+	err := app.Repositories.Languages.Update(language, auditChange(entry, "languages", &language.ID, &before, language))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagLanguages)
+	RebuildSuggestionsInBackground(app)
+	return nil
+}
+
+func DeleteLanguageService(app *app.Application, language *data.Language, entry *data.AuditEntry) error {
+	err := app.Repositories.Languages.Delete(language.ID, auditChange(entry, "languages", &language.ID, language, nil))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagLanguages)
+	RebuildSuggestionsInBackground(app)
+	return nil
+}
 
-	return app.Repositories.Languages.Update(language)
+func ListDeletedLanguagesService(
+	app *app.Application,
+	f *requests.TrashAdminFilters,
+) ([]*data.Language, types.PaginationMetadata, error) {
+	return app.Repositories.Languages.ListDeleted(&f.PaginationFilter)
 }
 
-func DeleteLanguageService(app *app.Application, id uuid.UUID) error {
-	return app.Repositories.Languages.Delete(id)
+func RestoreLanguageService(
+	app *app.Application,
+	id, restoredByID uuid.UUID,
+	entry *data.AuditEntry,
+) (*data.Language, error) {
+	language := &data.Language{ID: id, UpdatedByID: restoredByID}
+	err := app.Repositories.Languages.Restore(language, auditChange(entry, "languages", &language.ID, nil, language))
+	if err != nil {
+		return nil, err
+	}
+	invalidateCache(app, constants.CacheTagLanguages)
+	RebuildSuggestionsInBackground(app)
+	return language, nil
 }
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+// AuthenticateWithPasswordService returns the user owning phone if password
+// matches and the account may log in. Every failure, including an unknown
+// phone, is reported as common.ErrInvalidCredentials after a bcrypt
+// comparison, so callers can not tell the cases apart by error or timing.
+func AuthenticateWithPasswordService(app *app.Application, phone, password string) (*data.User, error) {
+	user, err := GetUserByPhoneService(app, phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			auth.CompareWithDummyPasswordHash(password)
+			return nil, common.ErrInvalidCredentials
+		default:
+			return nil, err
+		}
+	}
+
+	ok, err := auth.IsPasswordInputMatching(password, user.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || !user.IsActive || user.IsBanned {
+		return nil, common.ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+// CheckLoginLockoutService returns the remaining lockout for the phone or the
+// ip, whichever is longer. Zero means the login may be attempted.
+func CheckLoginLockoutService(app *app.Application, phone, ip string) (time.Duration, error) {
+	phoneLockout, err := app.Repositories.LoginAttempts.GetLockout(constants.LoginThrottlePhone, phone)
+	if err != nil {
+		return 0, err
+	}
+
+	ipLockout, err := app.Repositories.LoginAttempts.GetLockout(constants.LoginThrottleIP, ip)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(phoneLockout, ipLockout), nil
+}
+
+// RegisterLoginFailureService counts a failed login for both the phone and
+// the ip. Once a counter reaches its limit the key is locked out, doubling
+// the lockout with every further failure up to the configured maximum.
+func RegisterLoginFailureService(app *app.Application, phone, ip string) error {
+	cfg := app.Config.LoginThrottle
+
+	counters := []struct {
+		kind  string
+		key   string
+		limit int
+	}{
+		{constants.LoginThrottlePhone, phone, cfg.MaxPhoneFailures},
+		{constants.LoginThrottleIP, ip, cfg.MaxIPFailures},
+	}
+
+	for _, c := range counters {
+		failures, err := app.Repositories.LoginAttempts.RegisterFailure(c.kind, c.key, cfg.FailureWindow)
+		if err != nil {
+			return err
+		}
+
+		if failures < c.limit {
+			continue
+		}
+
+		lockout := loginLockoutDuration(cfg.BaseLockout, cfg.MaxLockout, failures-c.limit)
+		err = app.Repositories.LoginAttempts.Lock(c.kind, c.key, lockout)
+		if err != nil {
+			return err
+		}
+
+		app.Logger.Warn().
+			Str("kind", c.kind).
+			Str("key", c.key).
+			Int("failures", failures).
+			Dur("lockout", lockout).
+			Msg("login locked out")
+	}
+
+	return nil
+}
+
+func loginLockoutDuration(base, maxLockout time.Duration, excess int) time.Duration {
+	if excess >= 30 {
+		return maxLockout
+	}
+
+	lockout := base << excess
+	if lockout <= 0 || lockout > maxLockout {
+		return maxLockout
+	}
+
+	return lockout
+}
+
+// ResetLoginFailuresService clears the phone counter after a successful login.
+// The ip counter is left alone so one valid account can not be used to reset
+// a credential stuffing run.
+func ResetLoginFailuresService(app *app.Application, phone string) error {
+	return app.Repositories.LoginAttempts.Reset(constants.LoginThrottlePhone, phone)
+}
+
+func ListLoginLockoutsService(app *app.Application) ([]*data.LoginLockout, error) {
+	phones, err := app.Repositories.LoginAttempts.List(constants.LoginThrottlePhone)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := app.Repositories.LoginAttempts.List(constants.LoginThrottleIP)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(phones, ips...), nil
+}
+
+func ClearLoginLockoutService(app *app.Application, kind, key string) error {
+	return app.Repositories.LoginAttempts.Reset(kind, key)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/mail"
+	"github.com/kcharymyrat/e-commerce/internal/utils"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// sendLocalizedEmail renders the subject and body messages of an email
+// template in the language of localizer and sends the email in the
+// background. Both messages receive the same template data.
+func sendLocalizedEmail(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	to string,
+	template string,
+	templateData map[string]interface{},
+) error {
+	subject, err := localizer.Localize(&i18n.LocalizeConfig{
+		MessageID:    template + "_email_subject",
+		TemplateData: templateData,
+	})
+	if err != nil {
+		return err
+	}
+
+	body, err := localizer.Localize(&i18n.LocalizeConfig{
+		MessageID:    template + "_email_body",
+		TemplateData: templateData,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := mail.Message{To: to, Subject: subject, Body: body}
+	utils.BackgroundGoroutine(app.Logger, app.Wg, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err := app.Mailer.Send(ctx, msg)
+		if err != nil {
+			app.Logger.Error().Err(err).Str("template", template).Msg("failed to send email")
+		}
+	})
+
+	return nil
+}
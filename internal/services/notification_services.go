@@ -0,0 +1,44 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func GetNotificationPreferencesService(app *app.Application, userID uuid.UUID) (*data.NotificationPreferences, error) {
+	return app.Repositories.NotificationPrefs.GetByUserID(userID)
+}
+
+func UpdateNotificationPreferencesService(
+	app *app.Application,
+	input *requests.NotificationPreferencesUpdate,
+	prefs *data.NotificationPreferences,
+) error {
+	prefs.OrderUpdates = *input.OrderUpdates
+	prefs.Promotions = *input.Promotions
+	prefs.ReviewReplies = *input.ReviewReplies
+
+	return app.Repositories.NotificationPrefs.Upsert(prefs)
+}
+
+// PartialUpdateNotificationPreferencesService changes the preferences given
+// in input and keeps the others.
+func PartialUpdateNotificationPreferencesService(
+	app *app.Application,
+	input *requests.NotificationPreferencesPartialUpdate,
+	prefs *data.NotificationPreferences,
+) error {
+	if input.OrderUpdates != nil {
+		prefs.OrderUpdates = *input.OrderUpdates
+	}
+	if input.Promotions != nil {
+		prefs.Promotions = *input.Promotions
+	}
+	if input.ReviewReplies != nil {
+		prefs.ReviewReplies = *input.ReviewReplies
+	}
+
+	return app.Repositories.NotificationPrefs.Upsert(prefs)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/utils"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// IssueOTPService generates a new code for phone and purpose, stores its hash
+// and sends the code by SMS in the background.
+func IssueOTPService(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	phone string,
+	purpose string,
+) (*data.OTP, error) {
+	code, err := auth.GenerateOTPCode(app.Config.OTP.Length)
+	if err != nil {
+		return nil, err
+	}
+
+	otp := &data.OTP{
+		Phone:    phone,
+		Purpose:  purpose,
+		CodeHash: auth.HashOTPCode(code, phone, purpose, app.Config.SecretKey),
+	}
+
+	err = app.Repositories.OTPs.Save(otp, app.Config.OTP.TTL, app.Config.OTP.ResendCooldown)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := localizer.Localize(&i18n.LocalizeConfig{
+		MessageID: "otp_sms_message",
+		TemplateData: map[string]interface{}{
+			"code":    code,
+			"minutes": int(app.Config.OTP.TTL / time.Minute),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.BackgroundGoroutine(app.Logger, app.Wg, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := app.SMSSender.Send(ctx, phone, message)
+		if err != nil {
+			app.Logger.Error().Err(err).Str("phone", phone).Str("purpose", purpose).Msg("failed to send otp sms")
+		}
+	})
+
+	return otp, nil
+}
+
+// VerifyOTPService checks code against the stored hash. Every call counts as
+// an attempt; once the limit is exceeded the code is discarded. A matching
+// code is consumed so it can not be used twice.
+func VerifyOTPService(app *app.Application, phone, purpose, code string) error {
+	attempts, err := app.Repositories.OTPs.IncrementAttempts(purpose, phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrOTPInvalid
+		default:
+			return err
+		}
+	}
+
+	if attempts > app.Config.OTP.MaxAttempts {
+		err = app.Repositories.OTPs.Delete(purpose, phone)
+		if err != nil {
+			return err
+		}
+		return common.ErrOTPAttemptsExceeded
+	}
+
+	otp, err := app.Repositories.OTPs.Get(purpose, phone)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrOTPInvalid
+		default:
+			return err
+		}
+	}
+
+	if !auth.IsOTPCodeMatching(code, phone, purpose, otp.CodeHash, app.Config.SecretKey) {
+		return common.ErrOTPInvalid
+	}
+
+	return app.Repositories.OTPs.Delete(purpose, phone)
+}
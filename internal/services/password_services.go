@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+// SetUserPasswordService stores the new password and revokes every session of
+// the user, so that stolen refresh tokens stop working after the change.
+func SetUserPasswordService(app *app.Application, user *data.User, password string) error {
+	err := app.Repositories.Users.UpdatePassword(user.ID, password)
+	if err != nil {
+		return err
+	}
+
+	return RevokeAllUserSessionsService(app, user.Phone)
+}
+
+func ChangePasswordService(
+	app *app.Application,
+	user *data.User,
+	currentPassword string,
+	newPassword string,
+) error {
+	match, err := auth.IsPasswordInputMatching(currentPassword, user.PasswordHash)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return common.ErrPasswordMismatch
+	}
+
+	return SetUserPasswordService(app, user, newPassword)
+}
+
+// IssuePasswordResetTokenService creates a single-use reset token for user.
+// The plain token is returned to the caller and only its hash is stored.
+func IssuePasswordResetTokenService(app *app.Application, user *data.User) (string, time.Time, error) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	ttl := app.Config.PasswordReset.TokenTTL
+	err = app.Repositories.PasswordResets.Save(auth.HashOpaqueToken(token), user.ID, ttl)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, time.Now().Add(ttl), nil
+}
+
+func ResetPasswordService(app *app.Application, token string, newPassword string) error {
+	userID, err := app.Repositories.PasswordResets.Consume(auth.HashOpaqueToken(token))
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrPasswordResetTokenInvalid
+		default:
+			return err
+		}
+	}
+
+	user, err := GetUserByIDService(app, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrPasswordResetTokenInvalid
+		default:
+			return err
+		}
+	}
+
+	if user.IsBanned {
+		return common.ErrPasswordResetTokenInvalid
+	}
+
+	return SetUserPasswordService(app, user, newPassword)
+}
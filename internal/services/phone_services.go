@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// phoneChangeOTPPurpose ties a phone change code to the user that asked for
+// it, so a code sent to a phone can only move that user to it.
+func phoneChangeOTPPurpose(userID uuid.UUID) string {
+	return constants.OTPPurposePhoneChange + ":" + userID.String()
+}
+
+// RequestPhoneChangeService sends a code to the new phone of user. The phone
+// is not changed until the code is confirmed with ChangePhoneService.
+func RequestPhoneChangeService(
+	app *app.Application,
+	localizer *i18n.Localizer,
+	user *data.User,
+	phone string,
+) (*data.OTP, error) {
+	if phone == user.Phone {
+		return nil, common.ErrPhoneUnchanged
+	}
+
+	_, err := app.Repositories.Users.GetByPhone(phone)
+	switch {
+	case err == nil:
+		return nil, common.ErrPhoneTaken
+	case !errors.Is(err, common.ErrRecordNotFound):
+		return nil, err
+	}
+
+	return IssueOTPService(app, localizer, phone, phoneChangeOTPPurpose(user.ID))
+}
+
+// ChangePhoneService checks the code sent to the new phone and moves user to
+// it. Every session opened with the old phone is revoked on the way.
+func ChangePhoneService(app *app.Application, user *data.User, phone, code string) error {
+	err := VerifyOTPService(app, phone, phoneChangeOTPPurpose(user.ID), code)
+	if err != nil {
+		return err
+	}
+
+	err = app.Repositories.Users.UpdatePhone(user.ID, phone)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrOTPInvalid
+		case errors.As(err, &pgErr) && pgErr.Code == constants.UniqueViolation:
+			return common.ErrPhoneTaken
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/utils"
+)
+
+// erasureBatchSize caps the erasures carried out per run of the worker.
+const erasureBatchSize = 100
+
+func ExportUserDataService(app *app.Application, user *data.User) (*data.UserDataExport, error) {
+	return app.Repositories.Privacy.Export(user)
+}
+
+func GetPendingErasureService(app *app.Application, userID uuid.UUID) (*data.ErasureRequest, error) {
+	return app.Repositories.Privacy.GetPendingErasureRequest(userID)
+}
+
+// RequestErasureService schedules the erasure of user once the cooling-off
+// period has passed. The password is asked again so a stolen access token
+// alone cannot get an account erased.
+func RequestErasureService(
+	app *app.Application,
+	user *data.User,
+	password string,
+) (*data.ErasureRequest, error) {
+	match, err := auth.IsPasswordInputMatching(password, user.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, common.ErrPasswordMismatch
+	}
+
+	request := &data.ErasureRequest{
+		UserID:       user.ID,
+		ExecuteAfter: time.Now().Add(app.Config.Erasure.CoolingOff),
+	}
+	err = app.Repositories.Privacy.CreateErasureRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func CancelErasureService(app *app.Application, userID uuid.UUID) (*data.ErasureRequest, error) {
+	return app.Repositories.Privacy.CancelErasureRequest(userID)
+}
+
+// ExecuteDueErasuresService erases the users whose cooling-off period has
+// passed. Each erasure is its own transaction; a failing one is logged and
+// retried on the next run.
+func ExecuteDueErasuresService(app *app.Application) error {
+	requests, err := app.Repositories.Privacy.ListDueErasureRequests(erasureBatchSize)
+	if err != nil {
+		return fmt.Errorf("list due erasures: %w", err)
+	}
+
+	for _, request := range requests {
+		err := app.Repositories.Privacy.Erase(request.ID)
+		if err != nil {
+			if errors.Is(err, common.ErrRecordNotFound) {
+				continue
+			}
+			app.Logger.Error().Err(err).
+				Str("erasure_request_id", request.ID.String()).
+				Msg("failed to erase user")
+			continue
+		}
+
+		app.Logger.Info().
+			Str("erasure_request_id", request.ID.String()).
+			Str("user_id", request.UserID.String()).
+			Msg("user erased")
+	}
+
+	return nil
+}
+
+// StartErasureWorker runs ExecuteDueErasuresService every check interval.
+// Every erasure is a single transaction, so shutting down between two runs
+// loses nothing. A non-positive interval disables the worker.
+func StartErasureWorker(app *app.Application) {
+	utils.RunPeriodically(app.Logger, "erasure", app.Config.Erasure.CheckInterval, func() error {
+		return ExecuteDueErasuresService(app)
+	})
+}
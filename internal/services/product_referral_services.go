@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+// GetOrCreateProductReferralService returns the user's share code for the
+// product, generating it on first use.
+func GetOrCreateProductReferralService(
+	app *app.Application, userID, productID uuid.UUID,
+) (*data.ProductReferral, error) {
+	referral, err := app.Repositories.ProductReferrals.GetByUserAndProduct(userID, productID)
+	if err == nil || !errors.Is(err, common.ErrRecordNotFound) {
+		return referral, err
+	}
+
+	return createWithReferralCode(
+		app,
+		func(code string) (*data.ProductReferral, error) {
+			referral := &data.ProductReferral{UserID: userID, ProductID: productID, Code: code}
+			return referral, app.Repositories.ProductReferrals.Create(referral)
+		},
+		func() (*data.ProductReferral, error) {
+			return app.Repositories.ProductReferrals.GetByUserAndProduct(userID, productID)
+		},
+	)
+}
+
+func ListProductReferralsByUserService(app *app.Application, userID uuid.UUID) ([]*data.ProductReferral, error) {
+	return app.Repositories.ProductReferrals.ListByUserID(userID)
+}
+
+// GetProductReferralByCodeService resolves a product share code entered at
+// registration. Unknown codes are reported as ErrReferralCodeInvalid.
+func GetProductReferralByCodeService(app *app.Application, code string) (*data.ProductReferral, error) {
+	referral, err := app.Repositories.ProductReferrals.GetByCode(code)
+	if err != nil {
+		if errors.Is(err, common.ErrRecordNotFound) {
+			return nil, common.ErrReferralCodeInvalid
+		}
+		return nil, err
+	}
+	return referral, nil
+}
+
+func RegisterProductReferralClickService(app *app.Application, code string) (*data.ProductReferral, error) {
+	return app.Repositories.ProductReferrals.RegisterClick(code)
+}
+
+func ProductReferralShareURL(app *app.Application, referral *data.ProductReferral) string {
+	return app.Config.Referral.ProductShareBaseURL + "/" + referral.ProductID.String() +
+		"?" + url.Values{"pref": {referral.Code}}.Encode()
+}
+
+// RecordPaidOrderService is the hook to call once an order is paid. It
+// returns how many of the order's lines were credited to a product
+// referrer.
+func RecordPaidOrderService(
+	app *app.Application, orderID, buyerID uuid.UUID, lines []data.PaidOrderLine,
+) (int, error) {
+	referrers, err := app.Repositories.ProductReferrals.RecordPaidOrder(
+		orderID, buyerID, lines, app.Config.Bonus.PointsPerProductPurchase,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(referrers), applyDiscountRules(app, referrers)
+}
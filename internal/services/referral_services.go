@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/kcharymyrat/e-commerce/internal/utils"
+)
+
+const maxReferralCodeAttempts = 5
+
+// GetOrCreateReferralService returns the user's referral code, generating
+// one the first time it is asked for.
+func GetOrCreateReferralService(app *app.Application, userID uuid.UUID) (*data.UserReferral, error) {
+	referral, err := app.Repositories.Referrals.GetByUserID(userID)
+	if err == nil || !errors.Is(err, common.ErrRecordNotFound) {
+		return referral, err
+	}
+
+	return createWithReferralCode(
+		app,
+		func(code string) (*data.UserReferral, error) {
+			referral := &data.UserReferral{UserID: userID, Code: code}
+			return referral, app.Repositories.Referrals.Create(referral)
+		},
+		func() (*data.UserReferral, error) {
+			return app.Repositories.Referrals.GetByUserID(userID)
+		},
+	)
+}
+
+// createWithReferralCode calls create with freshly generated codes until
+// one is accepted. A unique violation means either the code collided or a
+// concurrent request created the row first; lookup tells the two apart.
+func createWithReferralCode[T any](
+	app *app.Application,
+	create func(code string) (T, error),
+	lookup func() (T, error),
+) (T, error) {
+	var zero T
+
+	for i := 0; i < maxReferralCodeAttempts; i++ {
+		code, err := utils.GenerateReferralCode(app.Config.Referral.CodeLength)
+		if err != nil {
+			return zero, err
+		}
+
+		created, err := create(code)
+		if err == nil {
+			return created, nil
+		}
+
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != constants.UniqueViolation {
+			return zero, err
+		}
+
+		existing, err := lookup()
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, common.ErrRecordNotFound) {
+			return zero, err
+		}
+	}
+
+	return zero, errors.New("could not generate a unique referral code")
+}
+
+// GetReferralByCodeService resolves a code entered at registration. Unknown
+// codes are reported as ErrReferralCodeInvalid.
+func GetReferralByCodeService(app *app.Application, code string) (*data.UserReferral, error) {
+	referral, err := app.Repositories.Referrals.GetByCode(code)
+	if err != nil {
+		if errors.Is(err, common.ErrRecordNotFound) {
+			return nil, common.ErrReferralCodeInvalid
+		}
+		return nil, err
+	}
+	return referral, nil
+}
+
+func ReferralShareURL(app *app.Application, code string) string {
+	return app.Config.Referral.ShareBaseURL + "?" + url.Values{"ref": {code}}.Encode()
+}
+
+func ListReferralLeaderboardService(
+	app *app.Application,
+	f *requests.ReferralLeaderboardFilters,
+) ([]*data.ReferralLeaderboardEntry, types.PaginationMetadata, error) {
+	return app.Repositories.Referrals.Leaderboard(f)
+}
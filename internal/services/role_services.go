@@ -0,0 +1,74 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+func CreateRoleService(app *app.Application, role *data.Role) error {
+	return app.Repositories.Roles.Create(role)
+}
+
+func GetRoleByIDService(app *app.Application, id uuid.UUID) (*data.Role, error) {
+	return app.Repositories.Roles.GetByID(id)
+}
+
+func ListRolesService(app *app.Application) ([]*data.Role, error) {
+	return app.Repositories.Roles.List()
+}
+
+// UpdateRoleService replaces the role's description and permission set.
+// System roles keep their name because the code resolves them by it.
+func UpdateRoleService(
+	app *app.Application,
+	input *requests.RoleAdminUpdate,
+	role *data.Role,
+	updatedByID uuid.UUID,
+) error {
+	if role.IsSystem && input.Name != role.Name {
+		return common.ErrSystemRole
+	}
+
+	role.Name = input.Name
+	role.Description = input.Description
+	role.Permissions = input.Permissions
+	role.UpdatedByID = &updatedByID
+
+	return app.Repositories.Roles.Update(role)
+}
+
+func DeleteRoleService(app *app.Application, id uuid.UUID) error {
+	role, err := app.Repositories.Roles.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if role.IsSystem {
+		return common.ErrSystemRole
+	}
+
+	return app.Repositories.Roles.Delete(id)
+}
+
+func ListPermissionsService(app *app.Application) ([]*data.Permission, error) {
+	return app.Repositories.Roles.ListPermissions()
+}
+
+func GetUserPermissionsService(app *app.Application, userID uuid.UUID) ([]string, error) {
+	return app.Repositories.Roles.GetUserPermissions(userID)
+}
+
+func ListUserRolesService(app *app.Application, userID uuid.UUID) ([]*data.Role, error) {
+	return app.Repositories.Roles.ListUserRoles(userID)
+}
+
+func AssignRoleToUserService(app *app.Application, userID, roleID, createdByID uuid.UUID) error {
+	return app.Repositories.Roles.AssignToUser(userID, roleID, &createdByID)
+}
+
+func RemoveRoleFromUserService(app *app.Application, userID, roleID uuid.UUID) error {
+	return app.Repositories.Roles.RemoveFromUser(userID, roleID)
+}
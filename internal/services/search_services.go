@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kcharymyrat/e-commerce/api/requests"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/types"
+	"github.com/kcharymyrat/e-commerce/internal/utils"
+)
+
+const (
+	// DefaultSuggestLimit is the number of suggestions returned when the
+	// request does not ask for a number.
+	DefaultSuggestLimit = 10
+
+	// Only clicks count towards popularity: results merely showing up in a
+	// search would let anyone inflate them by repeating the search.
+	searchClickWeight = 1.0
+)
+
+func SearchCatalogService(
+	app *app.Application,
+	f *requests.CatalogSearchFilters,
+	langCode string,
+) ([]*data.SearchHit, types.PaginationMetadata, error) {
+	return app.Repositories.Search.Catalog(f, langCode)
+}
+
+func SuggestService(
+	app *app.Application,
+	f *requests.SearchSuggestFilters,
+	langCode string,
+) ([]*data.Suggestion, error) {
+	limit := DefaultSuggestLimit
+	if f.Limit != nil {
+		limit = *f.Limit
+	}
+	return app.Repositories.Suggestions.Prefix(langCode, f.Query, limit)
+}
+
+// RegisterSearchClickService counts a click on a search result. Only
+// entities the search can show are counted, so that clicks cannot fill the
+// popularity set with made up ids.
+func RegisterSearchClickService(app *app.Application, input *requests.SearchClickReq) error {
+	ref := data.CatalogRef{Kind: input.Kind, ID: input.ID}
+
+	exists, err := app.Repositories.Search.Exists(ref)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return common.ErrRecordNotFound
+	}
+
+	return app.Repositories.Suggestions.AddPopularity([]data.CatalogRef{ref}, searchClickWeight)
+}
+
+// RebuildSuggestionsService rebuilds the suggestion index of every language
+// from the catalog and removes the indexes of languages that have nothing
+// to suggest anymore, such as deleted ones. The popularity of entities that
+// were deleted or deactivated is dropped as well.
+func RebuildSuggestionsService(app *app.Application) error {
+	suggestions, err := app.Repositories.Search.Suggestions()
+	if err != nil {
+		return err
+	}
+
+	langCodes := make([]string, 0, len(suggestions))
+	refs := []data.CatalogRef{}
+	for langCode, langSuggestions := range suggestions {
+		err = app.Repositories.Suggestions.Replace(langCode, langSuggestions)
+		if err != nil {
+			return err
+		}
+		langCodes = append(langCodes, langCode)
+		for _, s := range langSuggestions {
+			refs = append(refs, s.CatalogRef)
+		}
+	}
+
+	err = app.Repositories.Suggestions.RemoveExcept(langCodes)
+	if err != nil {
+		return err
+	}
+
+	return app.Repositories.Suggestions.PrunePopularity(refs)
+}
+
+// suggestionsRebuild coalesces rebuild requests: while a rebuild runs,
+// any number of catalog changes cause exactly one more.
+var suggestionsRebuild struct {
+	sync.Mutex
+	running bool
+	pending bool
+}
+
+// RebuildSuggestionsInBackground is called after every catalog change.
+func RebuildSuggestionsInBackground(app *app.Application) {
+	suggestionsRebuild.Lock()
+	defer suggestionsRebuild.Unlock()
+
+	if suggestionsRebuild.running {
+		suggestionsRebuild.pending = true
+		return
+	}
+	suggestionsRebuild.running = true
+
+	utils.BackgroundGoroutine(app.Logger, app.Wg, func() {
+		for {
+			rebuildSuggestions(app)
+
+			suggestionsRebuild.Lock()
+			if !suggestionsRebuild.pending {
+				suggestionsRebuild.running = false
+				suggestionsRebuild.Unlock()
+				return
+			}
+			suggestionsRebuild.pending = false
+			suggestionsRebuild.Unlock()
+		}
+	})
+}
+
+// StartSuggestionsRebuilder builds the suggestion index at startup and then
+// every rebuild interval, which picks up catalog changes made outside the
+// API. A non-positive interval disables the periodic rebuild.
+func StartSuggestionsRebuilder(app *app.Application) {
+	RebuildSuggestionsInBackground(app)
+
+	utils.RunPeriodically(app.Logger, "search suggestions rebuild", app.Config.Search.SuggestRebuildInterval, func() error {
+		RebuildSuggestionsInBackground(app)
+		return nil
+	})
+}
+
+func rebuildSuggestions(app *app.Application) {
+	defer func() {
+		if err := recover(); err != nil {
+			app.Logger.Err(fmt.Errorf("%v", err)).Msg("panic")
+		}
+	}()
+
+	err := RebuildSuggestionsService(app)
+	if err != nil {
+		app.Logger.Error().Err(err).Msg("failed to rebuild search suggestions")
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/data"
 )
 
@@ -25,3 +26,24 @@ func RevokeSessionByIDService(app *app.Application, id uuid.UUID) error {
 func DeleteSessionByIDService(app *app.Application, id uuid.UUID) error {
 	return app.Repositories.Sessions.DeleteByID(id)
 }
+
+func RevokeAllUserSessionsService(app *app.Application, phone string) error {
+	return app.Repositories.Sessions.RevokeAllByUserPhone(phone)
+}
+
+// SyncUserClaimsService replaces the account flags of claims with the
+// current ones of the user. Access tokens outlive bans, demotions and
+// deletions, so the flags they were issued with are never trusted.
+func SyncUserClaimsService(app *app.Application, claims *auth.UserClaims) error {
+	user, err := app.Repositories.Users.GetByID(claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	claims.IsActive = user.IsActive
+	claims.IsBanned = user.IsBanned
+	claims.IsStaff = user.IsStaff
+	claims.IsAdmin = user.IsAdmin
+	claims.IsSuperuser = user.IsSuperuser
+	return nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+const recoveryCodesCount = 10
+
+// IsMFARequired reports whether policy forces the user to use a second factor.
+func IsMFARequired(user *data.User) bool {
+	return user.IsAdmin || user.IsSuperuser
+}
+
+// MFAStatusService reports whether the user has to pass a second factor and,
+// if so, whether they first have to enroll one.
+func MFAStatusService(app *app.Application, user *data.User) (required bool, enroll bool, err error) {
+	totp, err := app.Repositories.TOTPs.GetByUserID(user.ID)
+	if err != nil && !errors.Is(err, common.ErrRecordNotFound) {
+		return false, false, err
+	}
+
+	if totp != nil && totp.IsEnabled {
+		return true, false, nil
+	}
+
+	if IsMFARequired(user) {
+		return true, true, nil
+	}
+
+	return false, false, nil
+}
+
+// StartTOTPEnrollmentService creates a pending secret for the user and
+// returns it together with the provisioning uri for the QR code.
+func StartTOTPEnrollmentService(app *app.Application, user *data.User) (string, string, error) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	encrypted, err := auth.EncryptTOTPSecret(secret, app.Config.SecretKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = app.Repositories.TOTPs.SavePending(user.ID, encrypted)
+	if err != nil {
+		return "", "", err
+	}
+
+	uri := auth.TOTPProvisioningURI(app.Config.MFA.Issuer, user.Phone, secret)
+
+	return secret, uri, nil
+}
+
+// ConfirmTOTPEnrollmentService enables the pending secret once the user
+// proved possession with a valid code, and returns fresh recovery codes.
+func ConfirmTOTPEnrollmentService(app *app.Application, user *data.User, code string) ([]string, error) {
+	totp, err := app.Repositories.TOTPs.GetByUserID(user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return nil, common.ErrTOTPNotEnabled
+		default:
+			return nil, err
+		}
+	}
+
+	if totp.IsEnabled {
+		return nil, common.ErrTOTPAlreadyEnabled
+	}
+
+	err = verifyTOTPCode(app, totp, code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = app.Repositories.TOTPs.Enable(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return RegenerateRecoveryCodesService(app, user.ID)
+}
+
+func RegenerateRecoveryCodesService(app *app.Application, userID uuid.UUID) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, auth.HashRecoveryCode(code, app.Config.SecretKey))
+	}
+
+	err = app.Repositories.TOTPs.ReplaceRecoveryCodes(userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// VerifySecondFactorService accepts either a current totp code or one of the
+// unused recovery codes (formatted xxxxx-xxxxx).
+func VerifySecondFactorService(app *app.Application, user *data.User, code string) error {
+	totp, err := app.Repositories.TOTPs.GetByUserID(user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrTOTPNotEnabled
+		default:
+			return err
+		}
+	}
+
+	if !totp.IsEnabled {
+		return common.ErrTOTPNotEnabled
+	}
+
+	if strings.Contains(code, "-") {
+		return app.Repositories.TOTPs.UseRecoveryCode(user.ID, auth.HashRecoveryCode(code, app.Config.SecretKey))
+	}
+
+	return verifyTOTPCode(app, totp, code)
+}
+
+func DisableTOTPService(app *app.Application, user *data.User, code string) error {
+	err := VerifySecondFactorService(app, user, code)
+	if err != nil {
+		return err
+	}
+
+	return app.Repositories.TOTPs.Delete(user.ID)
+}
+
+func verifyTOTPCode(app *app.Application, totp *data.UserTOTP, code string) error {
+	secret, err := auth.DecryptTOTPSecret(totp.SecretEncrypted, app.Config.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	step, ok := auth.ValidateTOTPCode(secret, code, time.Now())
+	if !ok {
+		return common.ErrTOTPInvalid
+	}
+
+	return app.Repositories.TOTPs.UseStep(totp.UserID, step)
+}
+
+// CreateMFAChallengeService starts the second step of a login and returns
+// the token the client has to present with the code.
+func CreateMFAChallengeService(app *app.Application, user *data.User, enroll bool) (string, time.Time, error) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	challenge := &data.MFAChallenge{
+		UserID: user.ID,
+		Enroll: enroll,
+	}
+
+	ttl := app.Config.MFA.ChallengeTTL
+	err = app.Repositories.MFAChallenges.Save(auth.HashOpaqueToken(token), challenge, ttl)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, time.Now().Add(ttl), nil
+}
+
+func GetMFAChallengeService(app *app.Application, token string) (*data.MFAChallenge, error) {
+	challenge, err := app.Repositories.MFAChallenges.Get(auth.HashOpaqueToken(token))
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return nil, common.ErrMFAChallengeInvalid
+		default:
+			return nil, err
+		}
+	}
+
+	return challenge, nil
+}
+
+// AttemptMFAChallengeService counts an attempt against the challenge and
+// discards it once the limit is exceeded.
+func AttemptMFAChallengeService(app *app.Application, token string) error {
+	tokenHash := auth.HashOpaqueToken(token)
+
+	attempts, err := app.Repositories.MFAChallenges.IncrementAttempts(tokenHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, common.ErrRecordNotFound):
+			return common.ErrMFAChallengeInvalid
+		default:
+			return err
+		}
+	}
+
+	if attempts > app.Config.OTP.MaxAttempts {
+		err = app.Repositories.MFAChallenges.Delete(tokenHash)
+		if err != nil {
+			return err
+		}
+		return common.ErrOTPAttemptsExceeded
+	}
+
+	return nil
+}
+
+func DeleteMFAChallengeService(app *app.Application, token string) error {
+	return app.Repositories.MFAChallenges.Delete(auth.HashOpaqueToken(token))
+}
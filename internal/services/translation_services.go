@@ -7,12 +7,20 @@ import (
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/internal/app"
 	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
 	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
 
-func CreateTranslationService(app *app.Application, tr *data.Translation) error {
-	return app.Repositories.Translations.Create(tr)
+func CreateTranslationService(app *app.Application, tr *data.Translation, entry *data.AuditEntry) error {
+	err := app.Repositories.Translations.Create(tr, auditChange(entry, "translations", &tr.ID, nil, tr))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagTranslations)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func GetTranslationService(app *app.Application, id uuid.UUID) (*data.Translation, error) {
@@ -21,32 +29,50 @@ func GetTranslationService(app *app.Application, id uuid.UUID) (*data.Translatio
 
 func ListTranslationsService(
 	app *app.Application,
-	filters *requests.TranslationsAdminFilters,
+	f *requests.TranslationsAdminFilters,
 ) ([]*data.Translation, types.PaginationMetadata, error) {
-	return app.Repositories.Translations.List(filters)
+	keyset, err := filters.NewKeyset(&f.SortListFilter, &f.CursorFilter, app.Config.SecretKey)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	return app.Repositories.Translations.List(f, keyset)
 }
 
 func UpdateTranslationService(
 	app *app.Application,
 	input *requests.TranslationAdminUpdate,
 	tr *data.Translation,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *tr
+
 	tr.LanguageCode = input.LanguageCode
 	tr.EntityID = input.EntityID
 	tr.TableName = input.TableName
 	tr.FieldName = input.FieldName
 	tr.TranslatedFieldName = input.TranslatedFieldName
 	tr.TranslatedValue = input.TranslatedValue
-	tr.UpdatedByID = input.UpdatedByID
+	tr.UpdatedByID = updatedByID
 
-	return app.Repositories.Translations.Update(tr)
+	err := app.Repositories.Translations.Update(tr, auditChange(entry, "translations", &tr.ID, &before, tr))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagTranslations)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func PartialUpdateTranslationService(
 	app *app.Application,
 	input *requests.TranslationAdminPartialUpdate,
 	tr *data.Translation,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *tr
+
 	if input.LanguageCode != nil {
 		tr.LanguageCode = *input.LanguageCode
 	}
@@ -65,13 +91,25 @@ func PartialUpdateTranslationService(
 	if input.TranslatedValue != nil {
 		tr.TranslatedValue = *input.TranslatedValue
 	}
-	tr.UpdatedByID = input.UpdatedByID
+	tr.UpdatedByID = updatedByID
 
-	return app.Repositories.Translations.Update(tr)
+	err := app.Repositories.Translations.Update(tr, auditChange(entry, "translations", &tr.ID, &before, tr))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagTranslations)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
-func DeleteTranslationService(app *app.Application, id uuid.UUID) error {
-	return app.Repositories.Translations.Delete(id)
+func DeleteTranslationService(app *app.Application, tr *data.Translation, entry *data.AuditEntry) error {
+	err := app.Repositories.Translations.Delete(tr.ID, auditChange(entry, "translations", &tr.ID, tr, nil))
+	if err != nil {
+		return err
+	}
+	invalidateCache(app, constants.CacheTagTranslations)
+	RebuildSuggestionsInBackground(app)
+	return nil
 }
 
 func GetByEntityIDLangCodeFieldName(
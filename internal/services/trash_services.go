@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/utils"
+)
+
+// trashErasureReason is the audit log reason of users erased by the purge.
+const trashErasureReason = "trash retention period passed"
+
+// PurgeTrashService removes the categories and languages that have been in
+// the trash for longer than the retention period. Such users are erased
+// instead, because other tables keep pointing at them.
+func PurgeTrashService(app *app.Application) error {
+	cutoff := time.Now().Add(-app.Config.Trash.Retention)
+
+	categories, err := app.Repositories.Categories.Purge(cutoff)
+	if err != nil {
+		return fmt.Errorf("purge categories: %w", err)
+	}
+
+	languages, err := app.Repositories.Languages.Purge(cutoff)
+	if err != nil {
+		return fmt.Errorf("purge languages: %w", err)
+	}
+
+	users, err := eraseExpiredUsers(app, cutoff)
+	if err != nil {
+		return fmt.Errorf("erase users: %w", err)
+	}
+
+	if categories > 0 || languages > 0 || users > 0 {
+		app.Logger.Info().
+			Int64("categories", categories).
+			Int64("languages", languages).
+			Int64("users", users).
+			Time("cutoff", cutoff).
+			Msg("trash purged")
+	}
+
+	return nil
+}
+
+// eraseExpiredUsers erases up to a batch of users deleted before cutoff,
+// the rest follow on the next runs. Like ExecuteDueErasuresService it erases
+// each user in its own transaction and a failing one is logged and retried
+// on the next run.
+func eraseExpiredUsers(app *app.Application, cutoff time.Time) (int64, error) {
+	ids, err := app.Repositories.Privacy.ListExpiredDeletedUsers(cutoff, erasureBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var erased int64
+	for _, id := range ids {
+		err := app.Repositories.Privacy.EraseDeletedUser(id, cutoff, trashErasureReason)
+		if err != nil {
+			if errors.Is(err, common.ErrRecordNotFound) {
+				continue
+			}
+			app.Logger.Error().Err(err).
+				Str("user_id", id.String()).
+				Msg("failed to erase user")
+			continue
+		}
+		erased++
+	}
+
+	return erased, nil
+}
+
+// StartTrashPurger runs PurgeTrashService every purge interval. Every step
+// of a purge is its own transaction, so shutting down in the middle of one
+// loses nothing. A non-positive retention or interval disables the purge.
+func StartTrashPurger(app *app.Application) {
+	if app.Config.Trash.Retention <= 0 {
+		app.Logger.Info().Msg("trash purge disabled")
+		return
+	}
+
+	utils.RunPeriodically(app.Logger, "trash purge", app.Config.Trash.PurgeInterval, func() error {
+		return PurgeTrashService(app)
+	})
+}
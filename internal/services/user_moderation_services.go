@@ -0,0 +1,178 @@
+package services
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
+	"github.com/kcharymyrat/e-commerce/internal/common"
+	"github.com/kcharymyrat/e-commerce/internal/constants"
+	"github.com/kcharymyrat/e-commerce/internal/data"
+)
+
+// CheckUserManageableService reports whether the actor may change the
+// target user at all. Only superusers may touch admins and superusers,
+// otherwise an admin could take over a superuser account, e.g. by
+// resetting its password.
+func CheckUserManageableService(actor *auth.UserClaims, target *data.User) error {
+	if actor.UserID == target.ID || actor.IsSuperuser {
+		return nil
+	}
+	if target.IsAdmin || target.IsSuperuser {
+		return common.ErrPrivilegeEscalation
+	}
+	return nil
+}
+
+func BanUserService(app *app.Application, actor *auth.UserClaims, id uuid.UUID, entry *data.AuditEntry) (*data.User, error) {
+	return moderateUser(app, actor, id, entry, true, func(user *data.User) error {
+		if user.IsBanned {
+			return common.ErrModerationNoop
+		}
+		user.IsBanned = true
+		user.IsTrusted = false
+		return nil
+	})
+}
+
+func UnbanUserService(app *app.Application, actor *auth.UserClaims, id uuid.UUID, entry *data.AuditEntry) (*data.User, error) {
+	return moderateUser(app, actor, id, entry, false, func(user *data.User) error {
+		if !user.IsBanned {
+			return common.ErrModerationNoop
+		}
+		user.IsBanned = false
+		return nil
+	})
+}
+
+func TrustUserService(app *app.Application, actor *auth.UserClaims, id uuid.UUID, entry *data.AuditEntry) (*data.User, error) {
+	return moderateUser(app, actor, id, entry, false, func(user *data.User) error {
+		if user.IsBanned {
+			return common.ErrUserBanned
+		}
+		if user.IsTrusted {
+			return common.ErrModerationNoop
+		}
+		user.IsTrusted = true
+		return nil
+	})
+}
+
+func UntrustUserService(app *app.Application, actor *auth.UserClaims, id uuid.UUID, entry *data.AuditEntry) (*data.User, error) {
+	return moderateUser(app, actor, id, entry, false, func(user *data.User) error {
+		if !user.IsTrusted {
+			return common.ErrModerationNoop
+		}
+		user.IsTrusted = false
+		return nil
+	})
+}
+
+// PromoteUserService grants the access level. Granting admin or superuser
+// requires a superuser.
+func PromoteUserService(
+	app *app.Application, actor *auth.UserClaims, id uuid.UUID, level string, entry *data.AuditEntry,
+) (*data.User, error) {
+	if level != constants.UserLevelStaff && !actor.IsSuperuser {
+		return nil, common.ErrPrivilegeEscalation
+	}
+
+	return moderateUser(app, actor, id, entry, false, func(user *data.User) error {
+		if user.IsBanned {
+			return common.ErrUserBanned
+		}
+
+		before := user.ModerationState()
+		switch level {
+		case constants.UserLevelStaff:
+			user.IsStaff = true
+		case constants.UserLevelAdmin:
+			user.IsStaff = true
+			user.IsAdmin = true
+		case constants.UserLevelSuperuser:
+			user.IsStaff = true
+			user.IsAdmin = true
+			user.IsSuperuser = true
+		}
+		if user.ModerationState() == before {
+			return common.ErrModerationNoop
+		}
+		return nil
+	})
+}
+
+// DemoteUserService takes the access level and every level above it away.
+// Sessions are revoked because access tokens carry the level flags.
+func DemoteUserService(
+	app *app.Application, actor *auth.UserClaims, id uuid.UUID, level string, entry *data.AuditEntry,
+) (*data.User, error) {
+	if level != constants.UserLevelStaff && !actor.IsSuperuser {
+		return nil, common.ErrPrivilegeEscalation
+	}
+
+	return moderateUser(app, actor, id, entry, true, func(user *data.User) error {
+		before := user.ModerationState()
+		switch level {
+		case constants.UserLevelStaff:
+			user.IsStaff = false
+			user.IsAdmin = false
+			user.IsSuperuser = false
+		case constants.UserLevelAdmin:
+			user.IsAdmin = false
+			user.IsSuperuser = false
+		case constants.UserLevelSuperuser:
+			user.IsSuperuser = false
+		}
+		if user.ModerationState() == before {
+			return common.ErrModerationNoop
+		}
+		return nil
+	})
+}
+
+// moderateUser loads the target, checks the actor may moderate it, applies
+// change and stores the result together with the audit entry.
+func moderateUser(
+	app *app.Application,
+	actor *auth.UserClaims,
+	id uuid.UUID,
+	entry *data.AuditEntry,
+	revokeSessions bool,
+	change func(user *data.User) error,
+) (*data.User, error) {
+	if actor.UserID == id {
+		return nil, common.ErrSelfModeration
+	}
+
+	user, err := app.Repositories.Users.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = CheckUserManageableService(actor, user)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Before, err = json.Marshal(user.ModerationState())
+	if err != nil {
+		return nil, err
+	}
+
+	err = change(user)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.TableName = "users"
+	entry.EntityID = &user.ID
+	user.UpdatedByID = &actor.UserID
+
+	err = app.Repositories.Users.UpdateModerationState(user, entry, revokeSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -4,12 +4,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/kcharymyrat/e-commerce/api/requests"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/auth"
 	"github.com/kcharymyrat/e-commerce/internal/data"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
 	"github.com/kcharymyrat/e-commerce/internal/types"
 )
 
-func CreateUserService(app *app.Application, user *data.User) error {
-	return app.Repositories.Users.Create(user)
+func CreateUserService(app *app.Application, user *data.User, entry *data.AuditEntry) error {
+	return app.Repositories.Users.Create(user, auditChange(entry, "users", &user.ID, nil, user))
 }
 
 func GetUserByIDService(app *app.Application, id uuid.UUID) (*data.User, error) {
@@ -21,40 +23,54 @@ func GetUserByPhoneService(app *app.Application, phone string) (*data.User, erro
 }
 
 func ListUsersService(app *app.Application, f *requests.UsersAdminFilters) ([]*data.User, types.PaginationMetadata, error) {
-	return app.Repositories.Users.List(f)
+	keyset, err := filters.NewKeyset(&f.SortListFilter, &f.CursorFilter, app.Config.SecretKey)
+	if err != nil {
+		return nil, types.PaginationMetadata{}, err
+	}
+	return app.Repositories.Users.List(f, keyset)
 }
 
 func UpdateUsersAdminService(
 	app *app.Application,
 	input *requests.UserAdminUpdate,
 	user *data.User,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *user
+
 	user.Phone = input.Phone
-	user.Password = input.Password
 	user.FirstName = input.FirstName
 	user.LastName = input.LastName
 	user.Patronomic = input.Patronomic
 	user.Email = input.Email
-	user.IsActive = input.IsActive
-	user.UpdatedByID = &input.UpdatedByID
+	user.IsActive = *input.IsActive
+	user.UpdatedByID = &updatedByID
 
-	return app.Repositories.Users.Update(user)
+	err := app.Repositories.Users.Update(user, auditChange(entry, "users", &user.ID, &before, user))
+	if err != nil {
+		return err
+	}
+
+	if input.Password != "" {
+		return SetUserPasswordService(app, user, input.Password)
+	}
+
+	return nil
 }
 
 func PartialUpdateUsersAdminService(
 	app *app.Application,
 	input *requests.UserAdminPartialUpdate,
 	user *data.User,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
+	before := *user
+
 	if input.Phone != nil {
 		user.Phone = *input.Phone
 	}
-	if input.Password != nil {
-		user.Password = *input.Password
-	}
-	if input.Password == nil {
-		user.Password = ""
-	}
 	if input.FirstName != nil {
 		user.FirstName = input.FirstName
 	}
@@ -70,42 +86,74 @@ func PartialUpdateUsersAdminService(
 	if input.IsActive != nil {
 		user.IsActive = *input.IsActive
 	}
-	user.UpdatedByID = &input.UpdatedByID
+	user.UpdatedByID = &updatedByID
+
+	err := app.Repositories.Users.Update(user, auditChange(entry, "users", &user.ID, &before, user))
+	if err != nil {
+		return err
+	}
+
+	if input.Password != nil {
+		return SetUserPasswordService(app, user, *input.Password)
+	}
+
+	return nil
+}
+
+func DeleteUserService(app *app.Application, user *data.User, entry *data.AuditEntry) error {
+	return app.Repositories.Users.Delete(user.ID, auditChange(entry, "users", &user.ID, user, nil))
+}
 
-	return app.Repositories.Users.Update(user)
+func ListDeletedUsersService(
+	app *app.Application,
+	f *requests.TrashAdminFilters,
+) ([]*data.User, types.PaginationMetadata, error) {
+	return app.Repositories.Users.ListDeleted(&f.PaginationFilter)
 }
 
-func DeleteUserService(app *app.Application, id uuid.UUID) error {
-	return app.Repositories.Users.Delete(id)
+// RestoreUserService takes the user out of the trash. Like every other
+// change to an admin or superuser it is reserved for superusers.
+func RestoreUserService(
+	app *app.Application,
+	actor *auth.UserClaims,
+	id uuid.UUID,
+	entry *data.AuditEntry,
+) (*data.User, error) {
+	user := &data.User{ID: id, UpdatedByID: &actor.UserID}
+	err := app.Repositories.Users.Restore(user, actor.IsSuperuser, auditChange(entry, "users", &user.ID, nil, user))
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func UpdateUsersSelfService(
 	app *app.Application,
 	input *requests.UserSelfUpdate,
 	user *data.User,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
-	user.Phone = input.Phone
-	user.Password = input.Password
+	before := *user
+
 	user.FirstName = input.FirstName
 	user.LastName = input.LastName
 	user.Patronomic = input.Patronomic
 	user.Email = input.Email
-	user.UpdatedByID = &input.UpdatedByID
+	user.UpdatedByID = &updatedByID
 
-	return app.Repositories.Users.Update(user)
+	return app.Repositories.Users.Update(user, auditChange(entry, "users", &user.ID, &before, user))
 }
 
 func PartialUpdateUsersSelfService(
 	app *app.Application,
 	input *requests.UserSelfPartialUpdate,
 	user *data.User,
+	updatedByID uuid.UUID,
+	entry *data.AuditEntry,
 ) error {
-	if input.Phone != nil {
-		user.Phone = *input.Phone
-	}
-	if input.Password != nil {
-		user.Password = *input.Password
-	}
+	before := *user
+
 	if input.FirstName != nil {
 		user.FirstName = input.FirstName
 	}
@@ -118,7 +166,24 @@ func PartialUpdateUsersSelfService(
 	if input.Email != nil {
 		user.Email = input.Email
 	}
-	user.UpdatedByID = &input.UpdatedByID
+	user.UpdatedByID = &updatedByID
+
+	return app.Repositories.Users.Update(user, auditChange(entry, "users", &user.ID, &before, user))
+}
+
+// ActivateUserService activates the account once its phone is confirmed.
+// Referral signups are only credited here so that unverified registrations
+// never count towards the referrer, and only on the first confirmation.
+func ActivateUserService(app *app.Application, id uuid.UUID) error {
+	err := app.Repositories.Users.Activate(id)
+	if err != nil {
+		return err
+	}
+
+	referrers, err := app.Repositories.Referrals.CountSignup(id, app.Config.Bonus.PointsPerReferralSignup)
+	if err != nil {
+		return err
+	}
 
-	return app.Repositories.Users.Update(user)
+	return applyDiscountRules(app, referrers)
 }
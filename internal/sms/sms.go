@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SMSSender delivers a text message to a phone number in E.164 format.
+type SMSSender interface {
+	Send(ctx context.Context, phone string, message string) error
+}
+
+// LogSMSSender writes outgoing messages to the application log instead of
+// sending them. Intended for local development.
+type LogSMSSender struct {
+	Logger *zerolog.Logger
+}
+
+func NewLogSMSSender(logger *zerolog.Logger) *LogSMSSender {
+	return &LogSMSSender{Logger: logger}
+}
+
+func (s *LogSMSSender) Send(ctx context.Context, phone string, message string) error {
+	s.Logger.Info().
+		Str("phone", phone).
+		Str("sms", message).
+		Msg("sms sent (log driver)")
+	return nil
+}
+
+// FileSMSSender appends outgoing messages to a file so that they can be read
+// back by developers and tests.
+type FileSMSSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileSMSSender(path string) (*FileSMSSender, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSMSSender{Path: path}, nil
+}
+
+func (s *FileSMSSender) Send(ctx context.Context, phone string, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().UTC().Format(time.RFC3339), phone, message)
+	return err
+}
+
+// NewSMSSender returns the sender configured by driver ("log" or "file").
+func NewSMSSender(driver string, outboxPath string, logger *zerolog.Logger) (SMSSender, error) {
+	switch driver {
+	case "file":
+		return NewFileSMSSender(outboxPath)
+	case "log", "":
+		return NewLogSMSSender(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown sms driver %q", driver)
+	}
+}
@@ -12,4 +12,8 @@ type PaginationMetadata struct {
 	FirstPage    int `json:"first_page,omitempty"`
 	LastPage     int `json:"last_page,omitempty"`
 	TotalRecords int `json:"total_records,omitempty"`
+	// NextCursor and PrevCursor are set instead of the page numbers when a
+	// list is paged by cursor.
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
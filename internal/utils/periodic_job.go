@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RunPeriodically runs job every interval in its own goroutine. An error or
+// a panic of one run is logged and the next run still happens. The loop is
+// not tracked by the application wait group, so a job must not leave
+// partial work behind when the process stops between two runs. A
+// non-positive interval disables the job.
+func RunPeriodically(logger *zerolog.Logger, name string, interval time.Duration, job func() error) {
+	if interval <= 0 {
+		logger.Info().Str("job", name).Msg("periodic job disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runJob(logger, name, job)
+		}
+	}()
+}
+
+func runJob(logger *zerolog.Logger, name string, job func() error) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Err(fmt.Errorf("%v", err)).Str("job", name).Msg("panic")
+		}
+	}()
+
+	err := job()
+	if err != nil {
+		logger.Error().Err(err).Str("job", name).Msg("periodic job failed")
+	}
+}
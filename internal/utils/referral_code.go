@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// referralCodeAlphabet leaves out characters that are easy to mistype when a
+// code is read aloud or copied by hand (0/O, 1/I/L).
+const referralCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+func GenerateReferralCode(length int) (string, error) {
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = referralCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
@@ -28,4 +28,32 @@ func RegisterCustomEnTranslations(app *app.Application, trans ut.Translator) {
 		t, _ := ut.T("decimalgtezero", fe.Field())
 		return t
 	})
+
+	app.Validator.RegisterTranslation("password", trans, func(ut ut.Translator) error {
+		return ut.Add("password", "{0} must contain upper and lower case latin letters and a digit", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("password", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("decimalgtzero", trans, func(ut ut.Translator) error {
+		return ut.Add("decimalgtzero", "{0} must be greater than 0.00", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("decimalgtzero", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("decimalnonzero", trans, func(ut ut.Translator) error {
+		return ut.Add("decimalnonzero", "{0} must not be zero", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("decimalnonzero", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("sortkey", trans, func(ut ut.Translator) error {
+		return ut.Add("sortkey", "{0} may only use these sort keys: {1}", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("sortkey", fe.Field(), fe.Param())
+		return t
+	})
 }
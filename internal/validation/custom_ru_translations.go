@@ -27,4 +27,32 @@ func RegisterCustomRuTranslations(app *app.Application, trans ut.Translator) {
 		t, _ := ut.T("decimalgtezero", fe.Field())
 		return t
 	})
+
+	app.Validator.RegisterTranslation("password", trans, func(ut ut.Translator) error {
+		return ut.Add("password", "{0} должен содержать заглавные и строчные латинские буквы и цифру", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("password", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("decimalgtzero", trans, func(ut ut.Translator) error {
+		return ut.Add("decimalgtzero", "{0} должен быть больше 0.00", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("decimalgtzero", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("decimalnonzero", trans, func(ut ut.Translator) error {
+		return ut.Add("decimalnonzero", "{0} не должен быть равен нулю", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("decimalnonzero", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("sortkey", trans, func(ut ut.Translator) error {
+		return ut.Add("sortkey", "{0} может содержать только эти ключи сортировки: {1}", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("sortkey", fe.Field(), fe.Param())
+		return t
+	})
 }
@@ -27,4 +27,32 @@ func RegisterCustomTkTranslations(app *app.Application, trans ut.Translator) {
 		t, _ := ut.T("decimalgtezero", fe.Field())
 		return t
 	})
+
+	app.Validator.RegisterTranslation("password", trans, func(ut ut.Translator) error {
+		return ut.Add("password", "{0} baş we setir latyn harplaryny hem-de sany saklamaly", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("password", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("decimalgtzero", trans, func(ut ut.Translator) error {
+		return ut.Add("decimalgtzero", "{0} 0.00-dan uly bolmaly", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("decimalgtzero", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("decimalnonzero", trans, func(ut ut.Translator) error {
+		return ut.Add("decimalnonzero", "{0} nola deň bolmaly däl", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("decimalnonzero", fe.Field())
+		return t
+	})
+
+	app.Validator.RegisterTranslation("sortkey", trans, func(ut ut.Translator) error {
+		return ut.Add("sortkey", "{0} diňe şu tertipleme açarlaryny ulanyp biler: {1}", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("sortkey", fe.Field(), fe.Param())
+		return t
+	})
 }
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"regexp"
+	"strings"
 	"unicode"
 
 	"github.com/go-playground/locales/en"
@@ -12,6 +13,7 @@ import (
 	en_translations "github.com/go-playground/validator/v10/translations/en"
 	ru_translations "github.com/go-playground/validator/v10/translations/ru"
 	"github.com/kcharymyrat/e-commerce/internal/app"
+	"github.com/kcharymyrat/e-commerce/internal/filters"
 	"github.com/shopspring/decimal"
 )
 
@@ -26,8 +28,12 @@ func NewValidator() *validator.Validate {
 	validate.RegisterValidation("slug", validateSlug)
 	validate.RegisterValidation("decimalpercent", validateDecimalPercent)
 	validate.RegisterValidation("decimalgtezero", validateDecimalGTE)
+	validate.RegisterValidation("decimalgtzero", validateDecimalGT)
+	validate.RegisterValidation("decimalnonzero", validateDecimalNonZero)
 	validate.RegisterValidation("password", validatePlainPassword)
 
+	validate.RegisterStructValidation(validateSortList, filters.SortListFilter{})
+
 	return validate
 }
 
@@ -79,6 +85,18 @@ func validateDecimalGTE(fl validator.FieldLevel) bool {
 	return val.GreaterThanOrEqual(min)
 }
 
+func validateDecimalGT(fl validator.FieldLevel) bool {
+	val := fl.Field().Interface().(decimal.Decimal)
+
+	return val.IsPositive()
+}
+
+func validateDecimalNonZero(fl validator.FieldLevel) bool {
+	val := fl.Field().Interface().(decimal.Decimal)
+
+	return !val.IsZero()
+}
+
 func validatePlainPassword(fl validator.FieldLevel) bool {
 	passwordPlaintext := fl.Field().String()
 
@@ -109,3 +127,12 @@ func validatePlainPassword(fl validator.FieldLevel) bool {
 
 	return isAscii && hasUpper && hasLower && hasNumber
 }
+
+// validateSortList rejects sorts that the resource's sort registry does not
+// accept. The error parameter lists the accepted keys.
+func validateSortList(sl validator.StructLevel) {
+	f := sl.Current().Interface().(filters.SortListFilter)
+	if len(f.Invalid()) > 0 {
+		sl.ReportError(f.Sorts, "Sorts", "sorts", "sortkey", strings.Join(f.SortRegistry.Keys(), ", "))
+	}
+}
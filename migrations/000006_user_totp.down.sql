@@ -0,0 +1,6 @@
+-- user_totp table triggers
+DROP TRIGGER IF EXISTS user_totp_set_timestamps ON user_totp;
+DROP TRIGGER IF EXISTS user_totp_prevent_created_at_update ON user_totp;
+
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
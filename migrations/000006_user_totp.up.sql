@@ -0,0 +1,49 @@
+-- TABLES
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id uuid PRIMARY KEY,
+    secret_encrypted bytea NOT NULL,
+    is_enabled boolean NOT NULL DEFAULT FALSE,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    enabled_at timestamp(0) with time zone,
+
+    CHECK (updated_at >= created_at)
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamp(0) with time zone,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+
+    UNIQUE (user_id, code_hash)
+);
+
+
+-- user_totp fk constraints
+ALTER TABLE user_totp
+ADD CONSTRAINT user_totp_user_id_fk FOREIGN KEY (user_id)
+REFERENCES users(id) ON DELETE CASCADE;
+
+-- user_recovery_codes fk constraints
+ALTER TABLE user_recovery_codes
+ADD CONSTRAINT user_recovery_codes_user_id_fk FOREIGN KEY (user_id)
+REFERENCES users(id) ON DELETE CASCADE;
+
+
+-- user_recovery_codes table indexes
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+
+
+-- user_totp table triggers
+CREATE TRIGGER user_totp_set_timestamps
+BEFORE INSERT OR UPDATE ON user_totp
+FOR EACH ROW
+EXECUTE FUNCTION set_timestamps();
+
+CREATE TRIGGER user_totp_prevent_created_at_update
+BEFORE UPDATE ON user_totp
+FOR EACH ROW
+EXECUTE FUNCTION prevent_created_at_update();
@@ -0,0 +1,8 @@
+-- roles table triggers
+DROP TRIGGER IF EXISTS roles_set_timestamps ON roles;
+DROP TRIGGER IF EXISTS roles_prevent_created_at_update ON roles;
+
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,131 @@
+-- TABLES
+CREATE TABLE IF NOT EXISTS roles (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name varchar(50) NOT NULL UNIQUE,
+    description text,
+    is_system boolean NOT NULL DEFAULT FALSE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    created_by_id uuid,
+    updated_by_id uuid,
+    version integer NOT NULL DEFAULT 1,
+
+    CHECK (updated_at >= created_at)
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code varchar(100) NOT NULL UNIQUE,
+    description text,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id uuid NOT NULL,
+    permission_id uuid NOT NULL,
+
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id uuid NOT NULL,
+    role_id uuid NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    created_by_id uuid,
+
+    PRIMARY KEY (user_id, role_id)
+);
+
+
+-- roles fk constraints
+ALTER TABLE roles
+ADD CONSTRAINT roles_created_by_id_fk FOREIGN KEY (created_by_id)
+REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE roles
+ADD CONSTRAINT roles_updated_by_id_fk FOREIGN KEY (updated_by_id)
+REFERENCES users(id) ON DELETE SET NULL;
+
+-- role_permissions fk constraints
+ALTER TABLE role_permissions
+ADD CONSTRAINT role_permissions_role_id_fk FOREIGN KEY (role_id)
+REFERENCES roles(id) ON DELETE CASCADE;
+
+ALTER TABLE role_permissions
+ADD CONSTRAINT role_permissions_permission_id_fk FOREIGN KEY (permission_id)
+REFERENCES permissions(id) ON DELETE CASCADE;
+
+-- user_roles fk constraints
+ALTER TABLE user_roles
+ADD CONSTRAINT user_roles_user_id_fk FOREIGN KEY (user_id)
+REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE user_roles
+ADD CONSTRAINT user_roles_role_id_fk FOREIGN KEY (role_id)
+REFERENCES roles(id) ON DELETE CASCADE;
+
+ALTER TABLE user_roles
+ADD CONSTRAINT user_roles_created_by_id_fk FOREIGN KEY (created_by_id)
+REFERENCES users(id) ON DELETE SET NULL;
+
+
+-- user_roles table indexes
+CREATE INDEX IF NOT EXISTS idx_user_roles_role_id ON user_roles(role_id);
+
+
+-- roles table triggers
+CREATE TRIGGER roles_set_timestamps
+BEFORE INSERT OR UPDATE ON roles
+FOR EACH ROW
+EXECUTE FUNCTION set_timestamps();
+
+CREATE TRIGGER roles_prevent_created_at_update
+BEFORE UPDATE ON roles
+FOR EACH ROW
+EXECUTE FUNCTION prevent_created_at_update();
+
+
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('category:read', 'View categories in the admin panel'),
+    ('category:write', 'Create, update and delete categories'),
+    ('language:read', 'View languages in the admin panel'),
+    ('language:write', 'Create, update and delete languages'),
+    ('translation:read', 'View translations in the admin panel'),
+    ('translation:write', 'Create, update and delete translations'),
+    ('user:read', 'View users in the admin panel'),
+    ('user:write', 'Create, update and delete users'),
+    ('user:ban', 'Ban and unban users'),
+    ('role:read', 'View roles, permissions and role assignments'),
+    ('role:write', 'Manage roles and assign them to users'),
+    ('lockout:manage', 'View and clear login lockouts')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO roles (name, description, is_system) VALUES
+    ('admin', 'Implicitly held by every user with is_admin', TRUE),
+    ('catalog_manager', 'Implicitly held by every user listed in catalog_managers', TRUE),
+    ('moderator', 'Reviews and bans users', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code <> 'role:write'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code IN (
+    'category:read', 'category:write', 'language:read', 'translation:read', 'translation:write'
+)
+WHERE r.name = 'catalog_manager'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code IN ('user:read', 'user:ban')
+WHERE r.name = 'moderator'
+ON CONFLICT DO NOTHING;
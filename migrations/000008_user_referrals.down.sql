@@ -0,0 +1,10 @@
+DELETE FROM permissions WHERE code = 'referral:read';
+
+-- users table indexes
+DROP INDEX IF EXISTS idx_users_total_referrals;
+DROP INDEX IF EXISTS idx_users_invited_by_id;
+
+ALTER TABLE user_referrals DROP CONSTRAINT IF EXISTS user_referrals_check;
+
+ALTER TABLE user_referrals
+ADD CONSTRAINT user_referrals_check CHECK (updated_at > created_at);
@@ -0,0 +1,25 @@
+-- user_referrals was created with a strict check that no freshly inserted
+-- row can satisfy because set_timestamps() sets both columns to NOW().
+ALTER TABLE user_referrals DROP CONSTRAINT IF EXISTS user_referrals_check;
+
+ALTER TABLE user_referrals
+ADD CONSTRAINT user_referrals_check CHECK (updated_at >= created_at);
+
+
+-- users table indexes
+CREATE INDEX IF NOT EXISTS idx_users_invited_by_id ON users(invited_by_id);
+CREATE INDEX IF NOT EXISTS idx_users_total_referrals ON users(total_referrals DESC)
+WHERE total_referrals > 0;
+
+
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('referral:read', 'View the referral leaderboard')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'referral:read'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+DELETE FROM permissions WHERE code = 'purchase:write';
+
+DROP TABLE IF EXISTS product_purchases;
+
+DROP INDEX IF EXISTS idx_user_prod_refs_code;
+CREATE INDEX IF NOT EXISTS idx_user_prod_refs_code ON user_product_referrals(code);
+
+ALTER TABLE user_product_referrals
+DROP COLUMN IF EXISTS purchases,
+DROP COLUMN IF EXISTS signups,
+DROP COLUMN IF EXISTS clicks;
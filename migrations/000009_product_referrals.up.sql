@@ -0,0 +1,55 @@
+-- TABLES
+ALTER TABLE user_product_referrals
+ADD COLUMN IF NOT EXISTS clicks integer NOT NULL DEFAULT 0 CHECK (clicks >= 0),
+ADD COLUMN IF NOT EXISTS signups integer NOT NULL DEFAULT 0 CHECK (signups >= 0),
+ADD COLUMN IF NOT EXISTS purchases integer NOT NULL DEFAULT 0 CHECK (purchases >= 0);
+
+-- One row per paid order line. order_id has no foreign key yet because
+-- there is no orders table; the primary key makes reporting an order twice
+-- a no-op.
+CREATE TABLE IF NOT EXISTS product_purchases (
+    order_id uuid NOT NULL,
+    product_id uuid NOT NULL,
+    buyer_id uuid NOT NULL,
+    quantity integer NOT NULL CHECK (quantity > 0),
+    referral_id uuid,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (order_id, product_id)
+);
+
+
+-- product_purchases fk constraints
+ALTER TABLE product_purchases
+ADD CONSTRAINT product_purchases_product_id_fk FOREIGN KEY (product_id)
+REFERENCES products(id) ON DELETE RESTRICT;
+
+ALTER TABLE product_purchases
+ADD CONSTRAINT product_purchases_buyer_id_fk FOREIGN KEY (buyer_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE product_purchases
+ADD CONSTRAINT product_purchases_referral_id_fk FOREIGN KEY (referral_id)
+REFERENCES user_product_referrals(id) ON DELETE SET NULL;
+
+
+-- user_product_referrals table indexes
+DROP INDEX IF EXISTS idx_user_prod_refs_code;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_prod_refs_code ON user_product_referrals(code);
+
+-- product_purchases table indexes
+CREATE INDEX IF NOT EXISTS idx_product_purchases_buyer_id ON product_purchases(buyer_id);
+CREATE INDEX IF NOT EXISTS idx_product_purchases_referral_id ON product_purchases(referral_id);
+
+
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('purchase:write', 'Report paid orders for purchase attribution')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'purchase:write'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+DELETE FROM permissions WHERE code IN ('bonus:read', 'bonus:write');
+
+DROP TRIGGER IF EXISTS bonus_ledger_prevent_modification ON bonus_ledger_entries;
+DROP FUNCTION IF EXISTS prevent_ledger_modification();
+
+DROP TABLE IF EXISTS bonus_ledger_entries;
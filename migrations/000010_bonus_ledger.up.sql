@@ -0,0 +1,83 @@
+-- TABLES
+-- Every change to users.bonus_points or users._dynamic_discount_percent is
+-- written here together with the balance it produced. Rows are never
+-- updated or deleted.
+CREATE TABLE IF NOT EXISTS bonus_ledger_entries (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    kind varchar(20) NOT NULL CHECK (kind IN ('bonus_points', 'discount_percent')),
+    delta decimal(10, 2) NOT NULL,
+    balance_after decimal(10, 2) NOT NULL,
+    reason varchar(50) NOT NULL,
+    note text,
+    order_id uuid,
+    referral_id uuid,
+    product_referral_id uuid,
+    actor_id uuid,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+
+-- bonus_ledger_entries fk constraints
+ALTER TABLE bonus_ledger_entries
+ADD CONSTRAINT bonus_ledger_user_id_fk FOREIGN KEY (user_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE bonus_ledger_entries
+ADD CONSTRAINT bonus_ledger_referral_id_fk FOREIGN KEY (referral_id)
+REFERENCES user_referrals(id) ON DELETE RESTRICT;
+
+ALTER TABLE bonus_ledger_entries
+ADD CONSTRAINT bonus_ledger_product_referral_id_fk FOREIGN KEY (product_referral_id)
+REFERENCES user_product_referrals(id) ON DELETE RESTRICT;
+
+ALTER TABLE bonus_ledger_entries
+ADD CONSTRAINT bonus_ledger_actor_id_fk FOREIGN KEY (actor_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+
+-- bonus_ledger_entries table indexes
+CREATE INDEX IF NOT EXISTS idx_bonus_ledger_user_id_created_at
+ON bonus_ledger_entries(user_id, created_at DESC);
+
+-- An order can only redeem points once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bonus_ledger_order_redemption
+ON bonus_ledger_entries(order_id)
+WHERE reason = 'redemption';
+
+
+-- FUNCTIONS
+CREATE OR REPLACE FUNCTION prevent_ledger_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries cannot be modified or deleted';
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- bonus_ledger_entries table triggers
+CREATE TRIGGER bonus_ledger_prevent_modification
+BEFORE UPDATE OR DELETE ON bonus_ledger_entries
+FOR EACH ROW
+EXECUTE FUNCTION prevent_ledger_modification();
+
+
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('bonus:read', 'View bonus point and discount history of users'),
+    ('bonus:write', 'Adjust bonus points and discounts of users')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code IN ('bonus:read', 'bonus:write')
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'bonus:read'
+WHERE r.name = 'moderator'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+DELETE FROM permissions WHERE code = 'country:write';
+
+DROP TRIGGER IF EXISTS customer_addresses_prevent_created_at_update ON customer_addresses;
+DROP TRIGGER IF EXISTS customer_addresses_set_timestamps ON customer_addresses;
+
+DROP TABLE IF EXISTS customer_addresses;
+
+ALTER TABLE countries DROP CONSTRAINT IF EXISTS countries_created_by_id_fk;
+ALTER TABLE countries DROP CONSTRAINT IF EXISTS countries_updated_by_id_fk;
+
+ALTER TABLE countries
+ADD CONSTRAINT countries_created_by_id_fk FOREIGN KEY (created_by_id)
+REFERENCES countries(id) ON DELETE RESTRICT;
+
+ALTER TABLE countries
+ADD CONSTRAINT countries_updated_by_id_fk FOREIGN KEY (updated_by_id)
+REFERENCES countries(id) ON DELETE RESTRICT;
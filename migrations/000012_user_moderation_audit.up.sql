@@ -0,0 +1,59 @@
+-- TABLES
+-- Append-only history of admin actions. before/after hold the changed
+-- columns of the entity as JSON.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id uuid,
+    action varchar(50) NOT NULL,
+    table_name varchar(63) NOT NULL,
+    entity_id uuid,
+    before jsonb,
+    after jsonb,
+    reason text,
+    request_id varchar(100),
+    ip varchar(45),
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+
+-- audit_log fk constraints
+ALTER TABLE audit_log
+ADD CONSTRAINT audit_log_actor_id_fk FOREIGN KEY (actor_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+
+-- audit_log table indexes
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(table_name, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+
+
+-- FUNCTIONS
+CREATE OR REPLACE FUNCTION prevent_audit_log_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log entries cannot be modified or deleted';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Banning used to silently clear is_active and is_trusted, which made an
+-- unban leave the account unusable. Login already rejects banned users, so
+-- is_active is left alone and only the contradictory state is rejected.
+CREATE OR REPLACE FUNCTION enforce_banned_user_constraints()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_banned AND NEW.is_trusted THEN
+        RAISE EXCEPTION 'banned users cannot be trusted'
+        USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- audit_log table triggers
+CREATE TRIGGER audit_log_prevent_modification
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW
+EXECUTE FUNCTION prevent_audit_log_modification();
@@ -0,0 +1,17 @@
+DELETE FROM permissions WHERE code = 'audit:read';
+
+CREATE OR REPLACE FUNCTION prevent_user_deletion() 
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Instead of deleting, mark the user as inactive
+    UPDATE users
+    SET is_active = FALSE
+    WHERE id = OLD.id;
+
+    -- Log that the user was marked as inactive
+    RAISE NOTICE 'User % marked as inactive instead of being deleted', OLD.id;
+
+    -- Prevent the actual deletion
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
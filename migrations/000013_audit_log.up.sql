@@ -0,0 +1,24 @@
+-- FUNCTIONS
+-- Deleting a user used to deactivate it and raise a NOTICE, so the DELETE
+-- reported zero rows and left no trace. Users are deactivated explicitly
+-- now; a DELETE is a bug and fails loudly.
+CREATE OR REPLACE FUNCTION prevent_user_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'users cannot be deleted, deactivate user % instead', OLD.id
+    USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('audit:read', 'View the audit log of admin changes')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'audit:read'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
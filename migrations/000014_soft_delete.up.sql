@@ -0,0 +1,16 @@
+-- TABLES
+-- Soft-deleted rows keep their unique slug, code and phone until they are
+-- purged, so a restore never collides with a newer row.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone;
+ALTER TABLE languages ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone;
+
+
+-- users table indexes
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- categories table indexes
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- languages table indexes
+CREATE INDEX IF NOT EXISTS idx_languages_deleted_at ON languages(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS erasure_requests;
+DROP SEQUENCE IF EXISTS erased_user_phone_seq;
+
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
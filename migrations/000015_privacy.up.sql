@@ -0,0 +1,34 @@
+-- TABLES
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at timestamp(0) with time zone;
+
+-- An erasure runs once execute_after has passed, unless the user cancels it
+-- first. Rows are kept after execution as proof that it happened.
+CREATE TABLE IF NOT EXISTS erasure_requests (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    requested_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    execute_after timestamp(0) with time zone NOT NULL,
+    cancelled_at timestamp(0) with time zone,
+    executed_at timestamp(0) with time zone,
+
+    CHECK (execute_after >= requested_at),
+    CHECK (cancelled_at IS NULL OR executed_at IS NULL)
+);
+
+-- Erased users keep a unique, valid but unroutable phone number:
+-- +999 is not assigned to any country.
+CREATE SEQUENCE IF NOT EXISTS erased_user_phone_seq;
+
+
+-- erasure_requests fk constraints
+ALTER TABLE erasure_requests
+ADD CONSTRAINT erasure_requests_user_id_fk FOREIGN KEY (user_id)
+REFERENCES users(id) ON DELETE RESTRICT;
+
+
+-- erasure_requests table indexes
+-- at most one pending erasure per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_erasure_requests_pending
+ON erasure_requests(user_id) WHERE cancelled_at IS NULL AND executed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_erasure_requests_due
+ON erasure_requests(execute_after) WHERE cancelled_at IS NULL AND executed_at IS NULL;
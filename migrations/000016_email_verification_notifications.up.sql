@@ -0,0 +1,22 @@
+-- TABLES
+-- Cleared whenever the email changes, see UserRepository.Update.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamp(0) with time zone;
+
+-- Users without a row get the defaults below.
+CREATE TABLE IF NOT EXISTS user_notification_preferences (
+    user_id uuid PRIMARY KEY,
+    order_updates boolean NOT NULL DEFAULT TRUE,
+    promotions boolean NOT NULL DEFAULT FALSE,
+    review_replies boolean NOT NULL DEFAULT TRUE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+
+    CHECK (updated_at >= created_at)
+);
+
+
+-- user_notification_preferences fk constraints
+ALTER TABLE user_notification_preferences
+ADD CONSTRAINT user_notification_preferences_user_id_fk FOREIGN KEY (user_id)
+REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_translations_name_trgm;
+DROP INDEX IF EXISTS idx_translations_search_vector;
+DROP INDEX IF EXISTS idx_brands_name_trgm;
+DROP INDEX IF EXISTS idx_brands_search_vector;
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_categories_search_vector;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+ALTER TABLE translations DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE brands DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE categories DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS search_config(text);
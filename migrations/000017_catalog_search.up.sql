@@ -0,0 +1,65 @@
+-- EXTENSIONS
+-- similarity() and the % operator, for matching names with typos
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+
+-- FUNCTIONS
+-- Text search configuration for a language code such as 'ru' or 'ru_RU'.
+-- Turkmen has no stemmer in PostgreSQL and falls back to 'simple'.
+-- IMMUTABLE so that it may be used in generated columns and indexes.
+CREATE OR REPLACE FUNCTION search_config(language_code text)
+RETURNS regconfig AS $$
+    SELECT CASE split_part(lower(language_code), '_', 1)
+        WHEN 'ru' THEN 'russian'::regconfig
+        WHEN 'en' THEN 'english'::regconfig
+        ELSE 'simple'::regconfig
+    END;
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+
+-- TABLES
+-- Base columns hold text in no particular language, so they are indexed
+-- with 'simple'. Translations use the configuration of their language.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', code), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE brands ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A')
+) STORED;
+
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+    setweight(
+        to_tsvector(search_config(language_code), translated_value),
+        CASE WHEN field_name = 'name' THEN 'A' ELSE 'B' END::"char"
+    )
+) STORED;
+
+
+-- products table indexes
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+
+-- categories table indexes
+CREATE INDEX IF NOT EXISTS idx_categories_search_vector ON categories USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops);
+
+-- brands table indexes
+CREATE INDEX IF NOT EXISTS idx_brands_search_vector ON brands USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_brands_name_trgm ON brands USING GIN (name gin_trgm_ops);
+
+-- translations table indexes
+CREATE INDEX IF NOT EXISTS idx_translations_search_vector ON translations USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_translations_name_trgm
+ON translations USING GIN (translated_value gin_trgm_ops) WHERE field_name = 'name';
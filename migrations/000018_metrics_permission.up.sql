@@ -0,0 +1,11 @@
+-- SEED
+INSERT INTO permissions (code, description) VALUES
+    ('metrics:read', 'View runtime metrics such as response cache hits and misses')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.code = 'metrics:read'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+CREATE OR REPLACE FUNCTION prevent_audit_log_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log entries cannot be modified or deleted';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,22 @@
+-- FUNCTIONS
+-- Erasing a user has to remove their personal data from the audit log too,
+-- so the log is append-only with one exception: a transaction that sets
+-- app.audit_redaction to 'on' may rewrite before and after and clear ip.
+-- Every other column stays as it is and rows are never deleted.
+CREATE OR REPLACE FUNCTION prevent_audit_log_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND current_setting('app.audit_redaction', true) = 'on'
+        AND (NEW.id, NEW.actor_id, NEW.action, NEW.table_name, NEW.entity_id,
+            NEW.reason, NEW.request_id, NEW.created_at)
+        IS NOT DISTINCT FROM (OLD.id, OLD.actor_id, OLD.action, OLD.table_name,
+            OLD.entity_id, OLD.reason, OLD.request_id, OLD.created_at)
+        AND (NEW.ip IS NULL OR NEW.ip = OLD.ip)
+    THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'audit log entries cannot be modified or deleted';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,10 @@
+-- TABLES
+-- Set once the phone is confirmed with a registration or phone change OTP.
+-- Registration OTPs are only issued while it is NULL, so an account an
+-- admin deactivated cannot reactivate itself and referral signups are
+-- credited once.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at timestamp(0) with time zone;
+
+-- Accounts that are active today were either confirmed or created by an
+-- admin, so their phone counts as verified.
+UPDATE users SET phone_verified_at = created_at WHERE is_active AND phone_verified_at IS NULL;